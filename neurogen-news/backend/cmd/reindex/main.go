@@ -0,0 +1,149 @@
+// Command reindex rebuilds the Meilisearch indexes from PostgreSQL.
+//
+//	reindex [-index all|articles|users|tags] [-batch 500] [-dry-run]
+//
+// Each index is filled as a shadow copy and swapped in atomically, so search
+// keeps serving the old documents until the new ones are ready. With -dry-run
+// nothing is written; documents missing from or stale in Meilisearch are reported.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/config"
+	"github.com/neurogen-news/backend/internal/repository"
+	"github.com/neurogen-news/backend/internal/search"
+	"github.com/neurogen-news/backend/internal/service"
+	"github.com/neurogen-news/backend/pkg/logger"
+)
+
+// Number of IDs printed per category in dry-run mode
+const maxListedIDs = 20
+
+func main() {
+	index := flag.String("index", "all", "index to rebuild: all, articles, users or tags")
+	batch := flag.Int("batch", 500, "documents per batch")
+	dryRun := flag.Bool("dry-run", false, "only report differences between PostgreSQL and Meilisearch")
+	flag.Parse()
+
+	indexes, err := selectIndexes(*index)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Initialize logger
+	zapLogger, err := logger.New(cfg.LogLevel, cfg.Environment)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer zapLogger.Sync()
+
+	if cfg.MeilisearchURL == "" {
+		zapLogger.Fatal("MEILISEARCH_URL is not configured")
+	}
+
+	// Initialize database
+	db, err := repository.NewPostgresDB(cfg.DatabaseURL)
+	if err != nil {
+		zapLogger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer db.Close()
+
+	searchClient, err := search.NewClient(cfg.MeilisearchURL, cfg.MeilisearchKey, zapLogger)
+	if err != nil {
+		zapLogger.Fatal("Failed to connect to Meilisearch", zap.Error(err))
+	}
+
+	repos := repository.NewRepositories(db)
+	reindexer := service.NewReindexer(repos.Outbox, repos.Article, repos.User, repos.Tag, searchClient, *batch, zapLogger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	failed := false
+	for _, name := range indexes {
+		if *dryRun {
+			diff, err := reindexer.Diff(ctx, name)
+			if err != nil {
+				zapLogger.Error("Diff failed", zap.String("index", name), zap.Error(err))
+				failed = true
+				continue
+			}
+			printDiff(diff)
+			if !diff.InSync() {
+				failed = true
+			}
+			continue
+		}
+
+		start := time.Now()
+		count, err := reindexer.Rebuild(ctx, name)
+		if err != nil {
+			zapLogger.Error("Reindex failed", zap.String("index", name), zap.Int("indexed", count), zap.Error(err))
+			failed = true
+			continue
+		}
+		zapLogger.Info("Reindex completed",
+			zap.String("index", name),
+			zap.Int("documents", count),
+			zap.Duration("took", time.Since(start)),
+		)
+	}
+
+	if failed {
+		zapLogger.Sync()
+		os.Exit(1)
+	}
+}
+
+func selectIndexes(name string) ([]string, error) {
+	if name == "all" {
+		return service.ReindexableIndexes, nil
+	}
+	for _, idx := range service.ReindexableIndexes {
+		if idx == name {
+			return []string{name}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown index %q", name)
+}
+
+func printDiff(d *service.IndexDiff) {
+	fmt.Printf("%s: %d in PostgreSQL, %d in Meilisearch\n", d.Index, d.Expected, d.Actual)
+	if d.InSync() {
+		fmt.Println("  in sync")
+		return
+	}
+	printIDs("missing", d.Missing)
+	printIDs("stale", d.Stale)
+	printIDs("extra", d.Extra)
+}
+
+func printIDs(label string, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	fmt.Printf("  %s: %d\n", label, len(ids))
+	for i, id := range ids {
+		if i == maxListedIDs {
+			fmt.Printf("    ... and %d more\n", len(ids)-maxListedIDs)
+			break
+		}
+		fmt.Printf("    %s\n", id)
+	}
+}
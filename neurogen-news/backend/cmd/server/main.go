@@ -17,7 +17,6 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/etag"
 	"github.com/gofiber/fiber/v2/middleware/filesystem"
 	"github.com/gofiber/fiber/v2/middleware/helmet"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
 	fiberlogger "github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"go.uber.org/zap"
@@ -25,8 +24,11 @@ import (
 	"github.com/neurogen-news/backend/internal/config"
 	"github.com/neurogen-news/backend/internal/handler"
 	appmiddleware "github.com/neurogen-news/backend/internal/middleware"
+	"github.com/neurogen-news/backend/internal/model"
 	"github.com/neurogen-news/backend/internal/repository"
+	"github.com/neurogen-news/backend/internal/search"
 	"github.com/neurogen-news/backend/internal/service"
+	"github.com/neurogen-news/backend/internal/storage"
 	"github.com/neurogen-news/backend/internal/websocket"
 	"github.com/neurogen-news/backend/pkg/logger"
 )
@@ -34,6 +36,9 @@ import (
 //go:embed web/dist/*
 var staticFiles embed.FS
 
+// localUploadsURL is where files of the local storage backend are served
+const localUploadsURL = "/uploads"
+
 func main() {
 	// Load configuration
 	cfg, err := config.Load()
@@ -65,14 +70,72 @@ func main() {
 	// Initialize repositories
 	repos := repository.NewRepositories(db)
 
+	// Initialize Meilisearch (optional, PostgreSQL search is used without it)
+	var searchClient *search.Client
+	if cfg.MeilisearchURL != "" {
+		searchClient, err = search.NewClient(cfg.MeilisearchURL, cfg.MeilisearchKey, zapLogger)
+		if err != nil {
+			zapLogger.Warn("Meilisearch unavailable, falling back to PostgreSQL search", zap.Error(err))
+			searchClient = nil
+		}
+	}
+
+	// Initialize file storage (local disk unless S3 is enabled)
+	var fileStorage storage.Storage
+	var localUploads *storage.LocalStorage
+	if cfg.S3Enabled {
+		fileStorage, err = storage.NewS3Client(storage.S3Config{
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			Endpoint:        cfg.S3Endpoint,
+			PublicEndpoint:  cfg.S3PublicEndpoint,
+			CDNBaseURL:      cfg.S3CDNBaseURL,
+			MaxFileSize:     cfg.MaxUploadSize,
+		}, zapLogger)
+	} else {
+		localUploads, err = storage.NewLocalStorage(cfg.UploadPath, localUploadsURL, zapLogger)
+		fileStorage = localUploads
+	}
+	if err != nil {
+		zapLogger.Fatal("Failed to initialize file storage", zap.Error(err))
+	}
+
+	// Initialize WebSocket hub (services push live updates through it)
+	wsHub := websocket.NewHub(redis, zapLogger)
+	go wsHub.Run(context.Background())
+
 	// Initialize services
 	services := service.NewServices(service.Deps{
 		Repos:     repos,
 		Redis:     redis,
 		JWTSecret: cfg.JWTSecret,
+		Search:    searchClient,
+		Hub:       wsHub,
+		Storage:   fileStorage,
 		Logger:    zapLogger,
+		Premoderation: service.PremoderationPolicy{
+			Enabled:       cfg.PremoderationEnabled,
+			MinKarma:      cfg.PremoderationMinKarma,
+			MinAccountAge: time.Duration(cfg.PremoderationMinAccountAge) * 24 * time.Hour,
+		},
 	})
 
+	// Load platform settings before serving; defaults apply if storage is down
+	if err := services.Settings.Reload(context.Background()); err != nil {
+		zapLogger.Warn("Failed to load platform settings, using defaults", zap.Error(err))
+	}
+
+	// Start background workers
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go services.Outbox.Run(workersCtx)
+	go services.BanExpirer.Run(workersCtx)
+	go services.UploadExpirer.Run(workersCtx)
+	go services.MediaCollector.Run(workersCtx)
+	go services.SettingsWatcher.Run(workersCtx)
+
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
 		AppName:               "Neurogen.News API",
@@ -105,23 +168,8 @@ func main() {
 	}))
 	app.Use(helmet.New())
 
-	// Rate limiting
-	app.Use(limiter.New(limiter.Config{
-		Max:        100,
-		Expiration: time.Minute,
-		KeyGenerator: func(c *fiber.Ctx) string {
-			return c.IP()
-		},
-		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"error": "Too many requests",
-			})
-		},
-	}))
-
-	// Initialize WebSocket hub
-	wsHub := websocket.NewHub(redis, zapLogger)
-	go wsHub.Run(context.Background())
+	// Rate limiting (the limit is a platform setting)
+	app.Use(appmiddleware.RateLimit(services.Settings, redis))
 
 	// Initialize WebSocket handler
 	wsHandler := websocket.NewHandler(wsHub, services.Auth, zapLogger)
@@ -132,6 +180,13 @@ func main() {
 	// Setup routes
 	setupRoutes(app, handlers, services, wsHandler, cfg)
 
+	// Serve local uploads; S3 objects are served by the bucket or CDN
+	if localUploads != nil {
+		app.Static(localUploadsURL, localUploads.Root(), fiber.Static{
+			MaxAge: 365 * 24 * 60 * 60,
+		})
+	}
+
 	// Serve static files (Vue SPA)
 	if cfg.ServeStatic {
 		staticFS, err := fs.Sub(staticFiles, "web/dist")
@@ -184,10 +239,11 @@ func setupRoutes(app *fiber.App, h *handler.Handlers, s *service.Services, ws *w
 
 	// WebSocket
 	app.Use("/ws", websocket.UpgradeCheck())
-	app.Get("/ws", appmiddleware.OptionalAuth(s.Auth), ws.Upgrade())
+	app.Get("/ws", appmiddleware.OptionalAuth(s.Auth), ws.RejectBanned(), ws.Upgrade())
 
 	// API v1
 	api := app.Group("/api/v1")
+	api.Use(appmiddleware.TrackActivity(s.Stats))
 
 	// Auth routes
 	auth := api.Group("/auth")
@@ -203,7 +259,7 @@ func setupRoutes(app *fiber.App, h *handler.Handlers, s *service.Services, ws *w
 	users.Get("/me", appmiddleware.Auth(s.Auth), h.User.GetCurrentProfile)
 	users.Put("/me", appmiddleware.Auth(s.Auth), h.User.UpdateProfile)
 	users.Get("/:username", appmiddleware.OptionalAuth(s.Auth), h.User.GetProfile)
-	users.Get("/:username/articles", h.User.GetArticles)
+	users.Get("/:username/articles", appmiddleware.OptionalAuth(s.Auth), h.User.GetArticles)
 	users.Get("/:username/followers", h.User.GetFollowers)
 	users.Get("/:username/following", h.User.GetFollowing)
 	users.Post("/:username/follow", appmiddleware.Auth(s.Auth), h.User.Follow)
@@ -211,9 +267,9 @@ func setupRoutes(app *fiber.App, h *handler.Handlers, s *service.Services, ws *w
 
 	// Article routes
 	articles := api.Group("/articles")
-	articles.Get("/", h.Article.List)
-	articles.Get("/:id", h.Article.GetByID)
-	articles.Get("/slug/:category/:slug", h.Article.GetBySlug)
+	articles.Get("/", appmiddleware.OptionalAuth(s.Auth), h.Article.List)
+	articles.Get("/:id", appmiddleware.OptionalAuth(s.Auth), h.Article.GetByID)
+	articles.Get("/slug/:category/:slug", appmiddleware.OptionalAuth(s.Auth), h.Article.GetBySlug)
 	articles.Post("/", appmiddleware.Auth(s.Auth), h.Article.Create)
 	articles.Put("/:id", appmiddleware.Auth(s.Auth), h.Article.Update)
 	articles.Delete("/:id", appmiddleware.Auth(s.Auth), h.Article.Delete)
@@ -221,12 +277,18 @@ func setupRoutes(app *fiber.App, h *handler.Handlers, s *service.Services, ws *w
 	articles.Delete("/:id/reactions", appmiddleware.Auth(s.Auth), h.Article.RemoveReaction)
 	articles.Post("/:id/bookmark", appmiddleware.Auth(s.Auth), h.Article.Bookmark)
 	articles.Delete("/:id/bookmark", appmiddleware.Auth(s.Auth), h.Article.RemoveBookmark)
+	articles.Get("/:id/versions", appmiddleware.Auth(s.Auth), h.Article.ListVersions)
+	articles.Get("/:id/versions/diff", appmiddleware.Auth(s.Auth), h.Article.DiffVersions)
+	articles.Get("/:id/versions/:version", appmiddleware.Auth(s.Auth), h.Article.GetVersion)
+	articles.Post("/:id/versions/:version/restore", appmiddleware.Auth(s.Auth), h.Article.RestoreVersion)
 
 	// Comment routes
 	comments := api.Group("/comments")
-	comments.Get("/article/:articleId", h.Comment.GetByArticle)
-	comments.Get("/:id", h.Comment.GetByID)
-	comments.Get("/:id/replies", h.Comment.GetReplies)
+	comments.Get("/article/:articleId", appmiddleware.OptionalAuth(s.Auth), h.Comment.GetByArticle)
+	comments.Get("/article/:articleId/tree", appmiddleware.OptionalAuth(s.Auth), h.Comment.GetTree)
+	comments.Get("/:id", appmiddleware.OptionalAuth(s.Auth), h.Comment.GetByID)
+	comments.Get("/:id/replies", appmiddleware.OptionalAuth(s.Auth), h.Comment.GetReplies)
+	comments.Get("/:id/thread", appmiddleware.OptionalAuth(s.Auth), h.Comment.GetThread)
 	comments.Post("/", appmiddleware.Auth(s.Auth), h.Comment.Create)
 	comments.Put("/:id", appmiddleware.Auth(s.Auth), h.Comment.Update)
 	comments.Delete("/:id", appmiddleware.Auth(s.Auth), h.Comment.Delete)
@@ -302,21 +364,57 @@ func setupRoutes(app *fiber.App, h *handler.Handlers, s *service.Services, ws *w
 	uploads := api.Group("/uploads")
 	uploads.Use(appmiddleware.Auth(s.Auth))
 	uploads.Post("/image", h.Upload.UploadImage)
+	uploads.Post("/presign", h.Upload.PresignUpload)
+	uploads.Post("/:id/complete", h.Upload.CompleteUpload)
 	uploads.Delete("/", h.Upload.DeleteFile)
 
-	// Admin routes
+	// Media library routes
+	media := api.Group("/media")
+	media.Use(appmiddleware.Auth(s.Auth))
+	media.Get("/", h.Media.List)
+	media.Delete("/:id", h.Media.Delete)
+
+	// Report routes
+	reports := api.Group("/reports")
+	reports.Post("/", appmiddleware.Auth(s.Auth), h.Report.Create)
+
+	// Admin routes (each route requires a permission of the caller's role)
 	admin := api.Group("/admin")
 	admin.Use(appmiddleware.Auth(s.Auth))
-	admin.Use(appmiddleware.RequireRole("ADMIN", "EDITOR", "MODERATOR"))
-	admin.Get("/dashboard", h.Admin.GetDashboard)
-	admin.Get("/users", h.Admin.GetUsers)
-	admin.Post("/users/:id/ban", h.Admin.BanUser)
-	admin.Delete("/users/:id/ban", h.Admin.UnbanUser)
-	admin.Get("/reports", h.Admin.GetReports)
-	admin.Put("/reports/:id", h.Admin.ResolveReport)
-	admin.Delete("/articles/:id", h.Admin.DeleteArticle)
-	admin.Delete("/comments/:id", h.Admin.DeleteComment)
-	admin.Get("/settings", h.Admin.GetSettings)
-	admin.Put("/settings", h.Admin.UpdateSettings)
+	admin.Get("/dashboard", appmiddleware.RequirePermission(model.PermDashboardView), h.Admin.GetDashboard)
+	admin.Get("/permissions", appmiddleware.RequirePermission(model.PermUserView), h.Admin.GetPermissionMatrix)
+	admin.Get("/users", appmiddleware.RequirePermission(model.PermUserView), h.Admin.GetUsers)
+	admin.Get("/users/:id/permissions", appmiddleware.RequirePermission(model.PermUserView), h.Admin.GetUserPermissions)
+	admin.Put("/users/:id/role", appmiddleware.RequirePermission(model.PermUserRoles), h.Admin.SetUserRole)
+	admin.Get("/users/:id/quota", appmiddleware.RequirePermission(model.PermUserView), h.Admin.GetUserQuota)
+	admin.Put("/users/:id/quota", appmiddleware.RequirePermission(model.PermUserQuota), h.Admin.SetUserQuota)
+	admin.Post("/users/:id/ban", appmiddleware.RequirePermission(model.PermUserBan), h.Admin.BanUser)
+	admin.Delete("/users/:id/ban", appmiddleware.RequirePermission(model.PermUserBan), h.Admin.UnbanUser)
+	admin.Get("/users/:id/bans", appmiddleware.RequirePermission(model.PermUserView), h.Admin.GetUserBans)
+	admin.Get("/reports", appmiddleware.RequirePermission(model.PermReportManage), h.Admin.GetReports)
+	admin.Get("/reports/:id", appmiddleware.RequirePermission(model.PermReportManage), h.Admin.GetReport)
+	admin.Post("/reports/:id/claim", appmiddleware.RequirePermission(model.PermReportManage), h.Admin.ClaimReport)
+	admin.Put("/reports/:id/assign", appmiddleware.RequirePermission(model.PermReportManage), h.Admin.AssignReport)
+	admin.Put("/reports/:id", appmiddleware.RequirePermission(model.PermReportManage), h.Admin.ResolveReport)
+	admin.Get("/articles/pending", appmiddleware.RequirePermission(model.PermArticleReview), h.Admin.GetReviewQueue)
+	admin.Post("/articles/:id/approve", appmiddleware.RequirePermission(model.PermArticleReview), h.Admin.ApproveArticle)
+	admin.Post("/articles/:id/reject", appmiddleware.RequirePermission(model.PermArticleReview), h.Admin.RejectArticle)
+	admin.Post("/articles/:id/request-changes", appmiddleware.RequirePermission(model.PermArticleReview), h.Admin.RequestArticleChanges)
+	admin.Delete("/articles/:id", appmiddleware.RequirePermission(model.PermArticleDeleteAny), h.Admin.DeleteArticle)
+	admin.Delete("/comments/:id", appmiddleware.RequirePermission(model.PermCommentDeleteAny), h.Admin.DeleteComment)
+	admin.Get("/categories/:id/moderators", appmiddleware.RequirePermission(model.PermCategoryManage), h.Admin.GetCategoryModerators)
+	admin.Post("/categories/:id/moderators", appmiddleware.RequirePermission(model.PermCategoryManage), h.Admin.GrantCategoryModerator)
+	admin.Delete("/categories/:id/moderators/:userId", appmiddleware.RequirePermission(model.PermCategoryManage), h.Admin.RevokeCategoryModerator)
+	admin.Get("/audit-log", appmiddleware.RequirePermission(model.PermAuditRead), h.Admin.GetAuditLog)
+	admin.Get("/audit-log/export", appmiddleware.RequirePermission(model.PermAuditRead), h.Admin.ExportAuditLog)
+	admin.Get("/automod/rules", appmiddleware.RequirePermission(model.PermAutomodManage), h.Admin.GetAutomodRules)
+	admin.Post("/automod/rules", appmiddleware.RequirePermission(model.PermAutomodManage), h.Admin.CreateAutomodRule)
+	admin.Put("/automod/rules/:id", appmiddleware.RequirePermission(model.PermAutomodManage), h.Admin.UpdateAutomodRule)
+	admin.Delete("/automod/rules/:id", appmiddleware.RequirePermission(model.PermAutomodManage), h.Admin.DeleteAutomodRule)
+	admin.Get("/settings", appmiddleware.RequirePermission(model.PermSettingsRead), h.Admin.GetSettings)
+	admin.Put("/settings", appmiddleware.RequirePermission(model.PermSettingsWrite), h.Admin.UpdateSettings)
+	admin.Get("/settings/history", appmiddleware.RequirePermission(model.PermSettingsRead), h.Admin.GetSettingsHistory)
+	admin.Get("/outbox/dead", appmiddleware.RequirePermission(model.PermOutboxManage), h.Admin.GetDeadOutboxEvents)
+	admin.Post("/outbox/:id/requeue", appmiddleware.RequirePermission(model.PermOutboxManage), h.Admin.RequeueOutboxEvent)
 }
 
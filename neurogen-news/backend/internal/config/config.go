@@ -37,7 +37,8 @@ type Config struct {
 	S3Bucket          string `mapstructure:"S3_BUCKET"`
 	S3AccessKeyID     string `mapstructure:"S3_ACCESS_KEY_ID"`
 	S3SecretAccessKey string `mapstructure:"S3_SECRET_ACCESS_KEY"`
-	S3Endpoint        string `mapstructure:"S3_ENDPOINT"` // For S3-compatible (MinIO, DO Spaces)
+	S3Endpoint        string `mapstructure:"S3_ENDPOINT"`        // For S3-compatible (MinIO, DO Spaces)
+	S3PublicEndpoint  string `mapstructure:"S3_PUBLIC_ENDPOINT"` // Endpoint as seen by browsers, if different
 	S3CDNBaseURL      string `mapstructure:"S3_CDN_BASE_URL"`
 
 	// Uploads (local fallback)
@@ -62,6 +63,11 @@ type Config struct {
 	GithubClientSecret string `mapstructure:"GITHUB_CLIENT_SECRET"`
 	GithubRedirectURL  string `mapstructure:"GITHUB_REDIRECT_URL"`
 
+	// Pre-moderation: articles from low-karma or new accounts wait for review
+	PremoderationEnabled       bool `mapstructure:"PREMODERATION_ENABLED"`
+	PremoderationMinKarma      int  `mapstructure:"PREMODERATION_MIN_KARMA"`
+	PremoderationMinAccountAge int  `mapstructure:"PREMODERATION_MIN_ACCOUNT_AGE"` // days
+
 	// Email (for notifications)
 	SMTPHost     string `mapstructure:"SMTP_HOST"`
 	SMTPPort     int    `mapstructure:"SMTP_PORT"`
@@ -99,6 +105,9 @@ func Load() (*Config, error) {
 	viper.SetDefault("MAX_UPLOAD_SIZE", 10*1024*1024) // 10MB
 	viper.SetDefault("LOG_LEVEL", "info")
 	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("PREMODERATION_ENABLED", false)
+	viper.SetDefault("PREMODERATION_MIN_KARMA", 10)
+	viper.SetDefault("PREMODERATION_MIN_ACCOUNT_AGE", 3)
 
 	// Read from environment variables
 	viper.AutomaticEnv()
@@ -1,12 +1,25 @@
 package handler
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/neurogen-news/backend/internal/middleware"
+	"github.com/neurogen-news/backend/internal/model"
 	"github.com/neurogen-news/backend/internal/service"
 )
 
+// auditExportTimeout bounds a streamed audit log export
+const auditExportTimeout = 5 * time.Minute
+
 type AdminHandler struct {
 	services *service.Services
 	logger   *zap.Logger
@@ -19,18 +32,17 @@ func NewAdminHandler(services *service.Services, logger *zap.Logger) *AdminHandl
 	}
 }
 
-// GetDashboard returns admin dashboard stats
+// GetDashboard returns platform totals and daily activity for the last 30 days
 func (h *AdminHandler) GetDashboard(c *fiber.Ctx) error {
-	// TODO: Implement admin dashboard with stats
-	return c.JSON(fiber.Map{
-		"stats": fiber.Map{
-			"totalUsers":     0,
-			"totalArticles":  0,
-			"totalComments":  0,
-			"pendingReports": 0,
-			"activeUsers24h": 0,
-		},
-	})
+	stats, err := h.services.Stats.GetDashboard(c.Context())
+	if err != nil {
+		h.logger.Error("Failed to load dashboard stats", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load dashboard stats",
+		})
+	}
+
+	return c.JSON(stats)
 }
 
 // GetUsers returns paginated list of users for admin
@@ -43,69 +55,916 @@ func (h *AdminHandler) GetUsers(c *fiber.Ctx) error {
 	})
 }
 
-// GetReports returns pending reports
+// GetReports returns the report queue filtered by status, target type, reason and assignee
 func (h *AdminHandler) GetReports(c *fiber.Ctx) error {
-	// TODO: Implement reports listing
-	return c.JSON(fiber.Map{
-		"items":   []interface{}{},
-		"total":   0,
-		"hasMore": false,
-	})
+	params := service.ReportListParams{
+		Status:     c.Query("status", "pending"),
+		TargetType: c.Query("targetType"),
+		Reason:     c.Query("reason"),
+		Unassigned: c.QueryBool("unassigned"),
+		Page:       c.QueryInt("page", 1),
+		PageSize:   c.QueryInt("pageSize", 20),
+	}
+	if params.Status == "all" {
+		params.Status = ""
+	}
+
+	switch assignee := c.Query("assignedTo"); assignee {
+	case "":
+	case "me":
+		userID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+		params.AssignedTo = &userID
+	default:
+		assigneeID, err := uuid.Parse(assignee)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid assignee ID",
+			})
+		}
+		params.AssignedTo = &assigneeID
+	}
+
+	result, err := h.services.Report.List(c.Context(), params)
+	if err != nil {
+		return reportError(c, h.logger, err, "Failed to fetch reports")
+	}
+
+	return c.JSON(result)
+}
+
+// GetReport returns a single report
+func (h *AdminHandler) GetReport(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid report ID",
+		})
+	}
+
+	report, err := h.services.Report.GetByID(c.Context(), id)
+	if err != nil {
+		return reportError(c, h.logger, err, "Failed to fetch report")
+	}
+
+	return c.JSON(report)
+}
+
+// ClaimReport assigns a pending report to the current moderator
+func (h *AdminHandler) ClaimReport(c *fiber.Ctx) error {
+	userID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid report ID",
+		})
+	}
+
+	report, err := h.services.Report.Claim(c.Context(), userID, id)
+	if err != nil {
+		return reportError(c, h.logger, err, "Failed to claim report")
+	}
+
+	return c.JSON(report)
+}
+
+type AssignReportRequest struct {
+	AssigneeID *string `json:"assigneeId"` // null releases the report
+}
+
+// AssignReport hands a pending report to another moderator or releases it
+func (h *AdminHandler) AssignReport(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid report ID",
+		})
+	}
+
+	var req AssignReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	var assigneeID *uuid.UUID
+	if req.AssigneeID != nil {
+		aid, err := uuid.Parse(*req.AssigneeID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid assignee ID",
+			})
+		}
+		assigneeID = &aid
+	}
+
+	report, err := h.services.Report.Assign(c.Context(), id, assigneeID)
+	if err != nil {
+		return reportError(c, h.logger, err, "Failed to assign report")
+	}
+
+	return c.JSON(report)
 }
 
-// ResolveReport resolves a report
+// ResolveReport closes a report, optionally deleting, hiding, warning or banning in the same step
 func (h *AdminHandler) ResolveReport(c *fiber.Ctx) error {
-	// TODO: Implement report resolution
-	return c.JSON(fiber.Map{
-		"message": "Report resolved",
-	})
+	userID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid report ID",
+		})
+	}
+
+	var input service.ResolveReportInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := validate.Struct(input); err != nil {
+		return validationError(c, err)
+	}
+
+	report, err := h.services.Report.Resolve(c.Context(), userID, id, input)
+	if err != nil {
+		return reportError(c, h.logger, err, "Failed to resolve report")
+	}
+
+	return c.JSON(report)
 }
 
-// BanUser bans a user
+// BanUser bans a user temporarily or permanently and ends their sessions
 func (h *AdminHandler) BanUser(c *fiber.Ctx) error {
-	// TODO: Implement user ban
-	return c.JSON(fiber.Map{
-		"message": "User banned",
-	})
+	moderatorID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var input service.BanInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	ban, err := h.services.Ban.Ban(c.Context(), moderatorID, userID, input)
+	if err != nil {
+		return h.banError(c, err, "Failed to ban user")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(ban)
 }
 
-// UnbanUser unbans a user
+type UnbanUserRequest struct {
+	Reason string `json:"reason"`
+}
+
+// UnbanUser lifts the active ban of a user
 func (h *AdminHandler) UnbanUser(c *fiber.Ctx) error {
-	// TODO: Implement user unban
+	moderatorID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	// The reason is optional, so an empty body is fine
+	var req UnbanUserRequest
+	_ = c.BodyParser(&req)
+
+	if err := h.services.Ban.Unban(c.Context(), moderatorID, userID, req.Reason); err != nil {
+		return h.banError(c, err, "Failed to unban user")
+	}
+
 	return c.JSON(fiber.Map{
 		"message": "User unbanned",
 	})
 }
 
-// DeleteArticle deletes an article as admin
+// GetUserBans returns the ban history of a user
+func (h *AdminHandler) GetUserBans(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	result, err := h.services.Ban.GetHistory(c.Context(), userID, c.QueryInt("limit", 20), c.QueryInt("offset", 0))
+	if err != nil {
+		return h.banError(c, err, "Failed to fetch bans")
+	}
+
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) banError(c *fiber.Ctx, err error, message string) error {
+	switch err {
+	case service.ErrInvalidBan:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case service.ErrCannotBanUser:
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case service.ErrUserNotBanned:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err.Error() == "user not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
+// GetReviewQueue returns articles awaiting pre-moderation, oldest first
+func (h *AdminHandler) GetReviewQueue(c *fiber.Ctx) error {
+	result, err := h.services.Review.ListPending(c.Context(), c.QueryInt("page", 1), c.QueryInt("pageSize", 20))
+	if err != nil {
+		h.logger.Error("Failed to fetch review queue", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch review queue",
+		})
+	}
+
+	return c.JSON(result)
+}
+
+type ReviewArticleRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ApproveArticle publishes a pending article
+func (h *AdminHandler) ApproveArticle(c *fiber.Ctx) error {
+	return h.reviewArticle(c, h.services.Review.Approve)
+}
+
+// RejectArticle rejects a pending article; a comment for the author is required
+func (h *AdminHandler) RejectArticle(c *fiber.Ctx) error {
+	return h.reviewArticle(c, h.services.Review.Reject)
+}
+
+// RequestArticleChanges sends a pending article back to its author with a comment
+func (h *AdminHandler) RequestArticleChanges(c *fiber.Ctx) error {
+	return h.reviewArticle(c, h.services.Review.RequestChanges)
+}
+
+func (h *AdminHandler) reviewArticle(c *fiber.Ctx, review func(ctx context.Context, reviewerID, id uuid.UUID, note string) (*model.Article, error)) error {
+	reviewerID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid article ID",
+		})
+	}
+
+	// Approval needs no comment, so an empty body is fine
+	var req ReviewArticleRequest
+	_ = c.BodyParser(&req)
+
+	article, err := review(c.Context(), reviewerID, id, req.Comment)
+	if err != nil {
+		switch err {
+		case service.ErrReviewNoteRequired:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case service.ErrArticleNotPending:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if err.Error() == "article not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Article not found",
+			})
+		}
+		h.logger.Error("Failed to review article", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to review article",
+		})
+	}
+
+	return c.JSON(article)
+}
+
+type ModerationReasonRequest struct {
+	Reason string `json:"reason"`
+}
+
+// DeleteArticle deletes an article as admin and records it in the audit log
 func (h *AdminHandler) DeleteArticle(c *fiber.Ctx) error {
-	// TODO: Implement article deletion by admin
+	moderatorID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid article ID",
+		})
+	}
+
+	// The reason is optional, so an empty body is fine
+	var req ModerationReasonRequest
+	_ = c.BodyParser(&req)
+
+	if err := h.services.Moderation.DeleteArticle(c.Context(), moderatorID, id, req.Reason); err != nil {
+		if err.Error() == "article not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Article not found",
+			})
+		}
+		h.logger.Error("Failed to delete article", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete article",
+		})
+	}
+
 	return c.JSON(fiber.Map{
 		"message": "Article deleted",
 	})
 }
 
-// DeleteComment deletes a comment as admin
+// DeleteComment deletes a comment as admin and records it in the audit log
 func (h *AdminHandler) DeleteComment(c *fiber.Ctx) error {
-	// TODO: Implement comment deletion by admin
+	moderatorID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid comment ID",
+		})
+	}
+
+	var req ModerationReasonRequest
+	_ = c.BodyParser(&req)
+
+	if err := h.services.Moderation.DeleteComment(c.Context(), moderatorID, id, req.Reason); err != nil {
+		if err.Error() == "comment not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Comment not found",
+			})
+		}
+		h.logger.Error("Failed to delete comment", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete comment",
+		})
+	}
+
 	return c.JSON(fiber.Map{
 		"message": "Comment deleted",
 	})
 }
 
-// GetSettings returns admin settings
+// GetAuditLog returns moderator actions filtered by moderator, target and action
+func (h *AdminHandler) GetAuditLog(c *fiber.Ctx) error {
+	params, err := auditLogParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	result, err := h.services.Moderation.GetAuditLog(c.Context(), params)
+	if err != nil {
+		h.logger.Error("Failed to fetch audit log", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch audit log",
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// ExportAuditLog returns the filtered audit log as a CSV file
+func (h *AdminHandler) ExportAuditLog(c *fiber.Ctx) error {
+	params, err := auditLogParams(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	filename := fmt.Sprintf("audit-log-%s.csv", time.Now().UTC().Format("20060102-150405"))
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+
+	// Rows are written as they are read. The request context is gone once the
+	// handler returns, and an error after the first bytes can only cut the
+	// file short.
+	moderation, logger := h.services.Moderation, h.logger
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), auditExportTimeout)
+		defer cancel()
+
+		if _, err := moderation.ExportAuditLog(ctx, params, w); err != nil {
+			logger.Error("Failed to export audit log", zap.Error(err))
+		}
+	})
+	return nil
+}
+
+// auditLogParams reads audit log filters from the query string.
+// Dates are RFC 3339 timestamps or plain YYYY-MM-DD days.
+func auditLogParams(c *fiber.Ctx) (service.AuditLogParams, error) {
+	params := service.AuditLogParams{
+		TargetType: c.Query("targetType"),
+		Action:     c.Query("action"),
+		Page:       c.QueryInt("page", 1),
+		PageSize:   c.QueryInt("pageSize", 50),
+	}
+
+	if v := c.Query("moderatorId"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return params, errors.New("Invalid moderator ID")
+		}
+		params.ModeratorID = &id
+	}
+	if v := c.Query("targetId"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return params, errors.New("Invalid target ID")
+		}
+		params.TargetID = &id
+	}
+	if v := c.Query("from"); v != "" {
+		from, err := parseAuditTime(v, false)
+		if err != nil {
+			return params, errors.New("Invalid from date")
+		}
+		params.From = &from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := parseAuditTime(v, true)
+		if err != nil {
+			return params, errors.New("Invalid to date")
+		}
+		params.To = &to
+	}
+
+	return params, nil
+}
+
+// parseAuditTime accepts a timestamp or a day; a day used as the upper
+// bound includes the whole day
+func parseAuditTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// GetAutomodRules returns all automoderation rules in evaluation order
+func (h *AdminHandler) GetAutomodRules(c *fiber.Ctx) error {
+	rules, err := h.services.Automod.ListRules(c.Context())
+	if err != nil {
+		h.logger.Error("Failed to fetch automod rules", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch automod rules",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"items": rules,
+	})
+}
+
+// CreateAutomodRule adds a rule; it applies to new content right away
+func (h *AdminHandler) CreateAutomodRule(c *fiber.Ctx) error {
+	adminID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	var req service.AutomodRuleInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	rule, err := h.services.Automod.CreateRule(c.Context(), adminID, req)
+	if err != nil {
+		return h.automodError(c, err, "Failed to create automod rule")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(rule)
+}
+
+// UpdateAutomodRule replaces a rule's settings
+func (h *AdminHandler) UpdateAutomodRule(c *fiber.Ctx) error {
+	adminID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid rule ID",
+		})
+	}
+
+	var req service.AutomodRuleInput
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	rule, err := h.services.Automod.UpdateRule(c.Context(), adminID, id, req)
+	if err != nil {
+		return h.automodError(c, err, "Failed to update automod rule")
+	}
+
+	return c.JSON(rule)
+}
+
+// DeleteAutomodRule removes a rule
+func (h *AdminHandler) DeleteAutomodRule(c *fiber.Ctx) error {
+	adminID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid rule ID",
+		})
+	}
+
+	if err := h.services.Automod.DeleteRule(c.Context(), adminID, id); err != nil {
+		return h.automodError(c, err, "Failed to delete automod rule")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Rule deleted",
+	})
+}
+
+func (h *AdminHandler) automodError(c *fiber.Ctx, err error, message string) error {
+	var appErr *service.AppError
+	if errors.As(err, &appErr) {
+		status := fiber.StatusBadRequest
+		if appErr == service.ErrAutomodRuleNotFound {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": appErr.Message,
+			"code":  appErr.Code,
+		})
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
+// GetSettings returns every platform setting with its bounds and current value
 func (h *AdminHandler) GetSettings(c *fiber.Ctx) error {
-	// TODO: Implement admin settings
+	settings, err := h.services.Settings.List(c.Context())
+	if err != nil {
+		h.logger.Error("Failed to load settings", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load settings",
+		})
+	}
+
 	return c.JSON(fiber.Map{
-		"settings": fiber.Map{},
+		"settings": settings,
 	})
 }
 
-// UpdateSettings updates admin settings
+type UpdateSettingsRequest struct {
+	Settings map[string]json.RawMessage `json:"settings"`
+}
+
+// UpdateSettings changes platform settings; all instances pick them up without a restart
 func (h *AdminHandler) UpdateSettings(c *fiber.Ctx) error {
-	// TODO: Implement admin settings update
+	adminID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	var req UpdateSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	settings, err := h.services.Settings.Update(c.Context(), adminID, req.Settings)
+	if err != nil {
+		return h.settingsError(c, err, "Failed to update settings")
+	}
+
+	return c.JSON(fiber.Map{
+		"message":  "Settings updated",
+		"settings": settings,
+	})
+}
+
+// GetSettingsHistory returns setting changes, newest first, optionally for one key
+func (h *AdminHandler) GetSettingsHistory(c *fiber.Ctx) error {
+	result, err := h.services.Settings.History(c.Context(), c.Query("key"), c.QueryInt("page", 1), c.QueryInt("pageSize", 50))
+	if err != nil {
+		return h.settingsError(c, err, "Failed to load settings history")
+	}
+
+	return c.JSON(result)
+}
+
+func (h *AdminHandler) settingsError(c *fiber.Ctx, err error, message string) error {
+	var appErr *service.AppError
+	if errors.As(err, &appErr) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": appErr.Message,
+			"code":  appErr.Code,
+		})
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
+// GetPermissionMatrix returns the permissions of every role and those a category grant gives
+func (h *AdminHandler) GetPermissionMatrix(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"roles":               model.RolePermissions,
+		"categoryPermissions": model.CategoryPermissions,
+	})
+}
+
+// GetUserPermissions returns a user's role, its permissions and category grants
+func (h *AdminHandler) GetUserPermissions(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	permissions, err := h.services.Permission.GetUserPermissions(c.Context(), userID)
+	if err != nil {
+		return h.permissionError(c, err, "Failed to fetch permissions")
+	}
+
+	return c.JSON(permissions)
+}
+
+// SetUserRole promotes or demotes a user and records it in the audit log
+func (h *AdminHandler) SetUserRole(c *fiber.Ctx) error {
+	adminID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var input service.SetRoleInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	user, err := h.services.Permission.SetRole(c.Context(), adminID, userID, input)
+	if err != nil {
+		return h.permissionError(c, err, "Failed to change role")
+	}
+
+	return c.JSON(user)
+}
+
+// GetUserQuota returns a user's upload quota and usage
+func (h *AdminHandler) GetUserQuota(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	status, err := h.services.Quota.GetStatus(c.Context(), userID)
+	if err != nil {
+		return h.quotaError(c, err, "Failed to fetch quota")
+	}
+
+	return c.JSON(status)
+}
+
+// SetUserQuota overrides a user's upload quota; null limits restore the default
+func (h *AdminHandler) SetUserQuota(c *fiber.Ctx) error {
+	adminID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var input service.QuotaOverrideInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	status, err := h.services.Quota.SetOverride(c.Context(), adminID, userID, input)
+	if err != nil {
+		return h.quotaError(c, err, "Failed to change quota")
+	}
+
+	return c.JSON(status)
+}
+
+func (h *AdminHandler) quotaError(c *fiber.Ctx, err error, message string) error {
+	if err == service.ErrInvalidQuota {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err.Error() == "user not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
+// GetCategoryModerators returns the users moderating a category
+func (h *AdminHandler) GetCategoryModerators(c *fiber.Ctx) error {
+	categoryID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+
+	moderators, err := h.services.Permission.ListCategoryModerators(c.Context(), categoryID)
+	if err != nil {
+		return h.permissionError(c, err, "Failed to fetch category moderators")
+	}
+
 	return c.JSON(fiber.Map{
-		"message": "Settings updated",
+		"items": moderators,
 	})
 }
 
+type GrantCategoryRequest struct {
+	UserID string `json:"userId"`
+}
+
+// GrantCategoryModerator lets a user moderate articles and comments of a category
+func (h *AdminHandler) GrantCategoryModerator(c *fiber.Ctx) error {
+	adminID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	categoryID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+
+	var req GrantCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	grant, err := h.services.Permission.GrantCategory(c.Context(), adminID, userID, categoryID)
+	if err != nil {
+		return h.permissionError(c, err, "Failed to grant category")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(grant)
+}
+
+// RevokeCategoryModerator removes a user's moderator grant for a category
+func (h *AdminHandler) RevokeCategoryModerator(c *fiber.Ctx) error {
+	adminID := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+
+	categoryID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid category ID",
+		})
+	}
+	userID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	if err := h.services.Permission.RevokeCategory(c.Context(), adminID, userID, categoryID); err != nil {
+		return h.permissionError(c, err, "Failed to revoke category")
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Category grant revoked",
+	})
+}
+
+func (h *AdminHandler) permissionError(c *fiber.Ctx, err error, message string) error {
+	switch err {
+	case service.ErrInvalidRole:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case service.ErrCannotChangeRole, service.ErrCannotGrantUser:
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case service.ErrCategoryNotGranted:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	switch err.Error() {
+	case "user not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	case "category not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Category not found",
+		})
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
+// GetDeadOutboxEvents lists search index events that ran out of retries
+func (h *AdminHandler) GetDeadOutboxEvents(c *fiber.Ctx) error {
+	result, err := h.services.Outbox.ListDead(c.Context(), c.QueryInt("page", 1), c.QueryInt("pageSize", 20))
+	if err != nil {
+		h.logger.Error("Failed to list dead outbox events", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list dead outbox events",
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// RequeueOutboxEvent retries a dead-lettered event
+func (h *AdminHandler) RequeueOutboxEvent(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid event ID",
+		})
+	}
+
+	if err := h.services.Outbox.Requeue(c.Context(), id); err != nil {
+		if err == service.ErrOutboxEventNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		h.logger.Error("Failed to requeue outbox event", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to requeue outbox event",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Event requeued",
+	})
+}
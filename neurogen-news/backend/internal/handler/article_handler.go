@@ -32,6 +32,7 @@ func (h *ArticleHandler) List(c *fiber.Ctx) error {
 		TimeRange:   c.Query("timeRange", "all"),
 		Page:        c.QueryInt("page", 1),
 		PageSize:    c.QueryInt("pageSize", 20),
+		ViewerID:    viewerID(c),
 	}
 
 	result, err := h.articleService.List(c.Context(), params)
@@ -54,7 +55,7 @@ func (h *ArticleHandler) GetByID(c *fiber.Ctx) error {
 		})
 	}
 
-	article, err := h.articleService.GetByID(c.Context(), id)
+	article, err := h.articleService.GetByID(c.Context(), id, viewerID(c))
 	if err != nil {
 		if err.Error() == "article not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -78,7 +79,7 @@ func (h *ArticleHandler) GetBySlug(c *fiber.Ctx) error {
 	categorySlug := c.Params("category")
 	articleSlug := c.Params("slug")
 
-	article, err := h.articleService.GetBySlug(c.Context(), categorySlug, articleSlug)
+	article, err := h.articleService.GetBySlug(c.Context(), categorySlug, articleSlug, viewerID(c))
 	if err != nil {
 		if err.Error() == "article not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -158,6 +159,16 @@ func (h *ArticleHandler) Create(c *fiber.Ctx) error {
 	})
 
 	if err != nil {
+		if err == service.ErrContentRejected {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if err == service.ErrTooManyTags {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		h.logger.Error("Failed to create article", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create article",
@@ -197,11 +208,26 @@ func (h *ArticleHandler) Update(c *fiber.Ctx) error {
 				"error": "Article not found",
 			})
 		}
-		if err.Error() == "FORBIDDEN" {
+		if err == service.ErrForbidden {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"error": "You don't have permission to edit this article",
 			})
 		}
+		if err == service.ErrArticleRejected {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if err == service.ErrContentRejected {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if err == service.ErrTooManyTags {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		h.logger.Error("Failed to update article", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update article",
@@ -233,7 +259,7 @@ func (h *ArticleHandler) Delete(c *fiber.Ctx) error {
 				"error": "Article not found",
 			})
 		}
-		if err.Error() == "FORBIDDEN" {
+		if err == service.ErrForbidden {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"error": "You don't have permission to delete this article",
 			})
@@ -276,7 +302,18 @@ func (h *ArticleHandler) AddReaction(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.articleService.AddReaction(c.Context(), userID, articleID, req.Emoji); err != nil {
+	reactions, err := h.articleService.AddReaction(c.Context(), userID, articleID, req.Emoji)
+	if err != nil {
+		switch err.Error() {
+		case "article not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Article not found",
+			})
+		case service.ErrInvalidReaction.Message:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		h.logger.Error("Failed to add reaction", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to add reaction",
@@ -284,7 +321,8 @@ func (h *ArticleHandler) AddReaction(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(fiber.Map{
-		"message": "Reaction added",
+		"message":   "Reaction added",
+		"reactions": reactions,
 	})
 }
 
@@ -304,7 +342,8 @@ func (h *ArticleHandler) RemoveReaction(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.articleService.RemoveReaction(c.Context(), userID, articleID); err != nil {
+	reactions, err := h.articleService.RemoveReaction(c.Context(), userID, articleID)
+	if err != nil {
 		h.logger.Error("Failed to remove reaction", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to remove reaction",
@@ -312,7 +351,8 @@ func (h *ArticleHandler) RemoveReaction(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(fiber.Map{
-		"message": "Reaction removed",
+		"message":   "Reaction removed",
+		"reactions": reactions,
 	})
 }
 
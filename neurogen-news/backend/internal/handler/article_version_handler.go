@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/middleware"
+	"github.com/neurogen-news/backend/internal/service"
+)
+
+// ListVersions returns the edit history of an article, newest first
+func (h *ArticleHandler) ListVersions(c *fiber.Ctx) error {
+	userID, ok := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid article ID",
+		})
+	}
+
+	result, err := h.articleService.ListVersions(c.Context(), userID, id, c.QueryInt("page", 1), c.QueryInt("pageSize", 20))
+	if err != nil {
+		return h.versionError(c, err, "Failed to list versions")
+	}
+
+	return c.JSON(result)
+}
+
+// GetVersion returns one version with its content
+func (h *ArticleHandler) GetVersion(c *fiber.Ctx) error {
+	userID, ok := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid article ID",
+		})
+	}
+	version, err := c.ParamsInt("version")
+	if err != nil || version < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid version",
+		})
+	}
+
+	result, err := h.articleService.GetVersion(c.Context(), userID, id, version)
+	if err != nil {
+		return h.versionError(c, err, "Failed to get version")
+	}
+
+	return c.JSON(result)
+}
+
+// DiffVersions compares two versions given by the from and to query
+// parameters, by line (default) or by word
+func (h *ArticleHandler) DiffVersions(c *fiber.Ctx) error {
+	userID, ok := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid article ID",
+		})
+	}
+	from, to := c.QueryInt("from"), c.QueryInt("to")
+	if from < 1 || to < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "from and to must be version numbers",
+		})
+	}
+	mode := service.DiffMode(c.Query("mode", string(service.DiffLines)))
+
+	result, err := h.articleService.DiffVersions(c.Context(), userID, id, from, to, mode)
+	if err != nil {
+		return h.versionError(c, err, "Failed to compare versions")
+	}
+
+	return c.JSON(result)
+}
+
+// RestoreVersion makes the title and content of a version current again
+func (h *ArticleHandler) RestoreVersion(c *fiber.Ctx) error {
+	userID, ok := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid article ID",
+		})
+	}
+	version, err := c.ParamsInt("version")
+	if err != nil || version < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid version",
+		})
+	}
+
+	article, err := h.articleService.RestoreVersion(c.Context(), userID, id, version)
+	if err != nil {
+		return h.versionError(c, err, "Failed to restore version")
+	}
+
+	return c.JSON(article)
+}
+
+func (h *ArticleHandler) versionError(c *fiber.Ctx, err error, message string) error {
+	if err.Error() == "article not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Article not found",
+		})
+	}
+	var appErr *service.AppError
+	if errors.As(err, &appErr) {
+		status := fiber.StatusBadRequest
+		switch appErr {
+		case service.ErrForbidden:
+			status = fiber.StatusForbidden
+		case service.ErrArticleVersionNotFound:
+			status = fiber.StatusNotFound
+		case service.ErrVersionIsCurrent:
+			status = fiber.StatusConflict
+		case service.ErrContentRejected:
+			status = fiber.StatusUnprocessableEntity
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": appErr.Message,
+			"code":  appErr.Code,
+		})
+	}
+	h.logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
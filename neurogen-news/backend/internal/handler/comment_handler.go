@@ -35,6 +35,7 @@ func (h *CommentHandler) GetByArticle(c *fiber.Ctx) error {
 		Sort:     c.Query("sort", "new"),
 		Page:     c.QueryInt("page", 1),
 		PageSize: c.QueryInt("pageSize", 20),
+		ViewerID: viewerID(c),
 	}
 
 	result, err := h.commentService.GetByArticle(c.Context(), articleID, params)
@@ -48,6 +49,68 @@ func (h *CommentHandler) GetByArticle(c *fiber.Ctx) error {
 	return c.JSON(result)
 }
 
+// GetTree returns the comment tree of an article, one page of root comments at a time
+func (h *CommentHandler) GetTree(c *fiber.Ctx) error {
+	articleID, err := uuid.Parse(c.Params("articleId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid article ID",
+		})
+	}
+
+	result, err := h.commentService.GetTree(c.Context(), articleID, viewerID(c), commentTreeParams(c))
+	if err != nil {
+		return h.treeError(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// GetThread loads more replies of one branch of the comment tree
+func (h *CommentHandler) GetThread(c *fiber.Ctx) error {
+	parentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid comment ID",
+		})
+	}
+
+	result, err := h.commentService.GetThread(c.Context(), parentID, viewerID(c), commentTreeParams(c))
+	if err != nil {
+		return h.treeError(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+func commentTreeParams(c *fiber.Ctx) service.CommentTreeParams {
+	return service.CommentTreeParams{
+		Sort:    c.Query("sort", "new"),
+		Cursor:  c.Query("cursor"),
+		Limit:   c.QueryInt("limit", 20),
+		Depth:   c.QueryInt("depth", 3),
+		Replies: c.QueryInt("replies", 3),
+	}
+}
+
+func (h *CommentHandler) treeError(c *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "comment not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Comment not found",
+		})
+	case service.ErrInvalidCursor.Message:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid cursor",
+		})
+	}
+
+	h.logger.Error("Failed to get comment tree", zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": "Failed to fetch comments",
+	})
+}
+
 // GetReplies returns replies for a specific comment
 func (h *CommentHandler) GetReplies(c *fiber.Ctx) error {
 	parentIDStr := c.Params("id")
@@ -61,7 +124,7 @@ func (h *CommentHandler) GetReplies(c *fiber.Ctx) error {
 	limit := c.QueryInt("limit", 10)
 	offset := c.QueryInt("offset", 0)
 
-	replies, err := h.commentService.GetReplies(c.Context(), parentID, limit, offset)
+	replies, err := h.commentService.GetReplies(c.Context(), parentID, viewerID(c), limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to get replies", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -84,7 +147,7 @@ func (h *CommentHandler) GetByID(c *fiber.Ctx) error {
 		})
 	}
 
-	comment, err := h.commentService.GetByID(c.Context(), id)
+	comment, err := h.commentService.GetByID(c.Context(), id, viewerID(c))
 	if err != nil {
 		if err.Error() == "comment not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -103,7 +166,7 @@ func (h *CommentHandler) GetByID(c *fiber.Ctx) error {
 type CreateCommentRequest struct {
 	ArticleID string  `json:"articleId" validate:"required"`
 	ParentID  *string `json:"parentId,omitempty"`
-	Content   string  `json:"content" validate:"required,min=1,max=10000"`
+	Content   string  `json:"content" validate:"required,min=1"`
 }
 
 // Create creates a new comment
@@ -146,6 +209,16 @@ func (h *CommentHandler) Create(c *fiber.Ctx) error {
 		Content:   req.Content,
 	})
 	if err != nil {
+		if err == service.ErrContentRejected {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if err == service.ErrCommentTooLong {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		h.logger.Error("Failed to create comment", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create comment",
@@ -156,7 +229,7 @@ func (h *CommentHandler) Create(c *fiber.Ctx) error {
 }
 
 type UpdateCommentRequest struct {
-	Content string `json:"content" validate:"required,min=1,max=10000"`
+	Content string `json:"content" validate:"required,min=1"`
 }
 
 // Update updates an existing comment
@@ -190,11 +263,16 @@ func (h *CommentHandler) Update(c *fiber.Ctx) error {
 				"error": "Comment not found",
 			})
 		}
-		if err.Error() == "FORBIDDEN" {
+		if err == service.ErrForbidden {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"error": "You don't have permission to edit this comment",
 			})
 		}
+		if err == service.ErrCommentTooLong {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		h.logger.Error("Failed to update comment", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update comment",
@@ -227,7 +305,7 @@ func (h *CommentHandler) Delete(c *fiber.Ctx) error {
 				"error": "Comment not found",
 			})
 		}
-		if err.Error() == "FORBIDDEN" {
+		if err == service.ErrForbidden {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"error": "You don't have permission to delete this comment",
 			})
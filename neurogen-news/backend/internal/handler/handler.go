@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/neurogen-news/backend/internal/middleware"
 	"github.com/neurogen-news/backend/internal/service"
 	"go.uber.org/zap"
 )
@@ -18,13 +21,15 @@ type Handlers struct {
 	Bookmark     *BookmarkHandler
 	Draft        *DraftHandler
 	Upload       *UploadHandler
+	Media        *MediaHandler
+	Report       *ReportHandler
 	Admin        *AdminHandler
 }
 
 func NewHandlers(services *service.Services, logger *zap.Logger) *Handlers {
 	return &Handlers{
 		Auth:         NewAuthHandler(services.Auth, logger),
-		User:         NewUserHandler(services.User, logger),
+		User:         NewUserHandler(services.User, services.Quota, logger),
 		Article:      NewArticleHandler(services.Article, logger),
 		Comment:      NewCommentHandler(services.Comment, logger),
 		Category:     NewCategoryHandler(services.Category, services.Article, logger),
@@ -35,7 +40,18 @@ func NewHandlers(services *service.Services, logger *zap.Logger) *Handlers {
 		Bookmark:     NewBookmarkHandler(services.Bookmark, logger),
 		Draft:        NewDraftHandler(services.Draft, logger),
 		Upload:       NewUploadHandler(services.Upload, logger),
+		Media:        NewMediaHandler(services.Media, logger),
+		Report:       NewReportHandler(services.Report, logger),
 		Admin:        NewAdminHandler(services, logger),
 	}
 }
 
+
+// viewerID returns the authenticated user on optionally authenticated routes
+func viewerID(c *fiber.Ctx) *uuid.UUID {
+	userID, ok := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+	if !ok {
+		return nil
+	}
+	return &userID
+}
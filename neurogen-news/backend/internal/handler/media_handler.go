@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/middleware"
+	"github.com/neurogen-news/backend/internal/service"
+)
+
+type MediaHandler struct {
+	mediaService service.MediaService
+	logger       *zap.Logger
+}
+
+func NewMediaHandler(mediaService service.MediaService, logger *zap.Logger) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+		logger:       logger,
+	}
+}
+
+// List returns the user's media library
+func (h *MediaHandler) List(c *fiber.Ctx) error {
+	userID, ok := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	params := service.MediaListParams{
+		Query:    c.Query("q"),
+		Type:     c.Query("type"),
+		Page:     c.QueryInt("page", 1),
+		PageSize: c.QueryInt("pageSize", 20),
+	}
+
+	result, err := h.mediaService.List(c.Context(), userID, params)
+	if err != nil {
+		h.logger.Error("Failed to get media", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch media",
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// Delete removes an unused media item with all its files
+func (h *MediaHandler) Delete(c *fiber.Ctx) error {
+	userID, ok := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	mediaID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid media ID",
+		})
+	}
+
+	if err := h.mediaService.Delete(c.Context(), userID, mediaID); err != nil {
+		switch err {
+		case service.ErrMediaNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": service.ErrMediaNotFound.Message,
+			})
+		case service.ErrMediaInUse:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": service.ErrMediaInUse.Message,
+				"code":  service.ErrMediaInUse.Code,
+			})
+		}
+		h.logger.Error("Failed to delete media", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete media",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Media deleted",
+	})
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/middleware"
+	"github.com/neurogen-news/backend/internal/service"
+)
+
+type ReportHandler struct {
+	reportService service.ReportService
+	logger        *zap.Logger
+}
+
+func NewReportHandler(reportService service.ReportService, logger *zap.Logger) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+		logger:        logger,
+	}
+}
+
+type CreateReportRequest struct {
+	TargetType  string `json:"targetType"`
+	TargetID    string `json:"targetId"`
+	Reason      string `json:"reason"`
+	Description string `json:"description"`
+}
+
+// Create files a report about an article, comment or user
+func (h *ReportHandler) Create(c *fiber.Ctx) error {
+	userID, ok := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req CreateReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	targetID, err := uuid.Parse(req.TargetID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid target ID",
+		})
+	}
+
+	input := service.CreateReportInput{
+		TargetType:  req.TargetType,
+		TargetID:    targetID,
+		Reason:      req.Reason,
+		Description: req.Description,
+	}
+	if err := validate.Struct(input); err != nil {
+		return validationError(c, err)
+	}
+
+	report, err := h.reportService.Create(c.Context(), userID, input)
+	if err != nil {
+		return reportError(c, h.logger, err, "Failed to create report")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(report)
+}
+
+// reportError maps report service errors for both the public and the admin endpoints
+func reportError(c *fiber.Ctx, logger *zap.Logger, err error, message string) error {
+	var appErr *service.AppError
+	if errors.As(err, &appErr) {
+		status := fiber.StatusBadRequest
+		switch appErr {
+		case service.ErrReportTargetNotFound:
+			status = fiber.StatusNotFound
+		case service.ErrReportRateLimited:
+			status = fiber.StatusTooManyRequests
+		case service.ErrReportExists, service.ErrReportClosed, service.ErrReportClaimed:
+			status = fiber.StatusConflict
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": appErr.Message,
+			"code":  appErr.Code,
+		})
+	}
+
+	if err.Error() == "report not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Report not found",
+		})
+	}
+
+	logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
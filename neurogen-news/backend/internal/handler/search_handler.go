@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 
@@ -29,12 +31,14 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 	}
 
 	params := service.SearchParams{
-		Type:     c.Query("type", "all"),
-		Sort:     c.Query("sort", "relevance"),
-		Level:    c.Query("level"),
-		Category: c.Query("category"),
-		Limit:    c.QueryInt("limit", 20),
-		Offset:   c.QueryInt("offset", 0),
+		Type:        c.Query("type", "all"),
+		Sort:        c.Query("sort", "relevance"),
+		Level:       c.Query("level"),
+		ContentType: c.Query("contentType"),
+		Category:    c.Query("category"),
+		Tags:        splitQueryList(c.Query("tags")),
+		Limit:       c.QueryInt("limit", 20),
+		Offset:      c.QueryInt("offset", 0),
 	}
 
 	result, err := h.searchService.Search(c.Context(), query, params)
@@ -48,6 +52,17 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 	return c.JSON(result)
 }
 
+// splitQueryList parses a comma-separated query parameter
+func splitQueryList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // GetSuggestions returns search suggestions
 func (h *SearchHandler) GetSuggestions(c *fiber.Ctx) error {
 	query := c.Query("q")
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -44,15 +46,94 @@ func (h *UploadHandler) UploadImage(c *fiber.Ctx) error {
 
 	result, err := h.uploadService.UploadImage(c.Context(), userID, file, uploadType)
 	if err != nil {
-		h.logger.Error("Upload failed", zap.Error(err))
+		return h.uploadError(c, err, "Failed to upload file")
+	}
+
+	return c.JSON(result)
+}
+
+// PresignUpload signs a direct upload to object storage
+func (h *UploadHandler) PresignUpload(c *fiber.Ctx) error {
+	userID, ok := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var input service.PresignUploadInput
+	if err := c.BodyParser(&input); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
+			"error": "Invalid request body",
 		})
 	}
+	if input.Type != "article" && input.Type != "avatar" && input.Type != "cover" {
+		input.Type = "article"
+	}
+
+	result, err := h.uploadService.PresignUpload(c.Context(), userID, &input)
+	if err != nil {
+		return h.uploadError(c, err, "Failed to prepare upload")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// CompleteUpload verifies and processes a direct upload
+func (h *UploadHandler) CompleteUpload(c *fiber.Ctx) error {
+	userID, ok := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	uploadID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid upload ID",
+		})
+	}
+
+	result, err := h.uploadService.CompleteUpload(c.Context(), userID, uploadID)
+	if err != nil {
+		return h.uploadError(c, err, "Failed to complete upload")
+	}
 
 	return c.JSON(result)
 }
 
+func (h *UploadHandler) uploadError(c *fiber.Ctx, err error, message string) error {
+	if err == service.ErrUploadNotFound {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": service.ErrUploadNotFound.Message,
+			"code":  service.ErrUploadNotFound.Code,
+		})
+	}
+	var appErr *service.AppError
+	if errors.As(err, &appErr) {
+		status := fiber.StatusBadRequest
+		switch appErr.Code {
+		case service.CodeStorageQuotaExceeded:
+			status = fiber.StatusForbidden
+		case service.CodeDailyUploadLimit:
+			status = fiber.StatusTooManyRequests
+		}
+		body := fiber.Map{
+			"error": appErr.Message,
+			"code":  appErr.Code,
+		}
+		if appErr.Details != nil {
+			body["details"] = appErr.Details
+		}
+		return c.Status(status).JSON(body)
+	}
+	h.logger.Error(message, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": message,
+	})
+}
+
 // DeleteFile deletes an uploaded file
 func (h *UploadHandler) DeleteFile(c *fiber.Ctx) error {
 	userID, ok := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
@@ -72,6 +153,21 @@ func (h *UploadHandler) DeleteFile(c *fiber.Ctx) error {
 	}
 
 	if err := h.uploadService.DeleteFile(c.Context(), userID, req.URL); err != nil {
+		switch err {
+		case service.ErrFileNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case service.ErrForbidden:
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "You can only delete your own files",
+			})
+		case service.ErrMediaInUse:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": service.ErrMediaInUse.Message,
+				"code":  service.ErrMediaInUse.Code,
+			})
+		}
 		h.logger.Error("Delete failed", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to delete file",
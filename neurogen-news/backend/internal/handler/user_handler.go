@@ -10,14 +10,16 @@ import (
 )
 
 type UserHandler struct {
-	userService service.UserService
-	logger      *zap.Logger
+	userService  service.UserService
+	quotaService service.QuotaService
+	logger       *zap.Logger
 }
 
-func NewUserHandler(userService service.UserService, logger *zap.Logger) *UserHandler {
+func NewUserHandler(userService service.UserService, quotaService service.QuotaService, logger *zap.Logger) *UserHandler {
 	return &UserHandler{
-		userService: userService,
-		logger:      logger,
+		userService:  userService,
+		quotaService: quotaService,
+		logger:       logger,
 	}
 }
 
@@ -72,6 +74,12 @@ func (h *UserHandler) GetCurrentProfile(c *fiber.Ctx) error {
 		})
 	}
 
+	// Only the owner sees their upload quota
+	profile.UploadQuota, err = h.quotaService.GetStatus(c.Context(), userID)
+	if err != nil {
+		h.logger.Warn("Failed to get upload quota", zap.Error(err))
+	}
+
 	return c.JSON(profile)
 }
 
@@ -263,7 +271,7 @@ func (h *UserHandler) GetFollowing(c *fiber.Ctx) error {
 	})
 }
 
-// GetArticles returns a user's articles
+// GetArticles returns a user's articles; the author also sees unpublished ones
 func (h *UserHandler) GetArticles(c *fiber.Ctx) error {
 	username := c.Params("username")
 	user, err := h.userService.GetByUsername(c.Context(), username)
@@ -281,7 +289,10 @@ func (h *UserHandler) GetArticles(c *fiber.Ctx) error {
 	limit := c.QueryInt("limit", 20)
 	offset := c.QueryInt("offset", 0)
 
-	articles, total, err := h.userService.GetUserArticles(c.Context(), user.ID, limit, offset)
+	viewerID, _ := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+	own := viewerID == user.ID
+
+	articles, total, err := h.userService.GetUserArticles(c.Context(), user.ID, own, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to get articles", zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// validate checks service inputs against their `validate` tags
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	// Report fields by the names clients send them under
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, key := range []string{"json", "query"} {
+			name := strings.SplitN(f.Tag.Get(key), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return f.Name
+	})
+	return v
+}
+
+// validationError answers 400 with the rule each invalid field broke
+func validationError(c *fiber.Ctx, err error) error {
+	fields := fiber.Map{}
+	var errs validator.ValidationErrors
+	if errors.As(err, &errs) {
+		for _, e := range errs {
+			fields[e.Field()] = e.Tag()
+		}
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":  "Invalid request",
+		"fields": fields,
+	})
+}
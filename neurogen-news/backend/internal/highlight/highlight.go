@@ -0,0 +1,21 @@
+// Package highlight marks search matches in a way both search backends share.
+// Matches are marked with private-use characters instead of HTML tags, so the
+// indexed text can be escaped before the <mark> tags are added.
+package highlight
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	PreTag  = "\ue000"
+	PostTag = "\ue001"
+)
+
+// ToHTML escapes highlighted text and wraps matches in <mark>
+func ToHTML(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, PreTag, "<mark>")
+	return strings.ReplaceAll(s, PostTag, "</mark>")
+}
@@ -0,0 +1,263 @@
+// Package imaging prepares uploaded images for serving: it removes metadata,
+// applies EXIF orientation and renders resized variants in the source format
+// and, for PNG and GIF sources, WebP where that is smaller. It is pure Go and
+// needs no external tools.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/HugoSmits86/nativewebp"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// Formats as reported by image.Decode
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatGIF  = "gif"
+	FormatWebP = "webp"
+)
+
+// MaxPixels bounds decoded images so a small file cannot expand into gigabytes
+const MaxPixels = 40_000_000
+
+const (
+	originalQuality = 90
+	variantQuality  = 82
+)
+
+// maxWebPPixels bounds the outputs rendered in WebP. The encoder is lossless
+// and slow, so large originals are served in their own format only.
+const maxWebPPixels = 1_300_000
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrImageTooLarge     = errors.New("image dimensions are too large")
+)
+
+// Spec describes a variant. With Height set the image is cropped around the
+// center to the Width:Height aspect ratio first; otherwise it keeps its
+// aspect ratio. Images are never upscaled.
+type Spec struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// Output is one encoded file; the original has an empty Name
+type Output struct {
+	Name        string
+	Format      string
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// Result holds the original and every variant in the fallback format, plus a
+// WebP copy where withWebP allows it and it came out smaller
+type Result struct {
+	Format  string
+	Width   int
+	Height  int
+	Outputs []Output
+}
+
+// Process decodes an image that Validate accepted, info being what it
+// returned, and renders the original and the variants. JPEG and PNG originals are re-encoded to drop metadata; GIF and
+// WebP originals are rewritten without their metadata blocks, so animations
+// survive.
+func Process(data []byte, info *Info, specs []Spec) (*Result, error) {
+	format := info.Format
+
+	img, err := decode(data, format)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatJPEG {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	bounds := img.Bounds()
+	result := &Result{
+		Format: format,
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+	}
+
+	// Variants of GIF and WebP sources fall back to PNG to keep transparency
+	fallback := FormatPNG
+	if format == FormatJPEG {
+		fallback = FormatJPEG
+	}
+
+	original, err := encodeOriginal(data, img, format)
+	if err != nil {
+		return nil, err
+	}
+	result.Outputs = append(result.Outputs, output("", format, img, original))
+	if format != FormatWebP && withWebP(format, img) {
+		webpData, err := encode(img, FormatWebP, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(webpData) < len(original) {
+			result.Outputs = append(result.Outputs, output("", FormatWebP, img, webpData))
+		}
+	}
+
+	for _, spec := range specs {
+		variant := resize(img, spec)
+		if variant == nil {
+			continue
+		}
+		encoded, err := encode(variant, fallback, variantQuality)
+		if err != nil {
+			return nil, err
+		}
+		result.Outputs = append(result.Outputs, output(spec.Name, fallback, variant, encoded))
+		if !withWebP(format, variant) {
+			continue
+		}
+
+		webpData, err := encode(variant, FormatWebP, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(webpData) < len(encoded) {
+			result.Outputs = append(result.Outputs, output(spec.Name, FormatWebP, variant, webpData))
+		}
+	}
+
+	return result, nil
+}
+
+// withWebP reports whether an output of a source in format gets a WebP copy.
+// Lossless WebP never beats JPEG on photos, so JPEG sources get none.
+func withWebP(format string, img image.Image) bool {
+	if format == FormatJPEG {
+		return false
+	}
+	bounds := img.Bounds()
+	return bounds.Dx()*bounds.Dy() <= maxWebPPixels
+}
+
+// ContentType maps a format to its MIME type
+func ContentType(format string) string {
+	return "image/" + format
+}
+
+// Extension maps a format to the file extension it is stored with
+func Extension(format string) string {
+	if format == FormatJPEG {
+		return ".jpg"
+	}
+	return "." + format
+}
+
+func decode(data []byte, format string) (image.Image, error) {
+	r := bytes.NewReader(data)
+	var img image.Image
+	var err error
+	switch format {
+	case FormatJPEG:
+		img, err = jpeg.Decode(r)
+	case FormatPNG:
+		img, err = png.Decode(r)
+	case FormatGIF:
+		// Variants use the first frame
+		img, err = gif.Decode(r)
+	case FormatWebP:
+		img, err = webp.Decode(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	return img, nil
+}
+
+func encodeOriginal(data []byte, img image.Image, format string) ([]byte, error) {
+	switch format {
+	case FormatGIF:
+		return stripGIFMetadata(data)
+	case FormatWebP:
+		return stripWebPMetadata(data)
+	}
+	return encode(img, format, originalQuality)
+}
+
+// encode renders img in format. Quality applies to JPEG; the WebP encoder is
+// lossless, so Process keeps WebP only when it beats the fallback format.
+func encode(img image.Image, format string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatJPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	case FormatPNG:
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	case FormatWebP:
+		err = nativewebp.Encode(&buf, img, nil)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func output(name, format string, img image.Image, data []byte) Output {
+	return Output{
+		Name:        name,
+		Format:      format,
+		ContentType: ContentType(format),
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+		Data:        data,
+	}
+}
+
+// resize renders a variant, or returns nil when the source is too small for it
+func resize(src image.Image, spec Spec) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	crop := bounds
+	width, height := spec.Width, spec.Height
+	if spec.Height > 0 {
+		// Largest centered region with the target aspect ratio
+		cw, ch := w, w*spec.Height/spec.Width
+		if ch > h {
+			cw, ch = h*spec.Width/spec.Height, h
+		}
+		x := bounds.Min.X + (w-cw)/2
+		y := bounds.Min.Y + (h-ch)/2
+		crop = image.Rect(x, y, x+cw, y+ch)
+		if cw < width {
+			return nil
+		}
+	} else {
+		if w <= width {
+			return nil
+		}
+		height = h * width / w
+	}
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
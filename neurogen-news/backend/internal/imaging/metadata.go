@@ -0,0 +1,233 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+)
+
+// jpegOrientation reads the EXIF orientation tag (1-8) of a JPEG; 1 when absent
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		// Metadata segments all come before the image data
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+size]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+// tiffOrientation finds tag 0x0112 in the first IFD of an EXIF TIFF block
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for n := 0; n < count; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8:]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// orient rotates and flips an image so that orientation 1 displays the same.
+// Once metadata is stripped the pixels have to carry the orientation.
+func orient(src image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if orientation >= 5 {
+		w, h = h, w
+	}
+
+	srcRGBA := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(srcRGBA, srcRGBA.Bounds(), src, b.Min, draw.Src)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored
+				dx, dy = b.Dx()-1-x, y
+			case 3: // rotated 180
+				dx, dy = b.Dx()-1-x, b.Dy()-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, b.Dy()-1-y
+			case 5: // mirrored and rotated 270 clockwise
+				dx, dy = y, x
+			case 6: // rotated 90 clockwise
+				dx, dy = b.Dy()-1-y, x
+			case 7: // mirrored and rotated 90 clockwise
+				dx, dy = b.Dy()-1-y, b.Dx()-1-x
+			case 8: // rotated 270 clockwise
+				dx, dy = y, b.Dx()-1-x
+			}
+			dst.SetNRGBA(dx, dy, srcRGBA.NRGBAAt(x, y))
+		}
+	}
+	return dst
+}
+
+// WebP extended-format flags for metadata chunks
+const (
+	webpFlagEXIF = 0x08
+	webpFlagXMP  = 0x04
+)
+
+var errInvalidWebP = errors.New("invalid webp container")
+
+// stripWebPMetadata drops EXIF and XMP chunks from a WebP file and clears
+// their flags, leaving the image data untouched
+func stripWebPMetadata(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errInvalidWebP
+	}
+
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+
+	for i := 12; i < len(data); {
+		if i+8 > len(data) {
+			return nil, errInvalidWebP
+		}
+		fourCC := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size + size%2
+		if size < 0 || end > len(data) {
+			return nil, errInvalidWebP
+		}
+
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte{}, data[i:end]...)
+			if size > 0 {
+				chunk[8] &^= webpFlagEXIF | webpFlagXMP
+			}
+			out = append(out, chunk...)
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
+
+var errInvalidGIF = errors.New("invalid gif stream")
+
+// stripGIFMetadata rewrites a GIF keeping only what affects display: the
+// header, color tables, frames, graphic control extensions and the NETSCAPE
+// loop extension. Comments, XMP and other application extensions are dropped.
+func stripGIFMetadata(data []byte) ([]byte, error) {
+	const headerSize = 13
+	if len(data) < headerSize {
+		return nil, errInvalidGIF
+	}
+	i := headerSize
+	if flags := data[10]; flags&0x80 != 0 {
+		i += 3 << ((flags & 0x07) + 1)
+	}
+	if i > len(data) {
+		return nil, errInvalidGIF
+	}
+
+	out := make([]byte, i, len(data))
+	copy(out, data[:i])
+
+	for i < len(data) {
+		switch data[i] {
+		case 0x21: // extension: label, then sub-blocks
+			if i+2 > len(data) {
+				return nil, errInvalidGIF
+			}
+			end, ok := skipSubBlocks(data, i+2)
+			if !ok {
+				return nil, errInvalidGIF
+			}
+			if keepGIFExtension(data[i+1], data[i+2:end]) {
+				out = append(out, data[i:end]...)
+			}
+			i = end
+		case 0x2C: // image descriptor, local color table and image data
+			if i+10 > len(data) {
+				return nil, errInvalidGIF
+			}
+			start := i
+			flags := data[i+9]
+			i += 10
+			if flags&0x80 != 0 {
+				i += 3 << ((flags & 0x07) + 1)
+			}
+			end, ok := skipSubBlocks(data, i+1)
+			if !ok {
+				return nil, errInvalidGIF
+			}
+			out = append(out, data[start:end]...)
+			i = end
+		case 0x3B: // trailer; anything after it is dropped
+			return append(out, 0x3B), nil
+		default:
+			return nil, errInvalidGIF
+		}
+	}
+	return nil, errInvalidGIF
+}
+
+// keepGIFExtension reports whether an extension affects how the GIF plays
+func keepGIFExtension(label byte, blocks []byte) bool {
+	switch label {
+	case 0xF9: // graphic control: delays, disposal, transparency
+		return true
+	case 0xFF: // application: only the loop count
+		return len(blocks) >= 12 && blocks[0] == 11 && string(blocks[1:12]) == "NETSCAPE2.0"
+	}
+	return false
+}
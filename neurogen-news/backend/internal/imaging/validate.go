@@ -0,0 +1,206 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+)
+
+// MaxGIFFrames bounds animations; each frame is decoded at full size
+const MaxGIFFrames = 300
+
+var (
+	ErrPolyglot      = errors.New("image carries non-image data")
+	ErrTooManyFrames = errors.New("animation has too many frames")
+)
+
+// Info describes a validated image
+type Info struct {
+	Format      string
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+	Frames      int
+
+	// Clean reports that nothing follows the end of the image. JPEG and PNG
+	// may carry trailing data (motion photos append a video); re-encoding
+	// drops it, so such files must not be stored as they are.
+	Clean bool
+}
+
+// Validate identifies an image by its magic bytes, never by a declared type
+// or file name, and checks that it is safe to store and decode: the header
+// must decode as the sniffed format, the pixel count stays under MaxPixels,
+// GIFs stay under MaxGIFFrames, nothing may look like markup a browser could
+// sniff, and GIF and WebP, which are stored without re-encoding, must end at
+// their end marker.
+func Validate(data []byte) (*Info, error) {
+	format := sniff(data)
+	if format == "" {
+		return nil, ErrUnsupportedFormat
+	}
+
+	cfg, decoded, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || decoded != format {
+		return nil, ErrUnsupportedFormat
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	clean := endsCleanly(data, format)
+	if containsMarkup(data) || (!clean && (format == FormatGIF || format == FormatWebP)) {
+		return nil, ErrPolyglot
+	}
+
+	frames := 1
+	if format == FormatGIF {
+		frames, err = gifFrames(data)
+		if err != nil {
+			return nil, err
+		}
+		if frames > MaxGIFFrames {
+			return nil, ErrTooManyFrames
+		}
+	}
+
+	return &Info{
+		Format:      format,
+		ContentType: ContentType(format),
+		Extension:   Extension(format),
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		Frames:      frames,
+		Clean:       clean,
+	}, nil
+}
+
+var (
+	jpegMagic = []byte{0xFF, 0xD8, 0xFF}
+	pngMagic  = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+	pngEnd    = []byte{0, 0, 0, 0, 'I', 'E', 'N', 'D', 0xAE, 0x42, 0x60, 0x82}
+)
+
+// sniff returns the format the magic bytes announce, or ""
+func sniff(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, jpegMagic):
+		return FormatJPEG
+	case bytes.HasPrefix(data, pngMagic):
+		return FormatPNG
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return FormatGIF
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return FormatWebP
+	}
+	return ""
+}
+
+// endsCleanly reports whether the file stops at its format's end marker, so
+// no archive or script is appended after the image
+func endsCleanly(data []byte, format string) bool {
+	switch format {
+	case FormatJPEG:
+		// Some encoders pad after the EOI marker with zeros
+		trimmed := bytes.TrimRight(data, "\x00")
+		return bytes.HasSuffix(trimmed, []byte{0xFF, 0xD9})
+	case FormatPNG:
+		return bytes.HasSuffix(data, pngEnd)
+	case FormatGIF:
+		return data[len(data)-1] == 0x3B
+	case FormatWebP:
+		size := int64(binary.LittleEndian.Uint32(data[4:8])) + 8
+		return size == int64(len(data)) || size+1 == int64(len(data))
+	}
+	return false
+}
+
+// markupSignatures are what browsers and interpreters look for when they
+// sniff content; none belong in the first bytes of an image
+var markupSignatures = [][]byte{
+	[]byte("<script"),
+	[]byte("<html"),
+	[]byte("<!doctype"),
+	[]byte("<svg"),
+	[]byte("<iframe"),
+	[]byte("<body"),
+	[]byte("<head"),
+	[]byte("<?php"),
+}
+
+// markupScanBytes covers the sniffing window of browsers with a margin
+const markupScanBytes = 1024
+
+func containsMarkup(data []byte) bool {
+	head := bytes.ToLower(data[:min(len(data), markupScanBytes)])
+	for _, sig := range markupSignatures {
+		if bytes.Contains(head, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// gifFrames counts image descriptors by walking the GIF block structure
+// without decompressing any frame
+func gifFrames(data []byte) (int, error) {
+	const headerSize = 13
+	if len(data) < headerSize {
+		return 0, ErrUnsupportedFormat
+	}
+	i := headerSize
+	if flags := data[10]; flags&0x80 != 0 {
+		i += 3 << ((flags & 0x07) + 1)
+	}
+
+	frames := 0
+	for i < len(data) {
+		switch data[i] {
+		case 0x21: // extension: label, then sub-blocks
+			end, ok := skipSubBlocks(data, i+2)
+			if !ok {
+				return 0, ErrUnsupportedFormat
+			}
+			i = end
+		case 0x2C: // image descriptor
+			frames++
+			if frames > MaxGIFFrames {
+				return frames, nil
+			}
+			if i+10 > len(data) {
+				return 0, ErrUnsupportedFormat
+			}
+			flags := data[i+9]
+			i += 10
+			if flags&0x80 != 0 {
+				i += 3 << ((flags & 0x07) + 1)
+			}
+			// LZW minimum code size, then the image data sub-blocks
+			end, ok := skipSubBlocks(data, i+1)
+			if !ok {
+				return 0, ErrUnsupportedFormat
+			}
+			i = end
+		case 0x3B: // trailer
+			return frames, nil
+		default:
+			return 0, ErrUnsupportedFormat
+		}
+	}
+	return 0, ErrUnsupportedFormat
+}
+
+// skipSubBlocks returns the index after the block terminator starting at i
+func skipSubBlocks(data []byte, i int) (int, bool) {
+	for i < len(data) {
+		size := int(data[i])
+		i++
+		if size == 0 {
+			return i, true
+		}
+		i += size
+	}
+	return 0, false
+}
@@ -0,0 +1,233 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// Highlighter renders the body of a fenced code block for the given language.
+// Returning ok=false falls back to a plain <pre><code class="language-*"> block.
+// The output still goes through the sanitizer, so highlighters should only
+// emit <span class="..."> markup whose classes are "chroma" or start with
+// "hl-"; any other class is stripped so authors cannot style the page.
+type Highlighter func(lang, code string) (html string, ok bool)
+
+// Config holds renderer configuration
+type Config struct {
+	Highlighter Highlighter
+}
+
+// Renderer converts CommonMark/GFM markdown into sanitized HTML
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+}
+
+var (
+	anchorPattern   = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+	footnotePattern = regexp.MustCompile(`^fn(ref\d*)?:\d+$`)
+	languagePattern = regexp.MustCompile(`^language-[\w+#-]+$`)
+	tokenPattern    = regexp.MustCompile(`^(chroma|hl-[\w-]+)( (chroma|hl-[\w-]+))*$`)
+)
+
+// New creates a new markdown renderer
+func New(cfg Config) *Renderer {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Footnote,
+		),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+		),
+		goldmark.WithRendererOptions(
+			// Raw HTML is allowed through goldmark and filtered by the policy below
+			html.WithUnsafe(),
+			renderer.WithNodeRenderers(
+				util.Prioritized(&codeBlockRenderer{highlighter: cfg.Highlighter}, 100),
+			),
+		),
+	)
+
+	return &Renderer{
+		md:     md,
+		policy: newPolicy(),
+	}
+}
+
+// Render converts markdown source to sanitized HTML
+func (r *Renderer) Render(source string) (string, error) {
+	var buf bytes.Buffer
+
+	ctx := parser.NewContext(parser.WithIDs(newAnchorIDs()))
+	if err := r.md.Convert([]byte(source), &buf, parser.WithContext(ctx)); err != nil {
+		return "", err
+	}
+
+	return r.policy.Sanitize(buf.String()), nil
+}
+
+// Sanitize filters arbitrary HTML through the article allow-list
+func (r *Renderer) Sanitize(html string) string {
+	return r.policy.Sanitize(html)
+}
+
+// newPolicy builds the HTML allow-list for user generated articles
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+
+	// Heading anchors
+	p.AllowAttrs("id").Matching(anchorPattern).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+
+	// Fenced code language and highlighter tokens
+	p.AllowAttrs("class").Matching(languagePattern).OnElements("code")
+	p.AllowAttrs("class").Matching(tokenPattern).OnElements("pre", "span")
+
+	// GFM task lists
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+
+	// Footnotes: goldmark names them fn:N, fnref:N and fnrefK:N
+	p.AllowAttrs("id").Matching(footnotePattern).OnElements("li", "sup")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^footnotes?(-ref|-backref)?$`)).OnElements("a", "div", "section")
+	p.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-(noteref|backlink|endnotes)$`)).OnElements("a", "div", "section")
+
+	// Images are lazy-loaded in the article body
+	p.AllowAttrs("loading").Matching(regexp.MustCompile(`^lazy$`)).OnElements("img")
+
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+
+	return p
+}
+
+// ============================================
+// Fenced code blocks
+// ============================================
+
+type codeBlockRenderer struct {
+	highlighter Highlighter
+}
+
+func (r *codeBlockRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderFencedCodeBlock)
+}
+
+func (r *codeBlockRenderer) renderFencedCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*ast.FencedCodeBlock)
+	lang := strings.ToLower(string(n.Language(source)))
+
+	var code bytes.Buffer
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		code.Write(line.Value(source))
+	}
+
+	if r.highlighter != nil && lang != "" {
+		if out, ok := r.highlighter(lang, code.String()); ok {
+			_, _ = w.WriteString(`<pre><code class="language-`)
+			_, _ = w.Write(util.EscapeHTML([]byte(lang)))
+			_, _ = w.WriteString(`">`)
+			_, _ = w.WriteString(out)
+			_, _ = w.WriteString("</code></pre>\n")
+			return ast.WalkSkipChildren, nil
+		}
+	}
+
+	_, _ = w.WriteString("<pre><code")
+	if lang != "" {
+		_, _ = w.WriteString(` class="language-`)
+		_, _ = w.Write(util.EscapeHTML([]byte(lang)))
+		_, _ = w.WriteString(`"`)
+	}
+	_, _ = w.WriteString(">")
+	_, _ = w.Write(util.EscapeHTML(code.Bytes()))
+	_, _ = w.WriteString("</code></pre>\n")
+
+	return ast.WalkSkipChildren, nil
+}
+
+// ============================================
+// Heading anchors
+// ============================================
+
+// anchorIDs generates readable, unique heading anchors (Cyrillic is transliterated)
+type anchorIDs struct {
+	used map[string]bool
+}
+
+func newAnchorIDs() *anchorIDs {
+	return &anchorIDs{used: make(map[string]bool)}
+}
+
+func (s *anchorIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := anchorSlug(string(value))
+	if base == "" {
+		base = "section"
+	}
+
+	id := base
+	for i := 1; s.used[id]; i++ {
+		id = base + "-" + strconv.Itoa(i)
+	}
+	s.used[id] = true
+
+	return []byte(id)
+}
+
+func (s *anchorIDs) Put(value []byte) {
+	s.used[string(value)] = true
+}
+
+var anchorTranslit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+func anchorSlug(text string) string {
+	var b strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case anchorTranslit[r] != "":
+			b.WriteString(anchorTranslit[r])
+			dash = false
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			dash = false
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+
+	slug := strings.TrimRight(b.String(), "-")
+	if len(slug) > 80 {
+		slug = strings.TrimRight(slug[:80], "-")
+	}
+
+	return slug
+}
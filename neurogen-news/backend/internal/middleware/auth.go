@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/neurogen-news/backend/internal/model"
 	"github.com/neurogen-news/backend/internal/service"
 )
@@ -50,14 +51,44 @@ func Auth(authService service.AuthService) fiber.Handler {
 			})
 		}
 
+		// Banned users keep valid tokens until they expire
+		ban, err := authService.CheckBan(c.Context(), claims.UserID)
+		if err != nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "Failed to verify account",
+			})
+		}
+		if ban != nil {
+			return BannedResponse(c, ban)
+		}
+
+		// Promotions and demotions apply before the token is refreshed
+		role := claims.Role
+		if current, err := authService.CurrentRole(c.Context(), claims.UserID); err == nil && current != "" {
+			role = current
+		}
+
 		// Set user info in context
 		c.Locals(string(UserIDKey), claims.UserID)
-		c.Locals(string(UserRoleKey), claims.Role)
+		c.Locals(string(UserRoleKey), role)
 
 		return c.Next()
 	}
 }
 
+// BannedResponse rejects a request from a banned user
+func BannedResponse(c *fiber.Ctx, ban *service.BanInfo) error {
+	body := fiber.Map{
+		"error":  "Your account has been banned",
+		"code":   "USER_BANNED",
+		"reason": ban.Reason,
+	}
+	if ban.Until != nil {
+		body["bannedUntil"] = ban.Until
+	}
+	return c.Status(fiber.StatusForbidden).JSON(body)
+}
+
 func OptionalAuth(authService service.AuthService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
@@ -81,25 +112,32 @@ func OptionalAuth(authService service.AuthService) fiber.Handler {
 	}
 }
 
-func RequireRole(roles ...string) fiber.Handler {
+// RequirePermission admits users whose role holds the permission.
+// Category moderator grants are checked by the services that know the category.
+func RequirePermission(perm model.Permission) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		role, ok := c.Locals(string(UserRoleKey)).(model.UserRole)
-		if !ok {
+		if !ok || !role.Can(perm) {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"error": "Access denied",
 			})
 		}
 
-		// Check if user's role is in the allowed roles
-		for _, r := range roles {
-			if string(role) == r {
-				return c.Next()
-			}
+		return c.Next()
+	}
+}
+
+// TrackActivity records authenticated users for the active users statistic.
+// It runs after the route, when Auth or OptionalAuth has identified the user.
+func TrackActivity(statsService service.StatsService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		if userID, ok := c.Locals(string(UserIDKey)).(uuid.UUID); ok {
+			statsService.TrackActiveUser(c.Context(), userID)
 		}
 
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Access denied",
-		})
+		return err
 	}
 }
 
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neurogen-news/backend/internal/repository"
+	"github.com/neurogen-news/backend/internal/service"
+)
+
+// RateLimit caps requests per IP at the rateLimitPerMinute setting. Counters
+// live in Redis, so the limit holds across instances; when Redis is
+// unavailable requests are let through.
+func RateLimit(settings service.SettingsService, redis *repository.RedisClient) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limit := settings.Current().RateLimitPerMinute
+
+		allowed, err := redis.CheckRateLimit(c.Context(), "ratelimit:"+c.IP(), limit, time.Minute)
+		if err == nil && !allowed {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "Too many requests",
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -33,6 +33,13 @@ const (
 	StatusArchived  ArticleStatus = "archived"
 )
 
+// Outcome of the last pre-moderation review
+const (
+	ReviewApproved         = "approved"
+	ReviewRejected         = "rejected"
+	ReviewChangesRequested = "changes_requested"
+)
+
 type Article struct {
 	ID              uuid.UUID     `json:"id" db:"id"`
 	Title           string        `json:"title" db:"title"`
@@ -67,6 +74,16 @@ type Article struct {
 	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updatedAt" db:"updated_at"`
 
+	// Pre-moderation
+	SubmittedAt    *time.Time `json:"submittedAt,omitempty" db:"submitted_at"`
+	ReviewDecision *string    `json:"reviewDecision,omitempty" db:"review_decision"`
+	ReviewNote     *string    `json:"reviewNote,omitempty" db:"review_note"`
+	ReviewedBy     *uuid.UUID `json:"reviewedBy,omitempty" db:"reviewed_by"`
+	ReviewedAt     *time.Time `json:"reviewedAt,omitempty" db:"reviewed_at"`
+
+	// Set by automoderation; shadow-hidden articles are shown only to the author
+	AutomodState *string `json:"-" db:"automod_state"`
+
 	// Relations (populated separately)
 	Author    *User          `json:"author,omitempty"`
 	Category  *Category      `json:"category,omitempty"`
@@ -74,6 +91,24 @@ type Article struct {
 	Reactions []ReactionCount `json:"reactions,omitempty"`
 }
 
+// ArticleVersion is the title and content of an article as they were after
+// an edit. Lists leave Content empty.
+type ArticleVersion struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	ArticleID uuid.UUID `json:"articleId" db:"article_id"`
+	Version   int       `json:"version" db:"version"`
+	Title     string    `json:"title" db:"title"`
+	Content   string    `json:"content,omitempty" db:"content"`
+	EditedBy  uuid.UUID `json:"editedBy" db:"edited_by"`
+	// The version whose text this edit brought back
+	RestoredFrom *int      `json:"restoredFrom,omitempty" db:"restored_from"`
+	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
+
+	// Populated separately; ByAuthor is false for edits by editors and moderators
+	Editor   *CommentAuthor `json:"editor,omitempty"`
+	ByAuthor bool           `json:"byAuthor"`
+}
+
 type ArticleCard struct {
 	ID            uuid.UUID    `json:"id" db:"id"`
 	Title         string       `json:"title" db:"title"`
@@ -86,6 +121,10 @@ type ArticleCard struct {
 	IsEditorial   bool         `json:"isEditorial" db:"is_editorial"`
 	IsPinned      bool         `json:"isPinned" db:"is_pinned"`
 
+	// The review note is shown to authors on their unpublished articles
+	Status     ArticleStatus `json:"status,omitempty" db:"status"`
+	ReviewNote *string       `json:"reviewNote,omitempty" db:"review_note"`
+
 	AuthorID       uuid.UUID `json:"authorId" db:"author_id"`
 	AuthorUsername string    `json:"authorUsername" db:"author_username"`
 	AuthorName     string    `json:"authorName" db:"author_display_name"`
@@ -106,6 +145,17 @@ type ArticleCard struct {
 	// Populated separately
 	Tags      []Tag          `json:"tags,omitempty"`
 	Reactions []ReactionCount `json:"reactions,omitempty"`
+
+	// Search results only
+	Formatted *ArticleHighlight `json:"_formatted,omitempty"`
+}
+
+// ArticleHighlight holds HTML-escaped fields of a search hit with the matched
+// terms wrapped in <mark>. Content is cropped to the fragments around matches.
+type ArticleHighlight struct {
+	Title   string `json:"title"`
+	Lead    string `json:"lead,omitempty"`
+	Content string `json:"content,omitempty"`
 }
 
 type Category struct {
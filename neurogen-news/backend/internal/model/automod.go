@@ -0,0 +1,68 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SystemUserID is the automoderator account seeded by migration 011. It files
+// automatic reports and signs automatic moderation actions.
+var SystemUserID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+type AutomodRuleKind string
+
+const (
+	AutomodStopwords  AutomodRuleKind = "stopwords"
+	AutomodRegex      AutomodRuleKind = "regex"
+	AutomodLinks      AutomodRuleKind = "links"
+	AutomodCaps       AutomodRuleKind = "caps"
+	AutomodDuplicate  AutomodRuleKind = "duplicate"
+	AutomodNewAccount AutomodRuleKind = "new_account"
+)
+
+// AutomodAction is what happens to content that matches a rule, in order of severity
+type AutomodAction string
+
+const (
+	AutomodAllow      AutomodAction = "allow"
+	AutomodHold       AutomodAction = "hold"
+	AutomodShadowHide AutomodAction = "shadow_hide"
+	AutomodReject     AutomodAction = "reject"
+)
+
+// Content a rule applies to
+const (
+	AutomodTargetComment = "comment"
+	AutomodTargetArticle = "article"
+	AutomodTargetAll     = "all"
+)
+
+// automod_state of comments and articles; NULL means visible to everyone
+const (
+	AutomodStateHeld   = "held"
+	AutomodStateShadow = "shadow"
+)
+
+// AutomodRule is an admin-managed rule. Config depends on Kind:
+//
+//	stopwords:   {"words": ["казино", "free money"]}
+//	regex:       {"patterns": ["(?i)t\\.me/\\w+"]}
+//	links:       {"max": 2}
+//	caps:        {"ratio": 0.7, "minLetters": 20}
+//	duplicate:   {"windowMinutes": 60, "global": false}
+//	new_account: {"maxAgeHours": 24, "limit": 3, "windowMinutes": 60}
+type AutomodRule struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	Name      string          `json:"name" db:"name"`
+	Kind      AutomodRuleKind `json:"kind" db:"kind"`
+	Target    string          `json:"target" db:"target"`
+	Action    AutomodAction   `json:"action" db:"action"`
+	Config    json.RawMessage `json:"config" db:"config"`
+	Priority  int             `json:"priority" db:"priority"` // lower runs first
+	IsEnabled bool            `json:"isEnabled" db:"is_enabled"`
+	CreatedBy *uuid.UUID      `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time       `json:"updatedAt" db:"updated_at"`
+}
@@ -19,11 +19,21 @@ type Comment struct {
 	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updatedAt" db:"updated_at"`
 
+	// Held and shadow-hidden comments are shown only to the author;
+	// IsHeld tells the author about a hold when the comment is created
+	AutomodState *string `json:"-" db:"automod_state"`
+	IsHeld       bool    `json:"isHeld,omitempty" db:"-"`
+
 	// Populated separately
 	Author    *CommentAuthor  `json:"author,omitempty"`
 	Reactions []ReactionCount `json:"reactions,omitempty"`
 	Replies   []Comment       `json:"replies,omitempty"`
 	Depth     int             `json:"depth"`
+
+	// Tree endpoints: replies not included in Replies. An empty cursor with
+	// HasMoreReplies means the branch was collapsed at the depth limit.
+	HasMoreReplies bool   `json:"hasMoreReplies,omitempty"`
+	RepliesCursor  string `json:"repliesCursor,omitempty"`
 }
 
 type CommentAuthor struct {
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Media is an uploaded image in its owner's library. Key is the storage key
+// of the original; variants are derived from it.
+type Media struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"userId" db:"user_id"`
+	Key         string    `json:"key" db:"key"`
+	Name        string    `json:"name" db:"name"`
+	Type        string    `json:"type" db:"type"`
+	ContentType string    `json:"contentType" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	Width       int       `json:"width" db:"width"`
+	Height      int       `json:"height" db:"height"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+
+	// Populated separately
+	URL        string           `json:"url"`
+	References []MediaReference `json:"references"`
+}
+
+type MediaReferenceType string
+
+const (
+	MediaRefArticle        MediaReferenceType = "article"
+	MediaRefArticleVersion MediaReferenceType = "article_version"
+	MediaRefDraft          MediaReferenceType = "draft"
+	MediaRefAvatar         MediaReferenceType = "avatar"
+	MediaRefProfileCover   MediaReferenceType = "profile_cover"
+)
+
+// MediaReference is a place that shows a media item. TargetID is the article,
+// article version, draft or user.
+type MediaReference struct {
+	MediaID    uuid.UUID          `json:"-" db:"media_id"`
+	TargetType MediaReferenceType `json:"targetType" db:"target_type"`
+	TargetID   uuid.UUID          `json:"targetId" db:"target_id"`
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ModerationActionType string
+
+const (
+	ModerationApprove        ModerationActionType = "approve"
+	ModerationReject         ModerationActionType = "reject"
+	ModerationRequestChanges ModerationActionType = "request_changes"
+	ModerationDelete         ModerationActionType = "delete"
+	ModerationHide           ModerationActionType = "hide"
+	ModerationWarn           ModerationActionType = "warn"
+	ModerationBan            ModerationActionType = "ban"
+	ModerationUnban          ModerationActionType = "unban"
+	ModerationResolve        ModerationActionType = "resolve"
+	ModerationDismiss        ModerationActionType = "dismiss"
+
+	// Automoderation
+	ModerationHold       ModerationActionType = "hold"
+	ModerationShadowHide ModerationActionType = "shadow_hide"
+	ModerationRelease    ModerationActionType = "release"
+
+	// Configuration changes
+	ModerationCreate ModerationActionType = "create"
+	ModerationUpdate ModerationActionType = "update"
+
+	// Role management
+	ModerationPromote ModerationActionType = "promote"
+	ModerationDemote  ModerationActionType = "demote"
+	ModerationGrant   ModerationActionType = "grant"
+	ModerationRevoke  ModerationActionType = "revoke"
+)
+
+// Audit log targets besides reportable content
+const (
+	AuditTargetReport      = "report"
+	AuditTargetAutomodRule = "automod_rule"
+	AuditTargetSettings    = "settings"
+)
+
+// ModerationAction is an audit log entry: who did what to which entity, and
+// the entity as it was before and after
+type ModerationAction struct {
+	ID          uuid.UUID            `json:"id" db:"id"`
+	ModeratorID uuid.UUID            `json:"moderatorId" db:"moderator_id"`
+	TargetType  string               `json:"targetType" db:"target_type"` // article, comment, user, report
+	TargetID    uuid.UUID            `json:"targetId" db:"target_id"`
+	Action      ModerationActionType `json:"action" db:"action"`
+	Reason      *string              `json:"reason,omitempty" db:"reason"`
+	Before      json.RawMessage      `json:"before,omitempty" db:"snapshot_before"`
+	After       json.RawMessage      `json:"after,omitempty" db:"snapshot_after"`
+	CreatedAt   time.Time            `json:"createdAt" db:"created_at"`
+
+	// Populated separately
+	Moderator *NotificationActor `json:"moderator,omitempty"`
+}
+
+// UserBan is one entry of a user's ban history. A ban ends at ExpiresAt unless
+// it is permanent, or earlier when it is revoked.
+type UserBan struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	UserID      uuid.UUID  `json:"userId" db:"user_id"`
+	BannedBy    uuid.UUID  `json:"bannedBy" db:"banned_by"`
+	Reason      string     `json:"reason" db:"reason"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty" db:"expires_at"`
+	IsPermanent bool       `json:"isPermanent" db:"is_permanent"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty" db:"revoked_at"`
+	RevokedBy   *uuid.UUID `json:"revokedBy,omitempty" db:"revoked_by"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// IsActive reports whether the ban is in force at t
+func (b *UserBan) IsActive(t time.Time) bool {
+	if b.RevokedAt != nil {
+		return false
+	}
+	return b.IsPermanent || (b.ExpiresAt != nil && b.ExpiresAt.After(t))
+}
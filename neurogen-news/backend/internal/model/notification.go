@@ -16,6 +16,7 @@ const (
 	NotificationArticlePublished NotificationType = "article_published"
 	NotificationMention         NotificationType = "mention"
 	NotificationSystem          NotificationType = "system"
+	NotificationArticleReview   NotificationType = "article_review"
 )
 
 type Notification struct {
@@ -75,16 +76,42 @@ type AchievementProgress struct {
 	TotalPoints      int       `json:"totalPoints" db:"total_points"`
 }
 
+// Report targets
+const (
+	ReportTargetArticle = "article"
+	ReportTargetComment = "comment"
+	ReportTargetUser    = "user"
+)
+
+// Report statuses
+const (
+	ReportStatusPending   = "pending"
+	ReportStatusResolved  = "resolved"
+	ReportStatusDismissed = "dismissed"
+)
+
+// Reasons a report can be filed for
+var ReportReasons = []string{"spam", "abuse", "harassment", "misinformation", "nsfw", "copyright", "other"}
+
+// ReportReasonAutomod marks reports filed by automoderation; users cannot pick it
+const ReportReasonAutomod = "automod"
+
 type Report struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	ReporterID  uuid.UUID `json:"reporterId" db:"reporter_id"`
-	TargetType  string    `json:"targetType" db:"target_type"` // article, comment, user
-	TargetID    uuid.UUID `json:"targetId" db:"target_id"`
-	Reason      string    `json:"reason" db:"reason"`
-	Description *string   `json:"description,omitempty" db:"description"`
-	Status      string    `json:"status" db:"status"` // pending, resolved, dismissed
-	ResolvedBy  *uuid.UUID `json:"resolvedBy,omitempty" db:"resolved_by"`
-	ResolvedAt  *time.Time `json:"resolvedAt,omitempty" db:"resolved_at"`
-	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
-}
+	ID             uuid.UUID             `json:"id" db:"id"`
+	ReporterID     uuid.UUID             `json:"reporterId" db:"reporter_id"`
+	TargetType     string                `json:"targetType" db:"target_type"` // article, comment, user
+	TargetID       uuid.UUID             `json:"targetId" db:"target_id"`
+	Reason         string                `json:"reason" db:"reason"`
+	Description    *string               `json:"description,omitempty" db:"description"`
+	Status         string                `json:"status" db:"status"` // pending, resolved, dismissed
+	AssignedTo     *uuid.UUID            `json:"assignedTo,omitempty" db:"assigned_to"`
+	AssignedAt     *time.Time            `json:"assignedAt,omitempty" db:"assigned_at"`
+	Resolution     *ModerationActionType `json:"resolution,omitempty" db:"resolution"`
+	ResolutionNote *string               `json:"resolutionNote,omitempty" db:"resolution_note"`
+	ResolvedBy     *uuid.UUID            `json:"resolvedBy,omitempty" db:"resolved_by"`
+	ResolvedAt     *time.Time            `json:"resolvedAt,omitempty" db:"resolved_at"`
+	CreatedAt      time.Time             `json:"createdAt" db:"created_at"`
 
+	// Populated separately
+	Reporter *NotificationActor `json:"reporter,omitempty"`
+}
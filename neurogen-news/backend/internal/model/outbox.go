@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxEventType string
+
+const (
+	OutboxArticleUpserted OutboxEventType = "article.upserted"
+	OutboxArticleDeleted  OutboxEventType = "article.deleted"
+	OutboxUserUpserted    OutboxEventType = "user.upserted"
+	OutboxTagUpserted     OutboxEventType = "tag.upserted"
+)
+
+// OutboxEvent is a domain event recorded in the same transaction as the change
+// it describes and delivered asynchronously by the outbox dispatcher
+type OutboxEvent struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	EventType   OutboxEventType `json:"eventType" db:"event_type"`
+	AggregateID uuid.UUID       `json:"aggregateId" db:"aggregate_id"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	LastError   *string         `json:"lastError,omitempty" db:"last_error"`
+	AvailableAt time.Time       `json:"availableAt" db:"available_at"`
+	ProcessedAt *time.Time      `json:"processedAt,omitempty" db:"processed_at"`
+	DeadAt      *time.Time      `json:"deadAt,omitempty" db:"dead_at"`
+	CreatedAt   time.Time       `json:"createdAt" db:"created_at"`
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission is a capability checked by routes and services instead of role names
+type Permission string
+
+const (
+	PermDashboardView Permission = "dashboard.view"
+	PermAuditRead     Permission = "audit.read"
+
+	// Users
+	PermUserView     Permission = "user.view"
+	PermUserBan      Permission = "user.ban"
+	PermUserRoles    Permission = "user.roles"
+	PermUserQuota    Permission = "user.quota"
+	PermReportManage Permission = "report.manage"
+
+	// Content
+	PermArticleReview    Permission = "article.review"
+	PermArticleEditAny   Permission = "article.edit.any"
+	PermArticleDeleteAny Permission = "article.delete.any"
+	PermCommentDeleteAny Permission = "comment.delete.any"
+
+	// Platform
+	PermSettingsRead   Permission = "settings.read"
+	PermSettingsWrite  Permission = "settings.write"
+	PermAutomodManage  Permission = "automod.manage"
+	PermCategoryManage Permission = "category.manage"
+	PermOutboxManage   Permission = "outbox.manage"
+)
+
+// RolePermissions is the permission matrix; ADMIN holds every permission
+var RolePermissions = map[UserRole][]Permission{
+	RoleUser:   {},
+	RoleAuthor: {},
+	RoleModerator: {
+		PermDashboardView,
+		PermUserView,
+		PermUserBan,
+		PermReportManage,
+		PermArticleDeleteAny,
+		PermCommentDeleteAny,
+	},
+	RoleEditor: {
+		PermDashboardView,
+		PermUserView,
+		PermReportManage,
+		PermArticleReview,
+		PermArticleEditAny,
+		PermArticleDeleteAny,
+		PermCommentDeleteAny,
+	},
+	RoleAdmin: {
+		PermDashboardView,
+		PermAuditRead,
+		PermUserView,
+		PermUserBan,
+		PermUserRoles,
+		PermUserQuota,
+		PermReportManage,
+		PermArticleReview,
+		PermArticleEditAny,
+		PermArticleDeleteAny,
+		PermCommentDeleteAny,
+		PermSettingsRead,
+		PermSettingsWrite,
+		PermAutomodManage,
+		PermCategoryManage,
+		PermOutboxManage,
+	},
+}
+
+// CategoryPermissions are what a category moderator grant allows within its category
+var CategoryPermissions = []Permission{
+	PermArticleEditAny,
+	PermArticleDeleteAny,
+	PermCommentDeleteAny,
+}
+
+// Can reports whether the role holds the permission
+func (r UserRole) Can(p Permission) bool {
+	for _, held := range RolePermissions[r] {
+		if held == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid reports whether the role is one of the known roles
+func (r UserRole) Valid() bool {
+	_, ok := RolePermissions[r]
+	return ok
+}
+
+// Rank orders roles from least to most privileged
+func (r UserRole) Rank() int {
+	switch r {
+	case RoleAuthor:
+		return 1
+	case RoleModerator:
+		return 2
+	case RoleEditor:
+		return 3
+	case RoleAdmin:
+		return 4
+	}
+	return 0
+}
+
+// IsCategoryPermission reports whether a category moderator grant can give the permission
+func IsCategoryPermission(p Permission) bool {
+	for _, cp := range CategoryPermissions {
+		if cp == p {
+			return true
+		}
+	}
+	return false
+}
+
+// CategoryModerator grants a user moderation of one category regardless of their role
+type CategoryModerator struct {
+	UserID     uuid.UUID  `json:"userId" db:"user_id"`
+	CategoryID uuid.UUID  `json:"categoryId" db:"category_id"`
+	GrantedBy  *uuid.UUID `json:"grantedBy,omitempty" db:"granted_by"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+
+	// Populated separately
+	User         *NotificationActor `json:"user,omitempty"`
+	CategoryName string             `json:"categoryName,omitempty"`
+}
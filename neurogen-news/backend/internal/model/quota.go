@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UploadQuota limits the total size of a user's originals and how many
+// images they can upload per day (UTC)
+type UploadQuota struct {
+	MaxBytes        int64 `json:"maxBytes"`
+	MaxDailyUploads int   `json:"maxDailyUploads"`
+}
+
+const (
+	megabyte = 1024 * 1024
+	gigabyte = 1024 * megabyte
+)
+
+// RoleUploadQuotas are the default quotas; admins can override them per user
+var RoleUploadQuotas = map[UserRole]UploadQuota{
+	RoleUser:      {MaxBytes: 100 * megabyte, MaxDailyUploads: 20},
+	RoleAuthor:    {MaxBytes: 1 * gigabyte, MaxDailyUploads: 100},
+	RoleModerator: {MaxBytes: 1 * gigabyte, MaxDailyUploads: 100},
+	RoleEditor:    {MaxBytes: 5 * gigabyte, MaxDailyUploads: 500},
+	RoleAdmin:     {MaxBytes: 5 * gigabyte, MaxDailyUploads: 500},
+}
+
+// PremiumUploadQuota applies to premium users whose role allows less
+var PremiumUploadQuota = UploadQuota{MaxBytes: 5 * gigabyte, MaxDailyUploads: 500}
+
+// UploadQuotaFor is the default quota of a role, raised for premium users
+func UploadQuotaFor(role UserRole, isPremium bool) UploadQuota {
+	quota := RoleUploadQuotas[role]
+	if isPremium {
+		quota.MaxBytes = max(quota.MaxBytes, PremiumUploadQuota.MaxBytes)
+		quota.MaxDailyUploads = max(quota.MaxDailyUploads, PremiumUploadQuota.MaxDailyUploads)
+	}
+	return quota
+}
+
+// UploadUsage is what a user has stored, maintained by triggers on media.
+// UploadsToday counts uploads made on UsageDate, deletions do not lower it.
+type UploadUsage struct {
+	UserID       uuid.UUID `json:"userId" db:"user_id"`
+	UsedBytes    int64     `json:"usedBytes" db:"used_bytes"`
+	FileCount    int       `json:"fileCount" db:"file_count"`
+	UploadsToday int       `json:"uploadsToday" db:"uploads_today"`
+
+	// Unexpired reservations of uploads still being processed
+	ReservedBytes   int64 `json:"reservedBytes"`
+	ReservedUploads int   `json:"reservedUploads"`
+
+	// Admin overrides of the role quota; nil keeps the default
+	MaxBytes        *int64 `json:"maxBytes,omitempty" db:"max_bytes"`
+	MaxDailyUploads *int   `json:"maxDailyUploads,omitempty" db:"max_daily_uploads"`
+
+	UpdatedAt *time.Time `json:"updatedAt,omitempty" db:"updated_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Setting is a stored override of a platform setting
+type Setting struct {
+	Key       string          `json:"key" db:"key"`
+	Value     json.RawMessage `json:"value" db:"value"`
+	UpdatedBy *uuid.UUID      `json:"updatedBy,omitempty" db:"updated_by"`
+	UpdatedAt time.Time       `json:"updatedAt" db:"updated_at"`
+}
+
+// SettingChange is one entry of the settings history. OldValue is empty when
+// the setting was at its default.
+type SettingChange struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	Key       string          `json:"key" db:"key"`
+	OldValue  json.RawMessage `json:"oldValue,omitempty" db:"old_value"`
+	NewValue  json.RawMessage `json:"newValue" db:"new_value"`
+	ChangedBy *uuid.UUID      `json:"changedBy,omitempty" db:"changed_by"`
+	ChangedAt time.Time       `json:"changedAt" db:"changed_at"`
+
+	// Populated separately
+	Changer *NotificationActor `json:"changer,omitempty"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// PlatformTotals are the headline numbers of the admin dashboard
+type PlatformTotals struct {
+	TotalUsers     int64 `json:"totalUsers"`
+	TotalArticles  int64 `json:"totalArticles"`
+	TotalComments  int64 `json:"totalComments"`
+	PendingReports int64 `json:"pendingReports"`
+	ActiveUsers24h int64 `json:"activeUsers24h"`
+}
+
+// DailyStats holds the activity of one UTC day
+type DailyStats struct {
+	Date          string `json:"date"` // YYYY-MM-DD
+	Signups       int64  `json:"signups"`
+	Publications  int64  `json:"publications"`
+	Comments      int64  `json:"comments"`
+	Views         int64  `json:"views"`
+	ReportsOpened int64  `json:"reportsOpened"`
+	ReportsClosed int64  `json:"reportsClosed"`
+}
+
+type DashboardStats struct {
+	Stats       PlatformTotals `json:"stats"`
+	Series      []DailyStats   `json:"series"`
+	GeneratedAt time.Time      `json:"generatedAt"`
+}
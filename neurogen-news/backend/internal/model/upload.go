@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DirectUpload is an upload the client sends straight to object storage with
+// a presigned URL. It binds the key to the user, type and declared size until
+// the client completes it; uploads left pending past ExpiresAt are removed.
+// ClaimedAt is set while a completion is processing the object.
+type DirectUpload struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	UserID      uuid.UUID  `json:"userId" db:"user_id"`
+	Key         string     `json:"key" db:"key"`
+	Type        string     `json:"type" db:"type"`
+	ContentType string     `json:"contentType" db:"content_type"`
+	Size        int64      `json:"size" db:"size"`
+	ExpiresAt   time.Time  `json:"expiresAt" db:"expires_at"`
+	ClaimedAt   *time.Time `json:"claimedAt,omitempty" db:"claimed_at"`
+	CompletedAt *time.Time `json:"completedAt,omitempty" db:"completed_at"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+}
@@ -45,6 +45,11 @@ type User struct {
 	ArticleCount   int `json:"articleCount,omitempty"`
 }
 
+// IsBannedAt reports whether a ban is in force at t; temporary bans lapse on their own
+func (u *User) IsBannedAt(t time.Time) bool {
+	return u.IsBanned && (u.BannedUntil == nil || u.BannedUntil.After(t))
+}
+
 type UserStats struct {
 	FollowerCount  int `json:"followerCount" db:"follower_count"`
 	FollowingCount int `json:"followingCount" db:"following_count"`
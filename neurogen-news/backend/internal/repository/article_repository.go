@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/neurogen-news/backend/internal/highlight"
 	"github.com/neurogen-news/backend/internal/model"
 )
 
@@ -21,8 +22,13 @@ type ArticleRepository interface {
 	GetBySlug(ctx context.Context, categorySlug, articleSlug string) (*model.Article, error)
 	Update(ctx context.Context, article *model.Article) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, status model.ArticleStatus) error
+	Review(ctx context.Context, review ArticleReview) (bool, error)
+	ClearAutomodState(ctx context.Context, id uuid.UUID) (bool, error)
 	
 	List(ctx context.Context, params ArticleListParams) ([]model.ArticleCard, int, error)
+	Search(ctx context.Context, params ArticleSearchParams) ([]model.ArticleCard, int, error)
+	SearchFacets(ctx context.Context, params ArticleSearchParams) (map[string]map[string]int64, error)
 	GetByAuthor(ctx context.Context, authorID uuid.UUID, limit, offset int) ([]model.ArticleCard, int, error)
 	GetByCategory(ctx context.Context, categoryID uuid.UUID, limit, offset int) ([]model.ArticleCard, int, error)
 	GetByTag(ctx context.Context, tagID uuid.UUID, limit, offset int) ([]model.ArticleCard, int, error)
@@ -33,6 +39,18 @@ type ArticleRepository interface {
 	AddTags(ctx context.Context, articleID uuid.UUID, tagIDs []uuid.UUID) error
 	RemoveTags(ctx context.Context, articleID uuid.UUID) error
 	GetTags(ctx context.Context, articleID uuid.UUID) ([]model.Tag, error)
+	
+	// Search index
+	ListPublishedForIndex(ctx context.Context, afterID uuid.UUID, limit int) ([]IndexableArticle, error)
+}
+
+// IndexableArticle is a published article with the related data its search document needs
+type IndexableArticle struct {
+	Article      model.Article
+	AuthorName   string
+	CategoryName string
+	CategorySlug string
+	Tags         []string
 }
 
 type ArticleListParams struct {
@@ -42,11 +60,35 @@ type ArticleListParams struct {
 	CategoryID  *uuid.UUID
 	TagID       *uuid.UUID
 	AuthorID    *uuid.UUID
+	Statuses    []model.ArticleStatus // published only when empty
 	TimeRange   string // 24h, 7d, 30d, all
 	Limit       int
 	Offset      int
 }
 
+// ArticleReview is a pre-moderation decision on a pending article
+type ArticleReview struct {
+	ID         uuid.UUID
+	Status     model.ArticleStatus // the article's status after the review
+	Decision   string
+	Note       *string
+	ReviewerID uuid.UUID
+}
+
+// ArticleSearchParams drives the PostgreSQL full-text search
+type ArticleSearchParams struct {
+	Query        string
+	Prefix       bool // treat the last word as a prefix (search-as-you-type)
+	Level        string
+	ContentType  string
+	CategoryID   *uuid.UUID
+	CategorySlug string
+	Tags         []string
+	Sort         string // relevance, new, popular
+	Limit        int
+	Offset       int
+}
+
 type articleRepository struct {
 	db *PostgresDB
 }
@@ -61,10 +103,10 @@ func (r *articleRepository) Create(ctx context.Context, article *model.Article)
 			id, title, slug, lead, content, html_content, cover_image_url,
 			level, content_type, status, reading_time, is_editorial, is_pinned,
 			is_nsfw, comments_enabled, author_id, category_id,
-			meta_title, meta_description, canonical_url,
+			meta_title, meta_description, canonical_url, published_at, submitted_at, automod_state,
 			created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, NOW(), NOW()
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, NOW(), NOW()
 		)
 	`
 	
@@ -91,6 +133,9 @@ func (r *articleRepository) Create(ctx context.Context, article *model.Article)
 		article.MetaTitle,
 		article.MetaDescription,
 		article.CanonicalURL,
+		article.PublishedAt,
+		article.SubmittedAt,
+		article.AutomodState,
 	)
 	
 	return err
@@ -104,7 +149,9 @@ func (r *articleRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.A
 			a.is_nsfw, a.comments_enabled, a.author_id, a.category_id,
 			a.meta_title, a.meta_description, a.canonical_url,
 			a.view_count, a.comment_count, a.bookmark_count,
-			a.published_at, a.created_at, a.updated_at
+			a.published_at, a.created_at, a.updated_at,
+			a.submitted_at, a.review_decision, a.review_note, a.reviewed_by, a.reviewed_at,
+			a.automod_state
 		FROM articles a
 		WHERE a.id = $1
 	`
@@ -137,6 +184,12 @@ func (r *articleRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.A
 		&article.PublishedAt,
 		&article.CreatedAt,
 		&article.UpdatedAt,
+		&article.SubmittedAt,
+		&article.ReviewDecision,
+		&article.ReviewNote,
+		&article.ReviewedBy,
+		&article.ReviewedAt,
+		&article.AutomodState,
 	)
 	
 	if err != nil {
@@ -157,7 +210,9 @@ func (r *articleRepository) GetBySlug(ctx context.Context, categorySlug, article
 			a.is_nsfw, a.comments_enabled, a.author_id, a.category_id,
 			a.meta_title, a.meta_description, a.canonical_url,
 			a.view_count, a.comment_count, a.bookmark_count,
-			a.published_at, a.created_at, a.updated_at
+			a.published_at, a.created_at, a.updated_at,
+			a.submitted_at, a.review_decision, a.review_note, a.reviewed_by, a.reviewed_at,
+			a.automod_state
 		FROM articles a
 		JOIN categories c ON c.id = a.category_id
 		WHERE a.slug = $1 AND c.slug = $2 AND a.status = 'published'
@@ -191,6 +246,12 @@ func (r *articleRepository) GetBySlug(ctx context.Context, categorySlug, article
 		&article.PublishedAt,
 		&article.CreatedAt,
 		&article.UpdatedAt,
+		&article.SubmittedAt,
+		&article.ReviewDecision,
+		&article.ReviewNote,
+		&article.ReviewedBy,
+		&article.ReviewedAt,
+		&article.AutomodState,
 	)
 	
 	if err != nil {
@@ -211,7 +272,7 @@ func (r *articleRepository) Update(ctx context.Context, article *model.Article)
 			reading_time = $11, is_editorial = $12, is_pinned = $13, is_nsfw = $14,
 			comments_enabled = $15, category_id = $16,
 			meta_title = $17, meta_description = $18, canonical_url = $19,
-			published_at = $20, updated_at = NOW()
+			published_at = $20, submitted_at = $21, automod_state = $22, updated_at = NOW()
 		WHERE id = $1
 	`
 	
@@ -236,6 +297,8 @@ func (r *articleRepository) Update(ctx context.Context, article *model.Article)
 		article.MetaDescription,
 		article.CanonicalURL,
 		article.PublishedAt,
+		article.SubmittedAt,
+		article.AutomodState,
 	)
 	
 	return err
@@ -247,13 +310,67 @@ func (r *articleRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+// UpdateStatus changes only the status, leaving content and publication date alone
+func (r *articleRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status model.ArticleStatus) error {
+	query := `UPDATE articles SET status = $2, updated_at = NOW() WHERE id = $1`
+	tag, err := r.db.Exec(ctx, query, id, status)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrArticleNotFound
+	}
+	return nil
+}
+
+// Review applies a pre-moderation decision. It reports false when the article
+// is no longer pending, so concurrent reviews cannot both succeed.
+func (r *articleRepository) Review(ctx context.Context, review ArticleReview) (bool, error) {
+	query := `
+		UPDATE articles SET
+			status = $2,
+			review_decision = $3,
+			review_note = $4,
+			reviewed_by = $5,
+			reviewed_at = NOW(),
+			published_at = CASE WHEN $2 = 'published' THEN COALESCE(published_at, NOW()) ELSE published_at END,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+	`
+	tag, err := r.db.Exec(ctx, query, review.ID, review.Status, review.Decision, review.Note, review.ReviewerID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ClearAutomodState makes a shadow-hidden article visible. It reports false
+// when the article was not hidden by automoderation.
+func (r *articleRepository) ClearAutomodState(ctx context.Context, id uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE articles SET automod_state = NULL, updated_at = NOW() WHERE id = $1 AND automod_state IS NOT NULL`, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
 func (r *articleRepository) List(ctx context.Context, params ArticleListParams) ([]model.ArticleCard, int, error) {
 	var conditions []string
 	var args []interface{}
 	argNum := 1
 	
-	// Base condition - only published
-	conditions = append(conditions, "a.status = 'published'")
+	// Base condition - only published unless statuses are given
+	if len(params.Statuses) > 0 {
+		statuses := make([]string, len(params.Statuses))
+		for i, status := range params.Statuses {
+			statuses[i] = string(status)
+		}
+		conditions = append(conditions, fmt.Sprintf("a.status::text = ANY($%d)", argNum))
+		args = append(args, statuses)
+		argNum++
+	} else {
+		conditions = append(conditions, "a.status = 'published' AND a.automod_state IS NULL")
+	}
 	
 	// Filters
 	if params.Level != "" {
@@ -302,6 +419,10 @@ func (r *articleRepository) List(ctx context.Context, params ArticleListParams)
 		orderBy = "a.published_at DESC"
 	case "hot":
 		orderBy = "(a.view_count + a.comment_count * 10) DESC, a.published_at DESC"
+	case "submitted": // review queue, oldest first
+		orderBy = "COALESCE(a.submitted_at, a.created_at) ASC"
+	case "updated":
+		orderBy = "a.updated_at DESC"
 	default: // popular
 		orderBy = "a.view_count DESC, a.published_at DESC"
 	}
@@ -319,10 +440,11 @@ func (r *articleRepository) List(ctx context.Context, params ArticleListParams)
 		SELECT 
 			a.id, a.title, a.slug, a.lead, a.cover_image_url,
 			a.level, a.content_type, a.reading_time, a.is_editorial, a.is_pinned,
+			a.status, CASE WHEN a.status = 'published' THEN NULL ELSE a.review_note END,
 			a.author_id, u.username, u.display_name, u.avatar_url, u.is_verified,
 			a.category_id, c.slug, c.name, c.icon,
 			a.view_count, a.comment_count, a.bookmark_count,
-			a.published_at
+			COALESCE(a.published_at, a.created_at)
 		FROM articles a
 		JOIN users u ON u.id = a.author_id
 		JOIN categories c ON c.id = a.category_id
@@ -353,6 +475,8 @@ func (r *articleRepository) List(ctx context.Context, params ArticleListParams)
 			&article.ReadingTime,
 			&article.IsEditorial,
 			&article.IsPinned,
+			&article.Status,
+			&article.ReviewNote,
 			&article.AuthorID,
 			&article.AuthorUsername,
 			&article.AuthorName,
@@ -376,6 +500,226 @@ func (r *articleRepository) List(ctx context.Context, params ArticleListParams)
 	return articles, total, nil
 }
 
+// Search runs a weighted full-text search over published articles.
+// Russian and English stemming are both applied, so queries match either language.
+func (r *articleRepository) Search(ctx context.Context, params ArticleSearchParams) ([]model.ArticleCard, int, error) {
+	tsQuery, conditions, args := articleSearchConditions(params, "")
+	argNum := len(args) + 1
+	
+	whereClause := strings.Join(conditions, " AND ")
+	
+	var orderBy string
+	switch params.Sort {
+	case "new":
+		orderBy = "a.published_at DESC"
+	case "popular":
+		orderBy = "a.view_count DESC, a.published_at DESC"
+	default: // relevance
+		orderBy = "rank DESC, a.published_at DESC"
+	}
+	
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM articles a
+		JOIN categories c ON c.id = a.category_id
+		WHERE %s
+	`, whereClause)
+	var total int
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	
+	// Headlines are expensive, so they are built only for the rows of the page
+	query := fmt.Sprintf(`
+		SELECT 
+			p.id, p.title, p.slug, p.lead, p.cover_image_url,
+			p.level, p.content_type, p.reading_time, p.is_editorial, p.is_pinned,
+			p.author_id, p.username, p.display_name, p.avatar_url, p.is_verified,
+			p.category_id, p.category_slug, p.category_name, p.icon,
+			p.view_count, p.comment_count, p.bookmark_count,
+			p.published_at,
+			ts_headline('russian', p.title, %[1]s, $%[2]d),
+			ts_headline('russian', COALESCE(p.lead, ''), %[1]s, $%[2]d),
+			ts_headline('russian', p.content, %[1]s, $%[3]d)
+		FROM (
+			SELECT 
+				a.id, a.title, a.slug, a.lead, a.content, a.cover_image_url,
+				a.level, a.content_type, a.reading_time, a.is_editorial, a.is_pinned,
+				a.author_id, u.username, u.display_name, u.avatar_url, u.is_verified,
+				a.category_id, c.slug AS category_slug, c.name AS category_name, c.icon,
+				a.view_count, a.comment_count, a.bookmark_count,
+				a.published_at,
+				ts_rank_cd(a.search_vector, %[1]s) AS rank
+			FROM articles a
+			JOIN users u ON u.id = a.author_id
+			JOIN categories c ON c.id = a.category_id
+			WHERE %[4]s
+			ORDER BY %[5]s
+			LIMIT $%[6]d OFFSET $%[7]d
+		) p
+		ORDER BY %[8]s
+	`, tsQuery, argNum, argNum+1, whereClause, orderBy, argNum+2, argNum+3, strings.ReplaceAll(orderBy, "a.", "p."))
+	
+	args = append(args, headlineAll, headlineFragments, params.Limit, params.Offset)
+	
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	
+	var articles []model.ArticleCard
+	for rows.Next() {
+		var article model.ArticleCard
+		var title, lead, content string
+		err := rows.Scan(
+			&article.ID,
+			&article.Title,
+			&article.Slug,
+			&article.Lead,
+			&article.CoverImageURL,
+			&article.Level,
+			&article.ContentType,
+			&article.ReadingTime,
+			&article.IsEditorial,
+			&article.IsPinned,
+			&article.AuthorID,
+			&article.AuthorUsername,
+			&article.AuthorName,
+			&article.AuthorAvatar,
+			&article.AuthorVerified,
+			&article.CategoryID,
+			&article.CategorySlug,
+			&article.CategoryName,
+			&article.CategoryIcon,
+			&article.ViewCount,
+			&article.CommentCount,
+			&article.BookmarkCount,
+			&article.PublishedAt,
+			&title,
+			&lead,
+			&content,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		article.Formatted = &model.ArticleHighlight{
+			Title:   highlight.ToHTML(title),
+			Lead:    highlight.ToHTML(lead),
+			Content: highlight.ToHTML(content),
+		}
+		articles = append(articles, article)
+	}
+	
+	return articles, total, rows.Err()
+}
+
+// SearchFacets counts the search matches per level, content type, category and tag.
+// Single-choice filters are left out of their own counts, so every option keeps its number.
+func (r *articleRepository) SearchFacets(ctx context.Context, params ArticleSearchParams) (map[string]map[string]int64, error) {
+	facets := []struct {
+		name  string
+		value string
+		joins string
+	}{
+		{name: "level", value: "a.level::text"},
+		{name: "contentType", value: "a.content_type::text"},
+		{name: "categorySlug", value: "c.slug"},
+		{name: "tags", value: "t.name", joins: "JOIN article_tags at ON at.article_id = a.id JOIN tags t ON t.id = at.tag_id"},
+	}
+	
+	result := make(map[string]map[string]int64, len(facets))
+	for _, f := range facets {
+		_, conditions, args := articleSearchConditions(params, f.name)
+		
+		query := fmt.Sprintf(`
+			SELECT %s, COUNT(*)
+			FROM articles a
+			JOIN categories c ON c.id = a.category_id
+			%s
+			WHERE %s
+			GROUP BY 1
+			ORDER BY 2 DESC
+			LIMIT 20
+		`, f.value, f.joins, strings.Join(conditions, " AND "))
+		
+		rows, err := r.db.Query(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		
+		counts := make(map[string]int64)
+		for rows.Next() {
+			var value string
+			var count int64
+			if err := rows.Scan(&value, &count); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			counts[value] = count
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		
+		result[f.name] = counts
+	}
+	
+	return result, nil
+}
+
+// articleSearchConditions builds the tsquery expression and WHERE conditions of a
+// search, leaving out the filter on skip (a facet name)
+func articleSearchConditions(params ArticleSearchParams, skip string) (string, []string, []interface{}) {
+	args := []interface{}{params.Query}
+	argNum := 2
+	
+	tsQuery := "(websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1))"
+	if prefix := prefixTSQuery(params.Query); params.Prefix && prefix != "" {
+		tsQuery = fmt.Sprintf("(%s || to_tsquery('simple', $%d))", tsQuery, argNum)
+		args = append(args, prefix)
+		argNum++
+	}
+	
+	conditions := []string{"a.status = 'published'", "a.automod_state IS NULL", "a.search_vector @@ " + tsQuery}
+	
+	if params.Level != "" && skip != "level" {
+		conditions = append(conditions, fmt.Sprintf("a.level = $%d", argNum))
+		args = append(args, params.Level)
+		argNum++
+	}
+	
+	if params.ContentType != "" && skip != "contentType" {
+		conditions = append(conditions, fmt.Sprintf("a.content_type = $%d", argNum))
+		args = append(args, params.ContentType)
+		argNum++
+	}
+	
+	if skip != "categorySlug" {
+		if params.CategoryID != nil {
+			conditions = append(conditions, fmt.Sprintf("a.category_id = $%d", argNum))
+			args = append(args, *params.CategoryID)
+			argNum++
+		} else if params.CategorySlug != "" {
+			conditions = append(conditions, fmt.Sprintf("c.slug = $%d", argNum))
+			args = append(args, params.CategorySlug)
+			argNum++
+		}
+	}
+	
+	for _, tag := range params.Tags {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM article_tags ft JOIN tags fg ON fg.id = ft.tag_id
+			WHERE ft.article_id = a.id AND fg.name = $%d
+		)`, argNum))
+		args = append(args, tag)
+		argNum++
+	}
+	
+	return tsQuery, conditions, args
+}
+
 func (r *articleRepository) GetByAuthor(ctx context.Context, authorID uuid.UUID, limit, offset int) ([]model.ArticleCard, int, error) {
 	return r.List(ctx, ArticleListParams{
 		AuthorID: &authorID,
@@ -464,3 +808,49 @@ func (r *articleRepository) GetTags(ctx context.Context, articleID uuid.UUID) ([
 	return tags, nil
 }
 
+// ListPublishedForIndex returns published articles ordered by ID, starting after afterID.
+// Keyset pagination keeps every page cheap no matter how deep the scan is.
+func (r *articleRepository) ListPublishedForIndex(ctx context.Context, afterID uuid.UUID, limit int) ([]IndexableArticle, error) {
+	query := `
+		SELECT 
+			a.id, a.title, a.slug, a.lead, a.content, a.cover_image_url,
+			a.level, a.content_type, a.status, a.reading_time, a.author_id, a.category_id,
+			a.view_count, a.comment_count, a.published_at,
+			u.display_name, c.name, c.slug,
+			COALESCE(
+				(SELECT array_agg(t.name ORDER BY t.name)
+				 FROM article_tags at JOIN tags t ON t.id = at.tag_id
+				 WHERE at.article_id = a.id),
+				'{}'
+			)
+		FROM articles a
+		JOIN users u ON u.id = a.author_id
+		JOIN categories c ON c.id = a.category_id
+		WHERE a.status = 'published' AND a.automod_state IS NULL AND a.id > $1
+		ORDER BY a.id
+		LIMIT $2
+	`
+	
+	rows, err := r.db.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var articles []IndexableArticle
+	for rows.Next() {
+		var ia IndexableArticle
+		a := &ia.Article
+		if err := rows.Scan(
+			&a.ID, &a.Title, &a.Slug, &a.Lead, &a.Content, &a.CoverImageURL,
+			&a.Level, &a.ContentType, &a.Status, &a.ReadingTime, &a.AuthorID, &a.CategoryID,
+			&a.ViewCount, &a.CommentCount, &a.PublishedAt,
+			&ia.AuthorName, &ia.CategoryName, &ia.CategorySlug, &ia.Tags,
+		); err != nil {
+			return nil, err
+		}
+		articles = append(articles, ia)
+	}
+	
+	return articles, rows.Err()
+}
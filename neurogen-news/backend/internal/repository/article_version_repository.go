@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/neurogen-news/backend/internal/model"
+)
+
+var ErrArticleVersionNotFound = errors.New("article version not found")
+
+// ArticleVersionRepository stores the edit history of articles
+type ArticleVersionRepository interface {
+	// Create appends the version after the latest one of the article. It is
+	// called after the article row is updated in the same transaction, so
+	// the row lock orders concurrent edits.
+	Create(ctx context.Context, version *model.ArticleVersion) error
+	// List returns versions newest first, without content
+	List(ctx context.Context, articleID uuid.UUID, limit, offset int) ([]model.ArticleVersion, int, error)
+	Get(ctx context.Context, articleID uuid.UUID, version int) (*model.ArticleVersion, error)
+}
+
+type articleVersionRepository struct {
+	db *PostgresDB
+}
+
+func NewArticleVersionRepository(db *PostgresDB) ArticleVersionRepository {
+	return &articleVersionRepository{db: db}
+}
+
+func (r *articleVersionRepository) Create(ctx context.Context, version *model.ArticleVersion) error {
+	query := `
+		INSERT INTO article_versions (id, article_id, title, content, edited_by, restored_from, version, created_at)
+		SELECT $1, $2, $3, $4, $5, $6, COALESCE(MAX(version), 0) + 1, NOW()
+		FROM article_versions
+		WHERE article_id = $2
+		RETURNING version, created_at
+	`
+
+	version.ID = uuid.New()
+
+	return r.db.QueryRow(ctx, query,
+		version.ID,
+		version.ArticleID,
+		version.Title,
+		version.Content,
+		version.EditedBy,
+		version.RestoredFrom,
+	).Scan(&version.Version, &version.CreatedAt)
+}
+
+func (r *articleVersionRepository) List(ctx context.Context, articleID uuid.UUID, limit, offset int) ([]model.ArticleVersion, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM article_versions WHERE article_id = $1`, articleID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT
+			v.id, v.article_id, v.version, v.title, '', v.edited_by, v.restored_from, v.created_at,
+			v.edited_by = a.author_id,
+			u.id, u.username, u.display_name, u.avatar_url, u.is_verified
+		FROM article_versions v
+		JOIN articles a ON a.id = v.article_id
+		JOIN users u ON u.id = v.edited_by
+		WHERE v.article_id = $1
+		ORDER BY v.version DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, articleID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var versions []model.ArticleVersion
+	for rows.Next() {
+		version, err := scanArticleVersion(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		versions = append(versions, *version)
+	}
+
+	return versions, total, rows.Err()
+}
+
+func (r *articleVersionRepository) Get(ctx context.Context, articleID uuid.UUID, version int) (*model.ArticleVersion, error) {
+	query := `
+		SELECT
+			v.id, v.article_id, v.version, v.title, v.content, v.edited_by, v.restored_from, v.created_at,
+			v.edited_by = a.author_id,
+			u.id, u.username, u.display_name, u.avatar_url, u.is_verified
+		FROM article_versions v
+		JOIN articles a ON a.id = v.article_id
+		JOIN users u ON u.id = v.edited_by
+		WHERE v.article_id = $1 AND v.version = $2
+	`
+
+	v, err := scanArticleVersion(r.db.QueryRow(ctx, query, articleID, version))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrArticleVersionNotFound
+		}
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func scanArticleVersion(row pgx.Row) (*model.ArticleVersion, error) {
+	var v model.ArticleVersion
+	v.Editor = &model.CommentAuthor{}
+	err := row.Scan(
+		&v.ID,
+		&v.ArticleID,
+		&v.Version,
+		&v.Title,
+		&v.Content,
+		&v.EditedBy,
+		&v.RestoredFrom,
+		&v.CreatedAt,
+		&v.ByAuthor,
+		&v.Editor.ID,
+		&v.Editor.Username,
+		&v.Editor.DisplayName,
+		&v.Editor.AvatarURL,
+		&v.Editor.IsVerified,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
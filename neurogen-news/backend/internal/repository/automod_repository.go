@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/neurogen-news/backend/internal/model"
+)
+
+var (
+	ErrAutomodRuleNotFound = errors.New("automod rule not found")
+)
+
+type AutomodRepository interface {
+	List(ctx context.Context) ([]model.AutomodRule, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.AutomodRule, error)
+	Create(ctx context.Context, rule *model.AutomodRule) error
+	Update(ctx context.Context, rule *model.AutomodRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type automodRepository struct {
+	db *PostgresDB
+}
+
+func NewAutomodRepository(db *PostgresDB) AutomodRepository {
+	return &automodRepository{db: db}
+}
+
+const automodRuleColumns = `id, name, kind, target, action, config, priority, is_enabled, created_by, created_at, updated_at`
+
+// List returns all rules in evaluation order
+func (r *automodRepository) List(ctx context.Context) ([]model.AutomodRule, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+automodRuleColumns+` FROM automod_rules ORDER BY priority, created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []model.AutomodRule
+	for rows.Next() {
+		rule, err := scanAutomodRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (r *automodRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.AutomodRule, error) {
+	rule, err := scanAutomodRule(r.db.QueryRow(ctx, `SELECT `+automodRuleColumns+` FROM automod_rules WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAutomodRuleNotFound
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *automodRepository) Create(ctx context.Context, rule *model.AutomodRule) error {
+	query := `
+		INSERT INTO automod_rules (id, name, kind, target, action, config, priority, is_enabled, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+
+	rule.ID = uuid.New()
+
+	return r.db.QueryRow(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.Kind,
+		rule.Target,
+		rule.Action,
+		string(rule.Config),
+		rule.Priority,
+		rule.IsEnabled,
+		rule.CreatedBy,
+	).Scan(&rule.CreatedAt, &rule.UpdatedAt)
+}
+
+func (r *automodRepository) Update(ctx context.Context, rule *model.AutomodRule) error {
+	query := `
+		UPDATE automod_rules SET
+			name = $2, kind = $3, target = $4, action = $5, config = $6,
+			priority = $7, is_enabled = $8, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.Kind,
+		rule.Target,
+		rule.Action,
+		string(rule.Config),
+		rule.Priority,
+		rule.IsEnabled,
+	).Scan(&rule.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrAutomodRuleNotFound
+	}
+	return err
+}
+
+func (r *automodRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM automod_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAutomodRuleNotFound
+	}
+	return nil
+}
+
+func scanAutomodRule(row pgx.Row) (*model.AutomodRule, error) {
+	var rule model.AutomodRule
+	err := row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.Kind,
+		&rule.Target,
+		&rule.Action,
+		&rule.Config,
+		&rule.Priority,
+		&rule.IsEnabled,
+		&rule.CreatedBy,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
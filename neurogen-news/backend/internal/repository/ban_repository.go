@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/neurogen-news/backend/internal/model"
+)
+
+var ErrBanNotFound = errors.New("ban not found")
+
+type BanRepository interface {
+	Create(ctx context.Context, ban *model.UserBan) error
+	GetActive(ctx context.Context, userID uuid.UUID) (*model.UserBan, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.UserBan, int, error)
+	// RevokeActive ends every ban of the user that is still in force
+	RevokeActive(ctx context.Context, userID, revokedBy uuid.UUID) (int64, error)
+	// ClearExpired lifts users.is_banned for temporary bans that have run out
+	ClearExpired(ctx context.Context) ([]uuid.UUID, error)
+}
+
+type banRepository struct {
+	db *PostgresDB
+}
+
+func NewBanRepository(db *PostgresDB) BanRepository {
+	return &banRepository{db: db}
+}
+
+const banColumns = `id, user_id, banned_by, reason, expires_at, is_permanent, revoked_at, revoked_by, created_at`
+
+func (r *banRepository) Create(ctx context.Context, ban *model.UserBan) error {
+	query := `
+		INSERT INTO user_bans (id, user_id, banned_by, reason, expires_at, is_permanent, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING created_at
+	`
+
+	ban.ID = uuid.New()
+
+	return r.db.QueryRow(ctx, query,
+		ban.ID,
+		ban.UserID,
+		ban.BannedBy,
+		ban.Reason,
+		ban.ExpiresAt,
+		ban.IsPermanent,
+	).Scan(&ban.CreatedAt)
+}
+
+func (r *banRepository) GetActive(ctx context.Context, userID uuid.UUID) (*model.UserBan, error) {
+	query := `SELECT ` + banColumns + `
+		FROM user_bans
+		WHERE user_id = $1 AND revoked_at IS NULL AND (is_permanent OR expires_at > NOW())
+		ORDER BY is_permanent DESC, expires_at DESC
+		LIMIT 1
+	`
+
+	ban, err := scanBan(r.db.QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrBanNotFound
+		}
+		return nil, err
+	}
+
+	return ban, nil
+}
+
+func (r *banRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.UserBan, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM user_bans WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + banColumns + `
+		FROM user_bans
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var bans []model.UserBan
+	for rows.Next() {
+		ban, err := scanBan(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		bans = append(bans, *ban)
+	}
+
+	return bans, total, rows.Err()
+}
+
+func (r *banRepository) RevokeActive(ctx context.Context, userID, revokedBy uuid.UUID) (int64, error) {
+	query := `
+		UPDATE user_bans SET revoked_at = NOW(), revoked_by = $2
+		WHERE user_id = $1 AND revoked_at IS NULL AND (is_permanent OR expires_at > NOW())
+	`
+
+	tag, err := r.db.Exec(ctx, query, userID, revokedBy)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *banRepository) ClearExpired(ctx context.Context) ([]uuid.UUID, error) {
+	query := `
+		UPDATE users SET is_banned = false, ban_reason = NULL, banned_until = NULL, updated_at = NOW()
+		WHERE is_banned = true AND banned_until IS NOT NULL AND banned_until <= NOW()
+		RETURNING id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func scanBan(row pgx.Row) (*model.UserBan, error) {
+	var ban model.UserBan
+	err := row.Scan(
+		&ban.ID,
+		&ban.UserID,
+		&ban.BannedBy,
+		&ban.Reason,
+		&ban.ExpiresAt,
+		&ban.IsPermanent,
+		&ban.RevokedAt,
+		&ban.RevokedBy,
+		&ban.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &ban, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -19,22 +21,50 @@ type CommentRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Comment, error)
 	Update(ctx context.Context, comment *model.Comment) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	Hide(ctx context.Context, id uuid.UUID) error
+	ClearAutomodState(ctx context.Context, id uuid.UUID) (bool, error)
 
 	// Lists
 	GetByArticle(ctx context.Context, articleID uuid.UUID, params CommentListParams) ([]model.Comment, int, error)
-	GetReplies(ctx context.Context, parentID uuid.UUID, limit, offset int) ([]model.Comment, error)
+	GetReplies(ctx context.Context, parentID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]model.Comment, error)
 	GetByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.Comment, int, error)
 
+	// Tree
+	GetThreadPage(ctx context.Context, params CommentThreadParams) ([]model.Comment, error)
+	GetDescendants(ctx context.Context, rootIDs []uuid.UUID, viewerID *uuid.UUID, maxDepth, branchLimit, maxNodes int) ([]model.Comment, error)
+	GetDepth(ctx context.Context, id uuid.UUID) (int, error)
+	CountRoots(ctx context.Context, articleID uuid.UUID, viewerID *uuid.UUID) (int, error)
+
 	// Reactions
 	AddReaction(ctx context.Context, commentID, userID, reactionID uuid.UUID) error
 	RemoveReaction(ctx context.Context, commentID, userID uuid.UUID) error
 	GetReactions(ctx context.Context, commentID uuid.UUID, userID *uuid.UUID) ([]model.ReactionCount, error)
+	GetCommentsReactions(ctx context.Context, commentIDs []uuid.UUID, userID *uuid.UUID) (map[uuid.UUID][]model.ReactionCount, error)
 }
 
 type CommentListParams struct {
-	Sort   string // new, popular, old
-	Limit  int
-	Offset int
+	Sort     string // new, popular, old
+	ViewerID *uuid.UUID
+	Limit    int
+	Offset   int
+}
+
+// CommentThreadParams selects one page of a comment level: the article's root
+// comments, or the direct replies of ParentID
+type CommentThreadParams struct {
+	ArticleID uuid.UUID
+	ParentID  *uuid.UUID
+	Sort      string // new, popular, old; replies are always old
+	ViewerID  *uuid.UUID
+	After     *CommentCursor
+	Limit     int
+}
+
+// CommentCursor is the keyset position of the last comment of a page
+type CommentCursor struct {
+	CreatedAt  time.Time `json:"c"`
+	ReplyCount int       `json:"r,omitempty"`
+	ID         uuid.UUID `json:"i"`
 }
 
 type commentRepository struct {
@@ -47,8 +77,8 @@ func NewCommentRepository(db *PostgresDB) CommentRepository {
 
 func (r *commentRepository) Create(ctx context.Context, comment *model.Comment) error {
 	query := `
-		INSERT INTO comments (id, content, html_content, author_id, article_id, parent_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		INSERT INTO comments (id, content, html_content, author_id, article_id, parent_id, automod_state, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
 	`
 
 	comment.ID = uuid.New()
@@ -60,14 +90,15 @@ func (r *commentRepository) Create(ctx context.Context, comment *model.Comment)
 		comment.AuthorID,
 		comment.ArticleID,
 		comment.ParentID,
+		comment.AutomodState,
 	)
 
 	if err != nil {
 		return err
 	}
 
-	// Increment reply count if this is a reply
-	if comment.ParentID != nil {
+	// Increment reply count if this is a reply; hidden replies count once released
+	if comment.ParentID != nil && comment.AutomodState == nil {
 		_, _ = r.db.Exec(ctx, `UPDATE comments SET reply_count = reply_count + 1 WHERE id = $1`, *comment.ParentID)
 	}
 
@@ -78,7 +109,7 @@ func (r *commentRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.C
 	query := `
 		SELECT 
 			c.id, c.content, c.html_content, c.author_id, c.article_id, c.parent_id,
-			c.reply_count, c.is_edited, c.is_deleted, c.created_at, c.updated_at,
+			c.reply_count, c.is_edited, c.is_deleted, c.automod_state, c.created_at, c.updated_at,
 			u.id, u.username, u.display_name, u.avatar_url, u.is_verified
 		FROM comments c
 		JOIN users u ON u.id = c.author_id
@@ -98,6 +129,7 @@ func (r *commentRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.C
 		&comment.ReplyCount,
 		&comment.IsEdited,
 		&comment.IsDeleted,
+		&comment.AutomodState,
 		&comment.CreatedAt,
 		&comment.UpdatedAt,
 		&comment.Author.ID,
@@ -114,6 +146,7 @@ func (r *commentRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.C
 		return nil, err
 	}
 
+	comment.IsHeld = comment.AutomodState != nil && *comment.AutomodState == model.AutomodStateHeld
 	return &comment, nil
 }
 
@@ -151,6 +184,48 @@ func (r *commentRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+// Hide replaces the text with a placeholder and keeps the original for moderators
+func (r *commentRepository) Hide(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE comments SET
+			is_hidden = true,
+			hidden_content = content,
+			content = '[Комментарий скрыт модератором]',
+			html_content = '<p>[Комментарий скрыт модератором]</p>',
+			updated_at = NOW()
+		WHERE id = $1 AND is_hidden = false AND is_deleted = false
+	`
+
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
+
+// ClearAutomodState makes a held or shadow-hidden comment visible. It reports
+// false when the comment was not hidden by automoderation.
+func (r *commentRepository) ClearAutomodState(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE comments SET automod_state = NULL
+		WHERE id = $1 AND automod_state IS NOT NULL
+		RETURNING parent_id
+	`
+
+	var parentID *uuid.UUID
+	if err := r.db.QueryRow(ctx, query, id).Scan(&parentID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// The reply was not counted while hidden
+	if parentID != nil {
+		if _, err := r.db.Exec(ctx, `UPDATE comments SET reply_count = reply_count + 1 WHERE id = $1`, *parentID); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
 func (r *commentRepository) GetByArticle(ctx context.Context, articleID uuid.UUID, params CommentListParams) ([]model.Comment, int, error) {
 	// Set defaults
 	if params.Limit <= 0 || params.Limit > 100 {
@@ -169,9 +244,8 @@ func (r *commentRepository) GetByArticle(ctx context.Context, articleID uuid.UUI
 	}
 
 	// Count total root comments
-	countQuery := `SELECT COUNT(*) FROM comments WHERE article_id = $1 AND parent_id IS NULL`
-	var total int
-	if err := r.db.QueryRow(ctx, countQuery, articleID).Scan(&total); err != nil {
+	total, err := r.CountRoots(ctx, articleID, params.ViewerID)
+	if err != nil {
 		return nil, 0, err
 	}
 
@@ -183,12 +257,12 @@ func (r *commentRepository) GetByArticle(ctx context.Context, articleID uuid.UUI
 			u.id, u.username, u.display_name, u.avatar_url, u.is_verified
 		FROM comments c
 		JOIN users u ON u.id = c.author_id
-		WHERE c.article_id = $1 AND c.parent_id IS NULL
+		WHERE c.article_id = $1 AND c.parent_id IS NULL AND %s
 		ORDER BY %s
-		LIMIT $2 OFFSET $3
-	`, orderBy)
+		LIMIT $3 OFFSET $4
+	`, commentVisibleTo(2), orderBy)
 
-	rows, err := r.db.Query(ctx, query, articleID, params.Limit, params.Offset)
+	rows, err := r.db.Query(ctx, query, articleID, viewerArg(params.ViewerID), params.Limit, params.Offset)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -227,24 +301,24 @@ func (r *commentRepository) GetByArticle(ctx context.Context, articleID uuid.UUI
 	return comments, total, nil
 }
 
-func (r *commentRepository) GetReplies(ctx context.Context, parentID uuid.UUID, limit, offset int) ([]model.Comment, error) {
+func (r *commentRepository) GetReplies(ctx context.Context, parentID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]model.Comment, error) {
 	if limit <= 0 || limit > 100 {
 		limit = 10
 	}
 
-	query := `
+	query := fmt.Sprintf(`
 		SELECT 
 			c.id, c.content, c.html_content, c.author_id, c.article_id, c.parent_id,
 			c.reply_count, c.is_edited, c.is_deleted, c.created_at, c.updated_at,
 			u.id, u.username, u.display_name, u.avatar_url, u.is_verified
 		FROM comments c
 		JOIN users u ON u.id = c.author_id
-		WHERE c.parent_id = $1
+		WHERE c.parent_id = $1 AND %s
 		ORDER BY c.created_at ASC
-		LIMIT $2 OFFSET $3
-	`
+		LIMIT $3 OFFSET $4
+	`, commentVisibleTo(2))
 
-	rows, err := r.db.Query(ctx, query, parentID, limit, offset)
+	rows, err := r.db.Query(ctx, query, parentID, viewerArg(viewerID), limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -288,7 +362,7 @@ func (r *commentRepository) GetByUser(ctx context.Context, userID uuid.UUID, lim
 		limit = 20
 	}
 
-	countQuery := `SELECT COUNT(*) FROM comments WHERE author_id = $1 AND is_deleted = false`
+	countQuery := `SELECT COUNT(*) FROM comments WHERE author_id = $1 AND is_deleted = false AND automod_state IS NULL`
 	var total int
 	if err := r.db.QueryRow(ctx, countQuery, userID).Scan(&total); err != nil {
 		return nil, 0, err
@@ -301,7 +375,7 @@ func (r *commentRepository) GetByUser(ctx context.Context, userID uuid.UUID, lim
 			u.id, u.username, u.display_name, u.avatar_url, u.is_verified
 		FROM comments c
 		JOIN users u ON u.id = c.author_id
-		WHERE c.author_id = $1 AND c.is_deleted = false
+		WHERE c.author_id = $1 AND c.is_deleted = false AND c.automod_state IS NULL
 		ORDER BY c.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -396,3 +470,246 @@ func (r *commentRepository) GetReactions(ctx context.Context, commentID uuid.UUI
 
 	return reactions, nil
 }
+
+// Columns of a comment joined with its author, shared by the tree queries
+const commentTreeColumns = `
+	c.id, c.content, c.html_content, c.author_id, c.article_id, c.parent_id,
+	c.reply_count, c.is_edited, c.is_deleted, c.automod_state, c.created_at, c.updated_at,
+	u.id, u.username, u.display_name, u.avatar_url, u.is_verified`
+
+func scanTreeComment(rows pgx.Rows, extra ...interface{}) (model.Comment, error) {
+	var comment model.Comment
+	comment.Author = &model.CommentAuthor{}
+
+	dest := []interface{}{
+		&comment.ID,
+		&comment.Content,
+		&comment.HTMLContent,
+		&comment.AuthorID,
+		&comment.ArticleID,
+		&comment.ParentID,
+		&comment.ReplyCount,
+		&comment.IsEdited,
+		&comment.IsDeleted,
+		&comment.AutomodState,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.Author.ID,
+		&comment.Author.Username,
+		&comment.Author.DisplayName,
+		&comment.Author.AvatarURL,
+		&comment.Author.IsVerified,
+	}
+
+	err := rows.Scan(append(dest, extra...)...)
+	comment.IsHeld = comment.AutomodState != nil && *comment.AutomodState == model.AutomodStateHeld
+	return comment, err
+}
+
+// GetThreadPage returns one keyset page of root comments or of a comment's replies
+func (r *commentRepository) GetThreadPage(ctx context.Context, params CommentThreadParams) ([]model.Comment, error) {
+	var conditions []string
+	var args []interface{}
+
+	if params.ParentID != nil {
+		conditions = append(conditions, "c.parent_id = $1")
+		args = append(args, *params.ParentID)
+		params.Sort = "old"
+	} else {
+		conditions = append(conditions, "c.article_id = $1 AND c.parent_id IS NULL")
+		args = append(args, params.ArticleID)
+	}
+	conditions = append(conditions, commentVisibleTo(2))
+	args = append(args, viewerArg(params.ViewerID))
+
+	var orderBy string
+	switch params.Sort {
+	case "popular":
+		orderBy = "c.reply_count DESC, c.created_at DESC, c.id DESC"
+		if params.After != nil {
+			conditions = append(conditions, "(c.reply_count, c.created_at, c.id) < ($3, $4, $5)")
+			args = append(args, params.After.ReplyCount, params.After.CreatedAt, params.After.ID)
+		}
+	case "old":
+		orderBy = "c.created_at ASC, c.id ASC"
+		if params.After != nil {
+			conditions = append(conditions, "(c.created_at, c.id) > ($3, $4)")
+			args = append(args, params.After.CreatedAt, params.After.ID)
+		}
+	default: // new
+		orderBy = "c.created_at DESC, c.id DESC"
+		if params.After != nil {
+			conditions = append(conditions, "(c.created_at, c.id) < ($3, $4)")
+			args = append(args, params.After.CreatedAt, params.After.ID)
+		}
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM comments c
+		JOIN users u ON u.id = c.author_id
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d
+	`, commentTreeColumns, strings.Join(conditions, " AND "), orderBy, len(args)+1)
+
+	rows, err := r.db.Query(ctx, query, append(args, params.Limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []model.Comment
+	for rows.Next() {
+		comment, err := scanTreeComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// GetDescendants walks the replies below rootIDs with a recursive CTE, taking at most
+// branchLimit replies (oldest first) per comment and stopping maxDepth levels down.
+// Depth is relative to the roots (direct replies have depth 1). Results are ordered
+// by depth and then chronologically, so truncation at maxNodes drops whole tails.
+func (r *commentRepository) GetDescendants(ctx context.Context, rootIDs []uuid.UUID, viewerID *uuid.UUID, maxDepth, branchLimit, maxNodes int) ([]model.Comment, error) {
+	if len(rootIDs) == 0 || maxDepth <= 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+		WITH RECURSIVE tree AS (
+			SELECT id, 0 AS depth
+			FROM unnest($1::uuid[]) AS id
+			UNION ALL
+			SELECT child.id, tree.depth + 1
+			FROM tree
+			CROSS JOIN LATERAL (
+				SELECT ch.id
+				FROM comments ch
+				WHERE ch.parent_id = tree.id AND (ch.automod_state IS NULL OR ch.author_id = $5)
+				ORDER BY ch.created_at ASC, ch.id ASC
+				LIMIT $3
+			) child
+			WHERE tree.depth < $2
+		)
+		SELECT %s, tree.depth
+		FROM tree
+		JOIN comments c ON c.id = tree.id
+		JOIN users u ON u.id = c.author_id
+		WHERE tree.depth > 0
+		ORDER BY tree.depth, c.created_at ASC, c.id ASC
+		LIMIT $4
+	`, commentTreeColumns)
+
+	rows, err := r.db.Query(ctx, query, rootIDs, maxDepth, branchLimit, maxNodes, viewerArg(viewerID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []model.Comment
+	for rows.Next() {
+		var depth int
+		comment, err := scanTreeComment(rows, &depth)
+		if err != nil {
+			return nil, err
+		}
+		comment.Depth = depth
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// GetDepth returns how many ancestors a comment has (0 for root comments)
+func (r *commentRepository) GetDepth(ctx context.Context, id uuid.UUID) (int, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth FROM comments WHERE id = $1
+			UNION ALL
+			SELECT c.id, c.parent_id, a.depth + 1
+			FROM comments c
+			JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT MAX(depth) FROM ancestors
+	`
+
+	var depth *int
+	if err := r.db.QueryRow(ctx, query, id).Scan(&depth); err != nil {
+		return 0, err
+	}
+	if depth == nil {
+		return 0, ErrCommentNotFound
+	}
+
+	return *depth, nil
+}
+
+func (r *commentRepository) CountRoots(ctx context.Context, articleID uuid.UUID, viewerID *uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM comments c WHERE c.article_id = $1 AND c.parent_id IS NULL AND ` + commentVisibleTo(2)
+
+	var total int
+	err := r.db.QueryRow(ctx, query, articleID, viewerArg(viewerID)).Scan(&total)
+	return total, err
+}
+
+// commentVisibleTo hides held and shadow-hidden comments from everyone but
+// their author, whose ID is the query argument at argPos
+func commentVisibleTo(argPos int) string {
+	return fmt.Sprintf("(c.automod_state IS NULL OR c.author_id = $%d)", argPos)
+}
+
+// viewerArg turns an anonymous viewer into an ID no author has
+func viewerArg(viewerID *uuid.UUID) uuid.UUID {
+	if viewerID == nil {
+		return uuid.Nil
+	}
+	return *viewerID
+}
+
+// GetCommentsReactions loads reaction counts for a batch of comments
+func (r *commentRepository) GetCommentsReactions(ctx context.Context, commentIDs []uuid.UUID, userID *uuid.UUID) (map[uuid.UUID][]model.ReactionCount, error) {
+	result := make(map[uuid.UUID][]model.ReactionCount, len(commentIDs))
+	if len(commentIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT 
+			cr.comment_id,
+			rt.emoji,
+			COUNT(*) as count,
+			COALESCE(bool_or(cr.user_id = $2), false) as is_reacted
+		FROM comment_reactions cr
+		JOIN reaction_types rt ON rt.id = cr.reaction_id
+		WHERE cr.comment_id = ANY($1)
+		GROUP BY cr.comment_id, rt.emoji
+		ORDER BY cr.comment_id, count DESC
+	`
+
+	var uid interface{} = nil
+	if userID != nil {
+		uid = *userID
+	}
+
+	rows, err := r.db.Query(ctx, query, commentIDs, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var commentID uuid.UUID
+		var rc model.ReactionCount
+		if err := rows.Scan(&commentID, &rc.Emoji, &rc.Count, &rc.IsReacted); err != nil {
+			return nil, err
+		}
+		result[commentID] = append(result[commentID], rc)
+	}
+
+	return result, rows.Err()
+}
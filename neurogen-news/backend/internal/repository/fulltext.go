@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/neurogen-news/backend/internal/highlight"
+)
+
+// ts_headline options for content fragments and for whole short fields
+const (
+	headlineFragments = "StartSel=" + highlight.PreTag + ", StopSel=" + highlight.PostTag +
+		`, MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=" … "`
+	headlineAll = "StartSel=" + highlight.PreTag + ", StopSel=" + highlight.PostTag +
+		", HighlightAll=true"
+)
+
+// prefixTSQuery builds a to_tsquery expression that requires every word and
+// matches the last one as a prefix: "нейро сет" -> "нейро & сет:*".
+// Only letters and digits survive, so the result is always valid tsquery syntax.
+func prefixTSQuery(query string) string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+
+	words[len(words)-1] += ":*"
+	return strings.Join(words, " & ")
+}
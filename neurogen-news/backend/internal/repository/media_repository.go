@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/neurogen-news/backend/internal/model"
+)
+
+var ErrMediaNotFound = errors.New("media not found")
+
+// MediaRepository stores the media library and where its items are used
+type MediaRepository interface {
+	Create(ctx context.Context, media *model.Media) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Media, error)
+	// GetByBase finds the media whose original key is base plus an extension
+	GetByBase(ctx context.Context, base string) (*model.Media, error)
+	List(ctx context.Context, userID uuid.UUID, query, mediaType string, limit, offset int) ([]model.Media, int, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// DeleteUnreferenced deletes the media unless something uses it and
+	// reports whether it did
+	DeleteUnreferenced(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// References are kept current by triggers on the content that can show media
+	ListReferences(ctx context.Context, mediaIDs []uuid.UUID) ([]model.MediaReference, error)
+	// ListUnreferenced returns media created before the cutoff that nothing uses
+	ListUnreferenced(ctx context.Context, before time.Time, limit int) ([]model.Media, error)
+}
+
+type mediaRepository struct {
+	db *PostgresDB
+}
+
+func NewMediaRepository(db *PostgresDB) MediaRepository {
+	return &mediaRepository{db: db}
+}
+
+const mediaColumns = `id, user_id, key, name, type, content_type, size, width, height, created_at`
+
+func (r *mediaRepository) Create(ctx context.Context, media *model.Media) error {
+	query := `
+		INSERT INTO media (id, user_id, key, name, type, content_type, size, width, height, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		RETURNING created_at
+	`
+
+	media.ID = uuid.New()
+
+	return r.db.QueryRow(ctx, query,
+		media.ID,
+		media.UserID,
+		media.Key,
+		media.Name,
+		media.Type,
+		media.ContentType,
+		media.Size,
+		media.Width,
+		media.Height,
+	).Scan(&media.CreatedAt)
+}
+
+func (r *mediaRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Media, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE id = $1`
+
+	media, err := scanMedia(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMediaNotFound
+		}
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func (r *mediaRepository) GetByBase(ctx context.Context, base string) (*model.Media, error) {
+	// Keys are UUID paths, so base holds no LIKE wildcards
+	query := `SELECT ` + mediaColumns + ` FROM media WHERE key LIKE $1 || '.%' LIMIT 1`
+
+	media, err := scanMedia(r.db.QueryRow(ctx, query, base))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMediaNotFound
+		}
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func (r *mediaRepository) List(ctx context.Context, userID uuid.UUID, query, mediaType string, limit, offset int) ([]model.Media, int, error) {
+	where := `WHERE user_id = $1 AND ($2 = '' OR name ILIKE '%' || $2 || '%') AND ($3 = '' OR type = $3)`
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM media `+where, userID, query, mediaType).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sqlQuery := `SELECT ` + mediaColumns + `
+		FROM media ` + where + `
+		ORDER BY created_at DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.Query(ctx, sqlQuery, userID, query, mediaType, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []model.Media
+	for rows.Next() {
+		media, err := scanMedia(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, *media)
+	}
+
+	return items, total, rows.Err()
+}
+
+func (r *mediaRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM media WHERE id = $1`, id)
+	return err
+}
+
+func (r *mediaRepository) DeleteUnreferenced(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		DELETE FROM media m
+		WHERE id = $1
+			AND NOT EXISTS (SELECT 1 FROM media_references mr WHERE mr.media_id = m.id)
+	`
+
+	result, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *mediaRepository) ListReferences(ctx context.Context, mediaIDs []uuid.UUID) ([]model.MediaReference, error) {
+	query := `
+		SELECT media_id, target_type, target_id
+		FROM media_references
+		WHERE media_id = ANY($1)
+		ORDER BY target_type, target_id
+	`
+
+	rows, err := r.db.Query(ctx, query, mediaIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []model.MediaReference
+	for rows.Next() {
+		var ref model.MediaReference
+		if err := rows.Scan(&ref.MediaID, &ref.TargetType, &ref.TargetID); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
+
+func (r *mediaRepository) ListUnreferenced(ctx context.Context, before time.Time, limit int) ([]model.Media, error) {
+	query := `SELECT ` + mediaColumns + `
+		FROM media m
+		WHERE created_at < $1
+			AND NOT EXISTS (SELECT 1 FROM media_references mr WHERE mr.media_id = m.id)
+		ORDER BY created_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.Media
+	for rows.Next() {
+		media, err := scanMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *media)
+	}
+
+	return items, rows.Err()
+}
+
+func scanMedia(row pgx.Row) (*model.Media, error) {
+	var media model.Media
+	err := row.Scan(
+		&media.ID,
+		&media.UserID,
+		&media.Key,
+		&media.Name,
+		&media.Type,
+		&media.ContentType,
+		&media.Size,
+		&media.Width,
+		&media.Height,
+		&media.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
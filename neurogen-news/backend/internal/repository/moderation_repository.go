@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/neurogen-news/backend/internal/model"
+)
+
+type ModerationRepository interface {
+	CreateAction(ctx context.Context, action *model.ModerationAction) error
+	ListActions(ctx context.Context, params AuditLogParams) ([]model.ModerationAction, int, error)
+	// ListActionsBefore pages newest first by keyset, ignoring Offset, so rows
+	// inserted meanwhile neither shift nor repeat pages; nil starts at the newest
+	ListActionsBefore(ctx context.Context, params AuditLogParams, before *AuditLogCursor) ([]model.ModerationAction, error)
+}
+
+// AuditLogCursor is the position of the last entry of a page
+type AuditLogCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// AuditLogParams filters the audit log; zero values match everything
+type AuditLogParams struct {
+	ModeratorID *uuid.UUID
+	TargetType  string
+	TargetID    *uuid.UUID
+	Action      string
+	From        *time.Time
+	To          *time.Time
+	Limit       int
+	Offset      int
+}
+
+type moderationRepository struct {
+	db *PostgresDB
+}
+
+func NewModerationRepository(db *PostgresDB) ModerationRepository {
+	return &moderationRepository{db: db}
+}
+
+func (r *moderationRepository) CreateAction(ctx context.Context, action *model.ModerationAction) error {
+	query := `
+		INSERT INTO moderation_actions (id, moderator_id, target_type, target_id, action, reason, snapshot_before, snapshot_after, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING created_at
+	`
+
+	action.ID = uuid.New()
+
+	return r.db.QueryRow(ctx, query,
+		action.ID,
+		action.ModeratorID,
+		action.TargetType,
+		action.TargetID,
+		action.Action,
+		action.Reason,
+		nullJSON(action.Before),
+		nullJSON(action.After),
+	).Scan(&action.CreatedAt)
+}
+
+func (r *moderationRepository) ListActions(ctx context.Context, params AuditLogParams) ([]model.ModerationAction, int, error) {
+	where, args := auditLogFilter(params, nil)
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM moderation_actions m `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, params.Limit, params.Offset)
+	actions, err := r.queryActions(ctx, where, fmt.Sprintf("LIMIT $%d OFFSET $%d", len(args)-1, len(args)), args)
+	return actions, total, err
+}
+
+func (r *moderationRepository) ListActionsBefore(ctx context.Context, params AuditLogParams, before *AuditLogCursor) ([]model.ModerationAction, error) {
+	where, args := auditLogFilter(params, before)
+
+	args = append(args, params.Limit)
+	return r.queryActions(ctx, where, fmt.Sprintf("LIMIT $%d", len(args)), args)
+}
+
+// queryActions lists audit log entries newest first
+func (r *moderationRepository) queryActions(ctx context.Context, where, limit string, args []interface{}) ([]model.ModerationAction, error) {
+	query := fmt.Sprintf(`
+		SELECT m.id, m.moderator_id, m.target_type, m.target_id, m.action, m.reason,
+			   m.snapshot_before, m.snapshot_after, m.created_at,
+			   u.username, u.display_name, u.avatar_url
+		FROM moderation_actions m
+		LEFT JOIN users u ON u.id = m.moderator_id
+		%s
+		ORDER BY m.created_at DESC, m.id DESC
+		%s
+	`, where, limit)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var actions []model.ModerationAction
+	for rows.Next() {
+		action, err := scanModerationAction(rows)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, *action)
+	}
+
+	return actions, rows.Err()
+}
+
+// auditLogFilter builds the WHERE clause for the filters and the cursor
+func auditLogFilter(params AuditLogParams, before *AuditLogCursor) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if params.ModeratorID != nil {
+		addCondition("m.moderator_id = $%d", *params.ModeratorID)
+	}
+	if params.TargetType != "" {
+		addCondition("m.target_type = $%d", params.TargetType)
+	}
+	if params.TargetID != nil {
+		addCondition("m.target_id = $%d", *params.TargetID)
+	}
+	if params.Action != "" {
+		addCondition("m.action = $%d", params.Action)
+	}
+	if params.From != nil {
+		addCondition("m.created_at >= $%d", *params.From)
+	}
+	if params.To != nil {
+		addCondition("m.created_at < $%d", *params.To)
+	}
+	if before != nil {
+		args = append(args, before.CreatedAt, before.ID)
+		conditions = append(conditions, fmt.Sprintf("(m.created_at, m.id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	return where, args
+}
+
+func scanModerationAction(row pgx.Row) (*model.ModerationAction, error) {
+	var action model.ModerationAction
+	var username, displayName *string
+	var avatarURL *string
+
+	err := row.Scan(
+		&action.ID,
+		&action.ModeratorID,
+		&action.TargetType,
+		&action.TargetID,
+		&action.Action,
+		&action.Reason,
+		&action.Before,
+		&action.After,
+		&action.CreatedAt,
+		&username,
+		&displayName,
+		&avatarURL,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if username != nil {
+		action.Moderator = &model.NotificationActor{
+			ID:          action.ModeratorID,
+			Username:    *username,
+			DisplayName: *displayName,
+			AvatarURL:   avatarURL,
+		}
+	}
+
+	return &action, nil
+}
+
+// nullJSON stores an empty snapshot as NULL rather than as invalid JSON
+func nullJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
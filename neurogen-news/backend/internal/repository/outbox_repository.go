@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/neurogen-news/backend/internal/model"
+)
+
+var ErrOutboxEventNotFound = errors.New("outbox event not found")
+
+type OutboxRepository interface {
+	// Enqueue records an event; call it with the context of the mutation's transaction
+	Enqueue(ctx context.Context, eventType model.OutboxEventType, aggregateID uuid.UUID) error
+
+	// Dispatcher side
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, error)
+	MarkProcessed(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, errMsg string, retryAt time.Time) error
+	MarkDead(ctx context.Context, id uuid.UUID, errMsg string) error
+
+	// Dead letters, for operators
+	ListDead(ctx context.Context, limit, offset int) ([]model.OutboxEvent, int, error)
+	Requeue(ctx context.Context, id uuid.UUID) error
+	DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// Replay enqueues the aggregates of events of the given types that were
+	// processed since the given time or are still pending, once per aggregate
+	Replay(ctx context.Context, eventTypes []model.OutboxEventType, since time.Time) (int64, error)
+}
+
+type outboxRepository struct {
+	db *PostgresDB
+}
+
+func NewOutboxRepository(db *PostgresDB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Enqueue(ctx context.Context, eventType model.OutboxEventType, aggregateID uuid.UUID) error {
+	query := `
+		INSERT INTO outbox_events (id, event_type, aggregate_id, available_at, created_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+	`
+
+	_, err := r.db.Exec(ctx, query, uuid.New(), eventType, aggregateID)
+	return err
+}
+
+// Claim leases a batch of due events. SKIP LOCKED lets several dispatchers
+// (one per instance) work the table without handing out the same event twice.
+func (r *outboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, error) {
+	query := `
+		UPDATE outbox_events SET
+			locked_until = NOW() + $2 * INTERVAL '1 millisecond',
+			attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE processed_at IS NULL AND dead_at IS NULL
+				AND available_at <= NOW()
+				AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, aggregate_id, attempts, last_error, available_at, created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.OutboxEvent
+	for rows.Next() {
+		var e model.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.EventType, &e.AggregateID, &e.Attempts, &e.LastError, &e.AvailableAt, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+func (r *outboxRepository) MarkProcessed(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE outbox_events SET processed_at = NOW(), locked_until = NULL, last_error = NULL WHERE id = $1`
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, errMsg string, retryAt time.Time) error {
+	query := `UPDATE outbox_events SET last_error = $2, available_at = $3, locked_until = NULL WHERE id = $1`
+	_, err := r.db.Exec(ctx, query, id, errMsg, retryAt)
+	return err
+}
+
+func (r *outboxRepository) MarkDead(ctx context.Context, id uuid.UUID, errMsg string) error {
+	query := `UPDATE outbox_events SET last_error = $2, dead_at = NOW(), locked_until = NULL WHERE id = $1`
+	_, err := r.db.Exec(ctx, query, id, errMsg)
+	return err
+}
+
+func (r *outboxRepository) ListDead(ctx context.Context, limit, offset int) ([]model.OutboxEvent, int, error) {
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM outbox_events WHERE dead_at IS NOT NULL`).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, event_type, aggregate_id, attempts, last_error, available_at, dead_at, created_at
+		FROM outbox_events
+		WHERE dead_at IS NOT NULL
+		ORDER BY dead_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var events []model.OutboxEvent
+	for rows.Next() {
+		var e model.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.EventType, &e.AggregateID, &e.Attempts, &e.LastError, &e.AvailableAt, &e.DeadAt, &e.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		events = append(events, e)
+	}
+
+	return events, total, rows.Err()
+}
+
+// Requeue gives a dead-lettered event a fresh set of attempts
+func (r *outboxRepository) Requeue(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE outbox_events SET dead_at = NULL, attempts = 0, available_at = NOW(), locked_until = NULL
+		WHERE id = $1 AND dead_at IS NOT NULL
+	`
+	tag, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrOutboxEventNotFound
+	}
+	return nil
+}
+
+func (r *outboxRepository) DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM outbox_events WHERE processed_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *outboxRepository) Replay(ctx context.Context, eventTypes []model.OutboxEventType, since time.Time) (int64, error) {
+	types := make([]string, len(eventTypes))
+	for i, t := range eventTypes {
+		types[i] = string(t)
+	}
+
+	// Pending events are included because one may be in flight right now
+	query := `
+		INSERT INTO outbox_events (id, event_type, aggregate_id, available_at, created_at)
+		SELECT gen_random_uuid(), event_type, aggregate_id, NOW(), NOW()
+		FROM (
+			SELECT DISTINCT event_type, aggregate_id
+			FROM outbox_events
+			WHERE event_type = ANY($1) AND dead_at IS NULL
+				AND (processed_at IS NULL OR processed_at >= $2)
+		) e
+	`
+	tag, err := r.db.Exec(ctx, query, types, since)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
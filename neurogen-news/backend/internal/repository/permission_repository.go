@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/neurogen-news/backend/internal/model"
+)
+
+var ErrGrantNotFound = errors.New("category grant not found")
+
+// PermissionRepository stores per-category moderator grants; role
+// permissions are defined in code
+type PermissionRepository interface {
+	Grant(ctx context.Context, grant *model.CategoryModerator) error
+	Revoke(ctx context.Context, userID, categoryID uuid.UUID) (*model.CategoryModerator, error)
+	IsCategoryModerator(ctx context.Context, userID, categoryID uuid.UUID) (bool, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]model.CategoryModerator, error)
+	ListByCategory(ctx context.Context, categoryID uuid.UUID) ([]model.CategoryModerator, error)
+}
+
+type permissionRepository struct {
+	db *PostgresDB
+}
+
+func NewPermissionRepository(db *PostgresDB) PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+const categoryModeratorColumns = `
+	cm.user_id, cm.category_id, cm.granted_by, cm.created_at,
+	u.username, u.display_name, u.avatar_url, c.name
+`
+
+const categoryModeratorJoins = `
+	FROM category_moderators cm
+	JOIN users u ON u.id = cm.user_id
+	JOIN categories c ON c.id = cm.category_id
+`
+
+// Grant is idempotent; granting again keeps the original grant
+func (r *permissionRepository) Grant(ctx context.Context, grant *model.CategoryModerator) error {
+	query := `
+		INSERT INTO category_moderators (user_id, category_id, granted_by, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id, category_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		RETURNING granted_by, created_at
+	`
+
+	return r.db.QueryRow(ctx, query, grant.UserID, grant.CategoryID, grant.GrantedBy).
+		Scan(&grant.GrantedBy, &grant.CreatedAt)
+}
+
+func (r *permissionRepository) Revoke(ctx context.Context, userID, categoryID uuid.UUID) (*model.CategoryModerator, error) {
+	query := `
+		DELETE FROM category_moderators
+		WHERE user_id = $1 AND category_id = $2
+		RETURNING user_id, category_id, granted_by, created_at
+	`
+
+	var grant model.CategoryModerator
+	err := r.db.QueryRow(ctx, query, userID, categoryID).Scan(
+		&grant.UserID,
+		&grant.CategoryID,
+		&grant.GrantedBy,
+		&grant.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrGrantNotFound
+		}
+		return nil, err
+	}
+
+	return &grant, nil
+}
+
+func (r *permissionRepository) IsCategoryModerator(ctx context.Context, userID, categoryID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM category_moderators WHERE user_id = $1 AND category_id = $2)`
+
+	var exists bool
+	err := r.db.QueryRow(ctx, query, userID, categoryID).Scan(&exists)
+	return exists, err
+}
+
+func (r *permissionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]model.CategoryModerator, error) {
+	query := `SELECT ` + categoryModeratorColumns + categoryModeratorJoins + `
+		WHERE cm.user_id = $1
+		ORDER BY c.name
+	`
+	return r.list(ctx, query, userID)
+}
+
+func (r *permissionRepository) ListByCategory(ctx context.Context, categoryID uuid.UUID) ([]model.CategoryModerator, error) {
+	query := `SELECT ` + categoryModeratorColumns + categoryModeratorJoins + `
+		WHERE cm.category_id = $1
+		ORDER BY cm.created_at
+	`
+	return r.list(ctx, query, categoryID)
+}
+
+func (r *permissionRepository) list(ctx context.Context, query string, id uuid.UUID) ([]model.CategoryModerator, error) {
+	rows, err := r.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grants := []model.CategoryModerator{}
+	for rows.Next() {
+		var grant model.CategoryModerator
+		var user model.NotificationActor
+		if err := rows.Scan(
+			&grant.UserID,
+			&grant.CategoryID,
+			&grant.GrantedBy,
+			&grant.CreatedAt,
+			&user.Username,
+			&user.DisplayName,
+			&user.AvatarURL,
+			&grant.CategoryName,
+		); err != nil {
+			return nil, err
+		}
+		user.ID = grant.UserID
+		grant.User = &user
+		grants = append(grants, grant)
+	}
+
+	return grants, rows.Err()
+}
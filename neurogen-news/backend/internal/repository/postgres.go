@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -11,6 +13,15 @@ type PostgresDB struct {
 	*pgxpool.Pool
 }
 
+// Transactor runs a function inside a database transaction
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+type txHooksKey struct{}
+
 func NewPostgresDB(databaseURL string) (*PostgresDB, error) {
 	config, err := pgxpool.ParseConfig(databaseURL)
 	if err != nil {
@@ -44,3 +55,65 @@ func (db *PostgresDB) Close() {
 	db.Pool.Close()
 }
 
+// WithTx runs fn inside a transaction. Repository calls made with the context
+// passed to fn join the transaction; nested calls reuse the outer one.
+func (db *PostgresDB) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) // no-op after commit
+
+	var hooks []func(ctx context.Context)
+	txCtx := context.WithValue(context.WithValue(ctx, txKey{}, tx), txHooksKey{}, &hooks)
+	if err := fn(txCtx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		hook(ctx)
+	}
+	return nil
+}
+
+// AfterCommit defers fn until the outermost transaction bound to ctx commits,
+// and drops it on rollback. Side effects outside the database (cache, push
+// notifications) go through it. Without a transaction fn runs right away.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if hooks, ok := ctx.Value(txHooksKey{}).(*[]func(ctx context.Context)); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+	fn(ctx)
+}
+
+// Exec runs a statement in the transaction bound to ctx, if any
+func (db *PostgresDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx.Exec(ctx, sql, args...)
+	}
+	return db.Pool.Exec(ctx, sql, args...)
+}
+
+// Query runs a query in the transaction bound to ctx, if any
+func (db *PostgresDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx.Query(ctx, sql, args...)
+	}
+	return db.Pool.Query(ctx, sql, args...)
+}
+
+// QueryRow runs a single-row query in the transaction bound to ctx, if any
+func (db *PostgresDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx.QueryRow(ctx, sql, args...)
+	}
+	return db.Pool.QueryRow(ctx, sql, args...)
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/neurogen-news/backend/internal/model"
+)
+
+// QuotaRepository reads upload usage, which triggers on media keep current,
+// and stores per-user quota overrides
+type QuotaRepository interface {
+	// GetUsage returns zero usage for users who never uploaded
+	GetUsage(ctx context.Context, userID uuid.UUID) (*model.UploadUsage, error)
+	// LockUsage reads the usage and locks it until the transaction ends, so
+	// reservations made under the lock see each other
+	LockUsage(ctx context.Context, userID uuid.UUID) (*model.UploadUsage, error)
+	// Reserve counts size bytes and one upload against the quota until the
+	// reservation is released or expires
+	Reserve(ctx context.Context, userID uuid.UUID, size int64, expiresAt time.Time) (uuid.UUID, error)
+	Release(ctx context.Context, id uuid.UUID) error
+	// SetOverride replaces both overrides; nil restores the role default
+	SetOverride(ctx context.Context, userID uuid.UUID, maxBytes *int64, maxDailyUploads *int) error
+}
+
+type quotaRepository struct {
+	db *PostgresDB
+}
+
+func NewQuotaRepository(db *PostgresDB) QuotaRepository {
+	return &quotaRepository{db: db}
+}
+
+// usageQuery reads usage with today's uploads (UTC) and active reservations
+const usageQuery = `
+	SELECT u.user_id, u.used_bytes, u.file_count,
+		CASE WHEN u.usage_date = (NOW() AT TIME ZONE 'UTC')::date THEN u.uploads_today ELSE 0 END,
+		COALESCE(r.bytes, 0), COALESCE(r.uploads, 0),
+		u.max_bytes, u.max_daily_uploads, u.updated_at
+	FROM upload_usage u
+	LEFT JOIN LATERAL (
+		SELECT SUM(size) AS bytes, COUNT(*) AS uploads
+		FROM upload_reservations
+		WHERE user_id = u.user_id AND expires_at > NOW()
+	) r ON TRUE
+	WHERE u.user_id = $1
+`
+
+func (r *quotaRepository) GetUsage(ctx context.Context, userID uuid.UUID) (*model.UploadUsage, error) {
+	usage, err := scanUsage(r.db.QueryRow(ctx, usageQuery, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &model.UploadUsage{UserID: userID}, nil
+		}
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+func (r *quotaRepository) LockUsage(ctx context.Context, userID uuid.UUID) (*model.UploadUsage, error) {
+	// The row is created first so there is always something to lock, and
+	// read after locking so the read sees reservations committed meanwhile
+	if _, err := r.db.Exec(ctx, `INSERT INTO upload_usage (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING`, userID); err != nil {
+		return nil, err
+	}
+	if _, err := r.db.Exec(ctx, `SELECT 1 FROM upload_usage WHERE user_id = $1 FOR UPDATE`, userID); err != nil {
+		return nil, err
+	}
+	return scanUsage(r.db.QueryRow(ctx, usageQuery, userID))
+}
+
+func (r *quotaRepository) Reserve(ctx context.Context, userID uuid.UUID, size int64, expiresAt time.Time) (uuid.UUID, error) {
+	if _, err := r.db.Exec(ctx, `DELETE FROM upload_reservations WHERE user_id = $1 AND expires_at <= NOW()`, userID); err != nil {
+		return uuid.Nil, err
+	}
+
+	id := uuid.New()
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO upload_reservations (id, user_id, size, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`, id, userID, size, expiresAt)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+func (r *quotaRepository) Release(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM upload_reservations WHERE id = $1`, id)
+	return err
+}
+
+func (r *quotaRepository) SetOverride(ctx context.Context, userID uuid.UUID, maxBytes *int64, maxDailyUploads *int) error {
+	query := `
+		INSERT INTO upload_usage (user_id, max_bytes, max_daily_uploads)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			max_bytes = EXCLUDED.max_bytes,
+			max_daily_uploads = EXCLUDED.max_daily_uploads,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.Exec(ctx, query, userID, maxBytes, maxDailyUploads)
+	return err
+}
+
+func scanUsage(row pgx.Row) (*model.UploadUsage, error) {
+	var usage model.UploadUsage
+	err := row.Scan(
+		&usage.UserID,
+		&usage.UsedBytes,
+		&usage.FileCount,
+		&usage.UploadsToday,
+		&usage.ReservedBytes,
+		&usage.ReservedUploads,
+		&usage.MaxBytes,
+		&usage.MaxDailyUploads,
+		&usage.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
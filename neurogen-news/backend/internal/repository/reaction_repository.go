@@ -23,6 +23,7 @@ type ReactionRepository interface {
 	AddArticleReaction(ctx context.Context, articleID, userID, reactionID uuid.UUID) error
 	RemoveArticleReaction(ctx context.Context, articleID, userID uuid.UUID) error
 	GetArticleReactions(ctx context.Context, articleID uuid.UUID, userID *uuid.UUID) ([]model.ReactionCount, error)
+	GetArticlesReactions(ctx context.Context, articleIDs []uuid.UUID, userID *uuid.UUID) (map[uuid.UUID][]model.ReactionCount, error)
 	GetUserArticleReaction(ctx context.Context, articleID, userID uuid.UUID) (*model.ReactionType, error)
 }
 
@@ -140,6 +141,49 @@ func (r *reactionRepository) GetArticleReactions(ctx context.Context, articleID
 	return reactions, nil
 }
 
+// GetArticlesReactions loads reaction counts for a batch of articles (feed cards)
+func (r *reactionRepository) GetArticlesReactions(ctx context.Context, articleIDs []uuid.UUID, userID *uuid.UUID) (map[uuid.UUID][]model.ReactionCount, error) {
+	result := make(map[uuid.UUID][]model.ReactionCount, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return result, nil
+	}
+	
+	query := `
+		SELECT 
+			ar.article_id,
+			rt.emoji,
+			COUNT(*) as count,
+			COALESCE(bool_or(ar.user_id = $2), false) as is_reacted
+		FROM article_reactions ar
+		JOIN reaction_types rt ON rt.id = ar.reaction_id
+		WHERE ar.article_id = ANY($1)
+		GROUP BY ar.article_id, rt.emoji
+		ORDER BY ar.article_id, count DESC
+	`
+	
+	var uid interface{} = nil
+	if userID != nil {
+		uid = *userID
+	}
+	
+	rows, err := r.db.Query(ctx, query, articleIDs, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	for rows.Next() {
+		var articleID uuid.UUID
+		var rc model.ReactionCount
+		if err := rows.Scan(&articleID, &rc.Emoji, &rc.Count, &rc.IsReacted); err != nil {
+			return nil, err
+		}
+		result[articleID] = append(result[articleID], rc)
+	}
+	
+	return result, rows.Err()
+}
+
 func (r *reactionRepository) GetUserArticleReaction(ctx context.Context, articleID, userID uuid.UUID) (*model.ReactionType, error) {
 	query := `
 		SELECT rt.id, rt.emoji, rt.name
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/neurogen-news/backend/internal/model"
+)
+
+var (
+	ErrReportNotFound = errors.New("report not found")
+	ErrReportExists   = errors.New("report already exists")
+)
+
+type ReportRepository interface {
+	Create(ctx context.Context, report *model.Report) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Report, error)
+	List(ctx context.Context, params ReportListParams) ([]model.Report, int, error)
+
+	// Queue handling
+	Claim(ctx context.Context, id, moderatorID uuid.UUID) (bool, error)
+	Assign(ctx context.Context, id uuid.UUID, assigneeID *uuid.UUID) (bool, error)
+	Resolve(ctx context.Context, resolution ReportResolution) (int64, error)
+}
+
+// ReportListParams filters the moderator queue; empty fields match everything
+type ReportListParams struct {
+	Status     string
+	TargetType string
+	Reason     string
+	AssignedTo *uuid.UUID
+	Unassigned bool
+	Limit      int
+	Offset     int
+}
+
+// ReportResolution closes a pending report
+type ReportResolution struct {
+	ID         uuid.UUID
+	Status     string // resolved or dismissed
+	Resolution *model.ModerationActionType
+	Note       *string
+	ResolvedBy uuid.UUID
+
+	// IncludeTarget also closes the other pending reports about the same target
+	IncludeTarget bool
+}
+
+type reportRepository struct {
+	db *PostgresDB
+}
+
+func NewReportRepository(db *PostgresDB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+const reportColumns = `
+	r.id, r.reporter_id, r.target_type, r.target_id, r.reason, r.description, r.status,
+	r.assigned_to, r.assigned_at, r.resolution, r.resolution_note, r.resolved_by, r.resolved_at, r.created_at,
+	u.id, u.username, u.display_name, u.avatar_url`
+
+func (r *reportRepository) Create(ctx context.Context, report *model.Report) error {
+	query := `
+		INSERT INTO reports (id, reporter_id, target_type, target_id, reason, description, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, 'pending', NOW())
+		RETURNING created_at
+	`
+
+	report.ID = uuid.New()
+	report.Status = model.ReportStatusPending
+
+	err := r.db.QueryRow(ctx, query,
+		report.ID,
+		report.ReporterID,
+		report.TargetType,
+		report.TargetID,
+		report.Reason,
+		report.Description,
+	).Scan(&report.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrReportExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *reportRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Report, error) {
+	query := `SELECT ` + reportColumns + `
+		FROM reports r
+		LEFT JOIN users u ON u.id = r.reporter_id
+		WHERE r.id = $1
+	`
+
+	report, err := scanReport(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReportNotFound
+		}
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (r *reportRepository) List(ctx context.Context, params ReportListParams) ([]model.Report, int, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if params.Status != "" {
+		addCondition("r.status = $%d", params.Status)
+	}
+	if params.TargetType != "" {
+		addCondition("r.target_type = $%d", params.TargetType)
+	}
+	if params.Reason != "" {
+		addCondition("r.reason = $%d", params.Reason)
+	}
+	if params.AssignedTo != nil {
+		addCondition("r.assigned_to = $%d", *params.AssignedTo)
+	} else if params.Unassigned {
+		conditions = append(conditions, "r.assigned_to IS NULL")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM reports r ` + where
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// The open queue is worked oldest first, closed reports are browsed newest first
+	orderBy := "r.created_at DESC, r.id DESC"
+	if params.Status == model.ReportStatusPending {
+		orderBy = "r.created_at ASC, r.id ASC"
+	}
+
+	args = append(args, params.Limit, params.Offset)
+	query := fmt.Sprintf(`SELECT %s
+		FROM reports r
+		LEFT JOIN users u ON u.id = r.reporter_id
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, reportColumns, where, orderBy, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var reports []model.Report
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reports = append(reports, *report)
+	}
+
+	return reports, total, rows.Err()
+}
+
+// Claim assigns a pending report to the moderator unless someone else already holds it
+func (r *reportRepository) Claim(ctx context.Context, id, moderatorID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE reports SET assigned_to = $2, assigned_at = NOW()
+		WHERE id = $1 AND status = 'pending' AND (assigned_to IS NULL OR assigned_to = $2)
+	`
+
+	tag, err := r.db.Exec(ctx, query, id, moderatorID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Assign hands a pending report to another moderator; a nil assignee releases it
+func (r *reportRepository) Assign(ctx context.Context, id uuid.UUID, assigneeID *uuid.UUID) (bool, error) {
+	query := `
+		UPDATE reports SET
+			assigned_to = $2,
+			assigned_at = CASE WHEN $2::uuid IS NULL THEN NULL ELSE NOW() END
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	tag, err := r.db.Exec(ctx, query, id, assigneeID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Resolve closes the report and returns the number of reports closed
+func (r *reportRepository) Resolve(ctx context.Context, res ReportResolution) (int64, error) {
+	query := `
+		UPDATE reports SET
+			status = $2,
+			resolution = $3,
+			resolution_note = $4,
+			resolved_by = $5,
+			resolved_at = NOW()
+		WHERE status = 'pending' AND (
+			id = $1 OR ($6 AND (target_type, target_id) = (SELECT target_type, target_id FROM reports WHERE id = $1))
+		)
+	`
+
+	tag, err := r.db.Exec(ctx, query, res.ID, res.Status, res.Resolution, res.Note, res.ResolvedBy, res.IncludeTarget)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanReport(row pgx.Row) (*model.Report, error) {
+	var report model.Report
+	var reporterID *uuid.UUID
+	var reporterUsername, reporterDisplayName *string
+	var reporterAvatar *string
+
+	err := row.Scan(
+		&report.ID,
+		&report.ReporterID,
+		&report.TargetType,
+		&report.TargetID,
+		&report.Reason,
+		&report.Description,
+		&report.Status,
+		&report.AssignedTo,
+		&report.AssignedAt,
+		&report.Resolution,
+		&report.ResolutionNote,
+		&report.ResolvedBy,
+		&report.ResolvedAt,
+		&report.CreatedAt,
+		&reporterID,
+		&reporterUsername,
+		&reporterDisplayName,
+		&reporterAvatar,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if reporterID != nil {
+		report.Reporter = &model.NotificationActor{
+			ID:          *reporterID,
+			Username:    *reporterUsername,
+			DisplayName: *reporterDisplayName,
+			AvatarURL:   reporterAvatar,
+		}
+	}
+
+	return &report, nil
+}
@@ -11,6 +11,21 @@ type Repositories struct {
 	Bookmark     BookmarkRepository
 	Draft        DraftRepository
 	Reaction     ReactionRepository
+	Outbox       OutboxRepository
+	Stats        StatsRepository
+	Report       ReportRepository
+	Moderation   ModerationRepository
+	Ban          BanRepository
+	Automod      AutomodRepository
+	Settings     SettingsRepository
+	Permission   PermissionRepository
+	Upload       UploadRepository
+	Media        MediaRepository
+	Quota        QuotaRepository
+	Version      ArticleVersionRepository
+
+	// Tx groups repository calls into one database transaction
+	Tx Transactor
 }
 
 func NewRepositories(db *PostgresDB) *Repositories {
@@ -25,6 +40,19 @@ func NewRepositories(db *PostgresDB) *Repositories {
 		Bookmark:     NewBookmarkRepository(db),
 		Draft:        NewDraftRepository(db),
 		Reaction:     NewReactionRepository(db),
+		Outbox:       NewOutboxRepository(db),
+		Stats:        NewStatsRepository(db),
+		Report:       NewReportRepository(db),
+		Moderation:   NewModerationRepository(db),
+		Ban:          NewBanRepository(db),
+		Automod:      NewAutomodRepository(db),
+		Settings:     NewSettingsRepository(db),
+		Permission:   NewPermissionRepository(db),
+		Upload:       NewUploadRepository(db),
+		Media:        NewMediaRepository(db),
+		Quota:        NewQuotaRepository(db),
+		Version:      NewArticleVersionRepository(db),
+		Tx:           db,
 	}
 }
 
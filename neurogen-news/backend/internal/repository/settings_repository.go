@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/neurogen-news/backend/internal/model"
+)
+
+type SettingsRepository interface {
+	List(ctx context.Context) ([]model.Setting, error)
+	Set(ctx context.Context, setting *model.Setting) error
+
+	// History
+	AddChange(ctx context.Context, change *model.SettingChange) error
+	ListChanges(ctx context.Context, key string, limit, offset int) ([]model.SettingChange, int, error)
+}
+
+type settingsRepository struct {
+	db *PostgresDB
+}
+
+func NewSettingsRepository(db *PostgresDB) SettingsRepository {
+	return &settingsRepository{db: db}
+}
+
+func (r *settingsRepository) List(ctx context.Context) ([]model.Setting, error) {
+	rows, err := r.db.Query(ctx, `SELECT key, value, updated_by, updated_at FROM platform_settings ORDER BY key`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settings []model.Setting
+	for rows.Next() {
+		var s model.Setting
+		if err := rows.Scan(&s.Key, &s.Value, &s.UpdatedBy, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		settings = append(settings, s)
+	}
+
+	return settings, rows.Err()
+}
+
+func (r *settingsRepository) Set(ctx context.Context, setting *model.Setting) error {
+	query := `
+		INSERT INTO platform_settings (key, value, updated_by, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			value = EXCLUDED.value,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(ctx, query, setting.Key, string(setting.Value), setting.UpdatedBy).Scan(&setting.UpdatedAt)
+}
+
+func (r *settingsRepository) AddChange(ctx context.Context, change *model.SettingChange) error {
+	query := `
+		INSERT INTO platform_settings_history (id, key, old_value, new_value, changed_by, changed_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING changed_at
+	`
+
+	change.ID = uuid.New()
+
+	return r.db.QueryRow(ctx, query,
+		change.ID,
+		change.Key,
+		nullJSON(change.OldValue),
+		string(change.NewValue),
+		change.ChangedBy,
+	).Scan(&change.ChangedAt)
+}
+
+// ListChanges returns the history newest first; an empty key lists all settings
+func (r *settingsRepository) ListChanges(ctx context.Context, key string, limit, offset int) ([]model.SettingChange, int, error) {
+	where := ""
+	var args []interface{}
+	if key != "" {
+		where = "WHERE h.key = $1"
+		args = append(args, key)
+	}
+
+	var total int
+	if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM platform_settings_history h `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT h.id, h.key, h.old_value, h.new_value, h.changed_by, h.changed_at,
+			   u.username, u.display_name, u.avatar_url
+		FROM platform_settings_history h
+		LEFT JOIN users u ON u.id = h.changed_by
+		%s
+		ORDER BY h.changed_at DESC, h.id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var changes []model.SettingChange
+	for rows.Next() {
+		var c model.SettingChange
+		var username, displayName, avatarURL *string
+
+		err := rows.Scan(
+			&c.ID, &c.Key, &c.OldValue, &c.NewValue, &c.ChangedBy, &c.ChangedAt,
+			&username, &displayName, &avatarURL,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if username != nil && c.ChangedBy != nil {
+			c.Changer = &model.NotificationActor{
+				ID:          *c.ChangedBy,
+				Username:    *username,
+				DisplayName: *displayName,
+				AvatarURL:   avatarURL,
+			}
+		}
+		changes = append(changes, c)
+	}
+
+	return changes, total, rows.Err()
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/neurogen-news/backend/internal/model"
+)
+
+type StatsRepository interface {
+	GetTotals(ctx context.Context) (*model.PlatformTotals, error)
+	// GetDailySeries returns one row per UTC day from the day of since through today.
+	// Views are not stored in Postgres and are left at zero.
+	GetDailySeries(ctx context.Context, since time.Time) ([]model.DailyStats, error)
+}
+
+type statsRepository struct {
+	db *PostgresDB
+}
+
+func NewStatsRepository(db *PostgresDB) StatsRepository {
+	return &statsRepository{db: db}
+}
+
+func (r *statsRepository) GetTotals(ctx context.Context) (*model.PlatformTotals, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM users),
+			(SELECT COUNT(*) FROM articles WHERE status = 'published'),
+			(SELECT COUNT(*) FROM comments WHERE is_deleted = false),
+			(SELECT COUNT(*) FROM reports WHERE status = 'pending')
+	`
+
+	var totals model.PlatformTotals
+	err := r.db.QueryRow(ctx, query).Scan(
+		&totals.TotalUsers,
+		&totals.TotalArticles,
+		&totals.TotalComments,
+		&totals.PendingReports,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &totals, nil
+}
+
+func (r *statsRepository) GetDailySeries(ctx context.Context, since time.Time) ([]model.DailyStats, error) {
+	query := `
+		WITH days AS (
+			SELECT generate_series(
+				($1::timestamptz AT TIME ZONE 'UTC')::date,
+				(NOW() AT TIME ZONE 'UTC')::date,
+				INTERVAL '1 day'
+			)::date AS day
+		),
+		signups AS (
+			SELECT (created_at AT TIME ZONE 'UTC')::date AS day, COUNT(*) AS n
+			FROM users WHERE created_at >= $1 GROUP BY 1
+		),
+		publications AS (
+			SELECT (published_at AT TIME ZONE 'UTC')::date AS day, COUNT(*) AS n
+			FROM articles WHERE status = 'published' AND published_at >= $1 GROUP BY 1
+		),
+		new_comments AS (
+			SELECT (created_at AT TIME ZONE 'UTC')::date AS day, COUNT(*) AS n
+			FROM comments WHERE created_at >= $1 GROUP BY 1
+		),
+		reports_opened AS (
+			SELECT (created_at AT TIME ZONE 'UTC')::date AS day, COUNT(*) AS n
+			FROM reports WHERE created_at >= $1 GROUP BY 1
+		),
+		reports_closed AS (
+			SELECT (resolved_at AT TIME ZONE 'UTC')::date AS day, COUNT(*) AS n
+			FROM reports WHERE resolved_at >= $1 GROUP BY 1
+		)
+		SELECT to_char(d.day, 'YYYY-MM-DD'),
+			COALESCE(s.n, 0), COALESCE(p.n, 0), COALESCE(c.n, 0),
+			COALESCE(ro.n, 0), COALESCE(rc.n, 0)
+		FROM days d
+		LEFT JOIN signups s ON s.day = d.day
+		LEFT JOIN publications p ON p.day = d.day
+		LEFT JOIN new_comments c ON c.day = d.day
+		LEFT JOIN reports_opened ro ON ro.day = d.day
+		LEFT JOIN reports_closed rc ON rc.day = d.day
+		ORDER BY d.day
+	`
+
+	rows, err := r.db.Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var series []model.DailyStats
+	for rows.Next() {
+		var day model.DailyStats
+		if err := rows.Scan(
+			&day.Date,
+			&day.Signups,
+			&day.Publications,
+			&day.Comments,
+			&day.ReportsOpened,
+			&day.ReportsClosed,
+		); err != nil {
+			return nil, err
+		}
+		series = append(series, day)
+	}
+
+	return series, rows.Err()
+}
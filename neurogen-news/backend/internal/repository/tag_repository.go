@@ -24,6 +24,7 @@ type TagRepository interface {
 	
 	GetPopular(ctx context.Context, limit int) ([]model.Tag, error)
 	Search(ctx context.Context, query string, limit int) ([]model.Tag, error)
+	ListForIndex(ctx context.Context, afterID uuid.UUID, limit int) ([]model.Tag, error)
 }
 
 type tagRepository struct {
@@ -200,3 +201,30 @@ func (r *tagRepository) Search(ctx context.Context, query string, limit int) ([]
 	return tags, nil
 }
 
+// ListForIndex returns tags ordered by ID, starting after afterID
+func (r *tagRepository) ListForIndex(ctx context.Context, afterID uuid.UUID, limit int) ([]model.Tag, error) {
+	query := `
+		SELECT id, name, slug, article_count, created_at
+		FROM tags
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+	
+	rows, err := r.db.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var tags []model.Tag
+	for rows.Next() {
+		var tag model.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Slug, &tag.ArticleCount, &tag.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	
+	return tags, rows.Err()
+}
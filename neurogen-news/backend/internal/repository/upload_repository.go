@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/neurogen-news/backend/internal/model"
+)
+
+var ErrUploadNotFound = errors.New("upload not found")
+
+// UploadRepository tracks presigned direct uploads
+type UploadRepository interface {
+	Create(ctx context.Context, upload *model.DirectUpload) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.DirectUpload, error)
+	// Claim reserves a pending upload of the user for one completion. Uploads
+	// that expired or were claimed before the cutoff count as missing.
+	Claim(ctx context.Context, id, userID uuid.UUID, cutoff time.Time) (*model.DirectUpload, error)
+	// Release drops the claim so the upload can be completed again
+	Release(ctx context.Context, id uuid.UUID) error
+	// Complete marks a claimed upload as completed
+	Complete(ctx context.Context, id uuid.UUID) (*model.DirectUpload, error)
+	// ListExpired returns pending uploads that expired before the cutoff and
+	// are not being completed
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]model.DirectUpload, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type uploadRepository struct {
+	db *PostgresDB
+}
+
+func NewUploadRepository(db *PostgresDB) UploadRepository {
+	return &uploadRepository{db: db}
+}
+
+const uploadColumns = `id, user_id, key, type, content_type, size, expires_at, claimed_at, completed_at, created_at`
+
+func (r *uploadRepository) Create(ctx context.Context, upload *model.DirectUpload) error {
+	query := `
+		INSERT INTO direct_uploads (id, user_id, key, type, content_type, size, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING created_at
+	`
+
+	upload.ID = uuid.New()
+
+	return r.db.QueryRow(ctx, query,
+		upload.ID,
+		upload.UserID,
+		upload.Key,
+		upload.Type,
+		upload.ContentType,
+		upload.Size,
+		upload.ExpiresAt,
+	).Scan(&upload.CreatedAt)
+}
+
+func (r *uploadRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.DirectUpload, error) {
+	query := `SELECT ` + uploadColumns + ` FROM direct_uploads WHERE id = $1`
+
+	upload, err := scanUpload(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+func (r *uploadRepository) Claim(ctx context.Context, id, userID uuid.UUID, cutoff time.Time) (*model.DirectUpload, error) {
+	query := `
+		UPDATE direct_uploads SET claimed_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND completed_at IS NULL AND expires_at > $3
+			AND (claimed_at IS NULL OR claimed_at <= $3)
+		RETURNING ` + uploadColumns
+
+	upload, err := scanUpload(r.db.QueryRow(ctx, query, id, userID, cutoff))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+func (r *uploadRepository) Release(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `UPDATE direct_uploads SET claimed_at = NULL WHERE id = $1 AND completed_at IS NULL`, id)
+	return err
+}
+
+func (r *uploadRepository) Complete(ctx context.Context, id uuid.UUID) (*model.DirectUpload, error) {
+	query := `
+		UPDATE direct_uploads SET completed_at = NOW()
+		WHERE id = $1 AND completed_at IS NULL
+		RETURNING ` + uploadColumns
+
+	upload, err := scanUpload(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+func (r *uploadRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]model.DirectUpload, error) {
+	query := `SELECT ` + uploadColumns + `
+		FROM direct_uploads
+		WHERE completed_at IS NULL AND expires_at <= $1
+			AND (claimed_at IS NULL OR claimed_at <= $1)
+		ORDER BY expires_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var uploads []model.DirectUpload
+	for rows.Next() {
+		upload, err := scanUpload(rows)
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, *upload)
+	}
+
+	return uploads, rows.Err()
+}
+
+func (r *uploadRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM direct_uploads WHERE id = $1`, id)
+	return err
+}
+
+func scanUpload(row pgx.Row) (*model.DirectUpload, error) {
+	var upload model.DirectUpload
+	err := row.Scan(
+		&upload.ID,
+		&upload.UserID,
+		&upload.Key,
+		&upload.Type,
+		&upload.ContentType,
+		&upload.Size,
+		&upload.ExpiresAt,
+		&upload.ClaimedAt,
+		&upload.CompletedAt,
+		&upload.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -21,8 +22,12 @@ type UserRepository interface {
 	GetByUsername(ctx context.Context, username string) (*model.User, error)
 	Update(ctx context.Context, user *model.User) error
 	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	SetBanned(ctx context.Context, id uuid.UUID, reason *string, until *time.Time) error
+	SetRole(ctx context.Context, id uuid.UUID, role model.UserRole) error
 	GetStats(ctx context.Context, id uuid.UUID) (*model.UserStats, error)
 	Search(ctx context.Context, query string, limit, offset int) ([]model.User, int, error)
+	ListForIndex(ctx context.Context, afterID uuid.UUID, limit int) ([]model.User, error)
+	GetIDsByUsernames(ctx context.Context, usernames []string) (map[string]uuid.UUID, error)
 	
 	// Follow
 	Follow(ctx context.Context, followerID, followingID uuid.UUID) error
@@ -337,19 +342,25 @@ func (r *userRepository) Search(ctx context.Context, query string, limit, offset
 
 	searchPattern := query + "%"
 
+	// Name prefixes keep search-as-you-type working; the full-text vector adds bio matches
+	tsQuery := "(websearch_to_tsquery('russian', $2) || websearch_to_tsquery('english', $2))"
+	where := "username ILIKE $1 OR display_name ILIKE $1 OR search_vector @@ " + tsQuery
+
 	// Count total
-	countQuery := `SELECT COUNT(*) FROM users WHERE username ILIKE $1 OR display_name ILIKE $1`
+	countQuery := `SELECT COUNT(*) FROM users WHERE ` + where
 	var total int
-	if err := r.db.QueryRow(ctx, countQuery, searchPattern).Scan(&total); err != nil {
+	if err := r.db.QueryRow(ctx, countQuery, searchPattern, query).Scan(&total); err != nil {
 		return nil, 0, err
 	}
 
 	sqlQuery := `
 		SELECT id, username, display_name, avatar_url, bio, is_verified
 		FROM users
-		WHERE username ILIKE $1 OR display_name ILIKE $1
+		WHERE ` + where + `
 		ORDER BY 
 			CASE WHEN username ILIKE $2 THEN 0 ELSE 1 END,
+			CASE WHEN username ILIKE $1 OR display_name ILIKE $1 THEN 0 ELSE 1 END,
+			ts_rank_cd(search_vector, ` + tsQuery + `) DESC,
 			is_verified DESC,
 			username ASC
 		LIMIT $3 OFFSET $4
@@ -373,6 +384,100 @@ func (r *userRepository) Search(ctx context.Context, query string, limit, offset
 	return users, total, nil
 }
 
+// ListForIndex returns users ordered by ID, starting after afterID
+func (r *userRepository) ListForIndex(ctx context.Context, afterID uuid.UUID, limit int) ([]model.User, error) {
+	query := `
+		SELECT id, username, display_name, bio, avatar_url, is_verified, karma
+		FROM users
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+	
+	rows, err := r.db.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var users []model.User
+	for rows.Next() {
+		var user model.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.DisplayName, &user.Bio, &user.AvatarURL, &user.IsVerified, &user.Karma); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	
+	return users, rows.Err()
+}
+
+// SetBanned bans a user until the given time, forever when until is nil;
+// a nil reason lifts the ban
+func (r *userRepository) SetBanned(ctx context.Context, id uuid.UUID, reason *string, until *time.Time) error {
+	query := `
+		UPDATE users SET
+			is_banned = $2::text IS NOT NULL,
+			ban_reason = $2,
+			banned_until = $3,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	
+	tag, err := r.db.Exec(ctx, query, id, reason, until)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) SetRole(ctx context.Context, id uuid.UUID, role model.UserRole) error {
+	query := `UPDATE users SET role = $2, updated_at = NOW() WHERE id = $1`
+	
+	tag, err := r.db.Exec(ctx, query, id, role)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// GetIDsByUsernames resolves usernames to user IDs; unknown and banned users are left out
+func (r *userRepository) GetIDsByUsernames(ctx context.Context, usernames []string) (map[string]uuid.UUID, error) {
+	ids := make(map[string]uuid.UUID, len(usernames))
+	if len(usernames) == 0 {
+		return ids, nil
+	}
+	
+	query := `
+		SELECT id, username
+		FROM users
+		WHERE username = ANY($1) AND is_banned = false
+	`
+	
+	rows, err := r.db.Query(ctx, query, usernames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	for rows.Next() {
+		var id uuid.UUID
+		var username string
+		if err := rows.Scan(&id, &username); err != nil {
+			return nil, err
+		}
+		ids[username] = id
+	}
+	
+	return ids, rows.Err()
+}
+
 func (r *userRepository) CreateSession(ctx context.Context, session *model.Session) error {
 	query := `
 		INSERT INTO sessions (id, user_id, refresh_token, user_agent, ip, expires_at, created_at)
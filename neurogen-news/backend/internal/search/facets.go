@@ -0,0 +1,4 @@
+package search
+
+// Article attributes with facet distributions in search results
+var ArticleFacets = []string{"level", "contentType", "categorySlug", "tags"}
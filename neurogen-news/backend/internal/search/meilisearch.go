@@ -3,10 +3,12 @@ package search
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/meilisearch/meilisearch-go"
 	"go.uber.org/zap"
 
+	"github.com/neurogen-news/backend/internal/highlight"
 	"github.com/neurogen-news/backend/internal/model"
 )
 
@@ -89,103 +91,72 @@ func NewClient(host, apiKey string, logger *zap.Logger) (*Client, error) {
 	return c, nil
 }
 
+// indexSettings holds the configuration of every index. Rebuild copies get the
+// same settings before documents are loaded into them.
+var indexSettings = map[string]*meilisearch.Settings{
+	IndexArticles: {
+		FilterableAttributes: []string{
+			"level",
+			"contentType",
+			"categoryId",
+			"categorySlug",
+			"authorId",
+			"tags",
+			"publishedAt",
+		},
+		SortableAttributes: []string{
+			"publishedAt",
+			"viewCount",
+			"commentCount",
+		},
+		// Searchable attributes with priority
+		SearchableAttributes: []string{
+			"title",
+			"lead",
+			"content",
+			"authorName",
+			"categoryName",
+			"tags",
+		},
+		RankingRules: []string{
+			"words",
+			"typo",
+			"proximity",
+			"attribute",
+			"sort",
+			"exactness",
+			"viewCount:desc",
+		},
+	},
+	IndexUsers: {
+		FilterableAttributes: []string{
+			"isVerified",
+		},
+		SortableAttributes: []string{
+			"karma",
+		},
+		SearchableAttributes: []string{
+			"username",
+			"displayName",
+			"bio",
+		},
+	},
+	IndexTags: {
+		SortableAttributes: []string{
+			"articleCount",
+		},
+		SearchableAttributes: []string{
+			"name",
+		},
+	},
+}
+
 // setupIndexes creates and configures search indexes
 func (c *Client) setupIndexes() error {
-	// Articles index
-	articlesIndex := c.client.Index(IndexArticles)
-
-	// Set filterable attributes
-	_, err := articlesIndex.UpdateFilterableAttributes(&[]string{
-		"level",
-		"contentType",
-		"categoryId",
-		"categorySlug",
-		"authorId",
-		"tags",
-		"publishedAt",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to set filterable attributes for articles: %w", err)
-	}
-
-	// Set sortable attributes
-	_, err = articlesIndex.UpdateSortableAttributes(&[]string{
-		"publishedAt",
-		"viewCount",
-		"commentCount",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to set sortable attributes for articles: %w", err)
-	}
-
-	// Set searchable attributes with priority
-	_, err = articlesIndex.UpdateSearchableAttributes(&[]string{
-		"title",
-		"lead",
-		"content",
-		"authorName",
-		"categoryName",
-		"tags",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to set searchable attributes for articles: %w", err)
-	}
-
-	// Set ranking rules
-	_, err = articlesIndex.UpdateRankingRules(&[]string{
-		"words",
-		"typo",
-		"proximity",
-		"attribute",
-		"sort",
-		"exactness",
-		"viewCount:desc",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to set ranking rules for articles: %w", err)
-	}
-
-	// Users index
-	usersIndex := c.client.Index(IndexUsers)
-
-	_, err = usersIndex.UpdateFilterableAttributes(&[]string{
-		"isVerified",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to set filterable attributes for users: %w", err)
-	}
-
-	_, err = usersIndex.UpdateSortableAttributes(&[]string{
-		"karma",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to set sortable attributes for users: %w", err)
-	}
-
-	_, err = usersIndex.UpdateSearchableAttributes(&[]string{
-		"username",
-		"displayName",
-		"bio",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to set searchable attributes for users: %w", err)
-	}
-
-	// Tags index
-	tagsIndex := c.client.Index(IndexTags)
-
-	_, err = tagsIndex.UpdateSortableAttributes(&[]string{
-		"articleCount",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to set sortable attributes for tags: %w", err)
-	}
-
-	_, err = tagsIndex.UpdateSearchableAttributes(&[]string{
-		"name",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to set searchable attributes for tags: %w", err)
+	for _, name := range []string{IndexArticles, IndexUsers, IndexTags} {
+		if _, err := c.client.Index(name).UpdateSettings(indexSettings[name]); err != nil {
+			return fmt.Errorf("failed to configure %s index: %w", name, err)
+		}
 	}
 
 	c.logger.Info("Meilisearch indexes configured successfully")
@@ -231,41 +202,29 @@ func (c *Client) DeleteArticle(ctx context.Context, id string) error {
 
 // SearchArticleParams represents article search parameters
 type SearchArticleParams struct {
-	Query       string
-	Level       string
-	ContentType string
-	CategoryID  string
-	Tags        []string
-	Sort        string // "relevance", "new", "popular"
-	Limit       int64
-	Offset      int64
+	Query        string
+	Level        string
+	ContentType  string
+	CategoryID   string
+	CategorySlug string
+	Tags         []string
+	Sort         string // "relevance", "new", "popular"
+	Limit        int64
+	Offset       int64
+	Facets       bool // compute ArticleFacets distributions
 }
 
 // SearchArticleResult represents search result
 type SearchArticleResult struct {
-	Hits             []SearchableArticle `json:"hits"`
-	Total            int64               `json:"total"`
-	ProcessingTimeMs int64               `json:"processingTimeMs"`
+	Hits             []SearchableArticle         `json:"hits"`
+	Highlights       []model.ArticleHighlight    `json:"highlights"` // same order as Hits
+	Facets           map[string]map[string]int64 `json:"facets,omitempty"`
+	Total            int64                       `json:"total"`
+	ProcessingTimeMs int64                       `json:"processingTimeMs"`
 }
 
 // SearchArticles searches for articles
 func (c *Client) SearchArticles(ctx context.Context, params SearchArticleParams) (*SearchArticleResult, error) {
-	// Build filter
-	var filters []string
-
-	if params.Level != "" {
-		filters = append(filters, fmt.Sprintf("level = '%s'", params.Level))
-	}
-	if params.ContentType != "" {
-		filters = append(filters, fmt.Sprintf("contentType = '%s'", params.ContentType))
-	}
-	if params.CategoryID != "" {
-		filters = append(filters, fmt.Sprintf("categoryId = '%s'", params.CategoryID))
-	}
-	for _, tag := range params.Tags {
-		filters = append(filters, fmt.Sprintf("tags = '%s'", tag))
-	}
-
 	// Build sort
 	var sort []string
 	switch params.Sort {
@@ -283,22 +242,28 @@ func (c *Client) SearchArticles(ctx context.Context, params SearchArticleParams)
 	}
 
 	searchReq := &meilisearch.SearchRequest{
-		Limit:  params.Limit,
-		Offset: params.Offset,
+		Limit:                 params.Limit,
+		Offset:                params.Offset,
+		AttributesToHighlight: []string{"title", "lead"},
+		AttributesToCrop:      []string{"content"},
+		CropLength:            30,
+		CropMarker:            "…",
+		HighlightPreTag:       highlight.PreTag,
+		HighlightPostTag:      highlight.PostTag,
 	}
 
-	if len(filters) > 0 {
-		filterStr := filters[0]
-		for i := 1; i < len(filters); i++ {
-			filterStr += " AND " + filters[i]
-		}
-		searchReq.Filter = filterStr
+	if filter := articleFilter(params, ""); filter != "" {
+		searchReq.Filter = filter
 	}
 
 	if len(sort) > 0 {
 		searchReq.Sort = sort
 	}
 
+	if params.Facets {
+		searchReq.Facets = ArticleFacets
+	}
+
 	resp, err := c.client.Index(IndexArticles).Search(params.Query, searchReq)
 	if err != nil {
 		c.logger.Error("Article search failed", zap.Error(err))
@@ -307,18 +272,90 @@ func (c *Client) SearchArticles(ctx context.Context, params SearchArticleParams)
 
 	// Convert hits
 	var hits []SearchableArticle
+	var highlights []model.ArticleHighlight
 	for _, hit := range resp.Hits {
 		if hitMap, ok := hit.(map[string]interface{}); ok {
 			article := mapToSearchableArticle(hitMap)
 			hits = append(hits, article)
+			highlights = append(highlights, mapToArticleHighlight(hitMap))
 		}
 	}
 
-	return &SearchArticleResult{
+	result := &SearchArticleResult{
 		Hits:             hits,
+		Highlights:       highlights,
 		Total:            resp.EstimatedTotalHits,
 		ProcessingTimeMs: resp.ProcessingTimeMs,
-	}, nil
+	}
+
+	if params.Facets {
+		result.Facets = facetDistribution(resp.FacetDistribution)
+
+		// Single-choice filters count their options as if the filter was not set,
+		// otherwise every other option would show zero
+		for _, attr := range []string{"level", "contentType", "categorySlug"} {
+			if articleFilter(params, attr) == articleFilter(params, "") {
+				continue
+			}
+			counts, err := c.facetCounts(params, attr)
+			if err != nil {
+				c.logger.Warn("Facet search failed", zap.String("facet", attr), zap.Error(err))
+				continue
+			}
+			result.Facets[attr] = counts
+		}
+	}
+
+	return result, nil
+}
+
+// facetCounts returns the distribution of one facet with its own filter removed
+func (c *Client) facetCounts(params SearchArticleParams, attr string) (map[string]int64, error) {
+	searchReq := &meilisearch.SearchRequest{
+		Limit:  0,
+		Facets: []string{attr},
+	}
+	if filter := articleFilter(params, attr); filter != "" {
+		searchReq.Filter = filter
+	}
+
+	resp, err := c.client.Index(IndexArticles).Search(params.Query, searchReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return facetDistribution(resp.FacetDistribution)[attr], nil
+}
+
+// articleFilter builds the filter expression, leaving out the filter on skip
+func articleFilter(params SearchArticleParams, skip string) string {
+	var filters []string
+
+	if params.Level != "" && skip != "level" {
+		filters = append(filters, "level = "+quoteFilterValue(params.Level))
+	}
+	if params.ContentType != "" && skip != "contentType" {
+		filters = append(filters, "contentType = "+quoteFilterValue(params.ContentType))
+	}
+	if skip != "categorySlug" {
+		if params.CategoryID != "" {
+			filters = append(filters, "categoryId = "+quoteFilterValue(params.CategoryID))
+		}
+		if params.CategorySlug != "" {
+			filters = append(filters, "categorySlug = "+quoteFilterValue(params.CategorySlug))
+		}
+	}
+	for _, tag := range params.Tags {
+		filters = append(filters, "tags = "+quoteFilterValue(tag))
+	}
+
+	return strings.Join(filters, " AND ")
+}
+
+// quoteFilterValue quotes user input for a filter expression
+func quoteFilterValue(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	return "'" + strings.ReplaceAll(v, "'", `\'`) + "'"
 }
 
 // ============================================
@@ -480,6 +517,51 @@ func mapToSearchableArticle(m map[string]interface{}) SearchableArticle {
 	return article
 }
 
+// mapToArticleHighlight reads the _formatted copy of a hit
+func mapToArticleHighlight(m map[string]interface{}) model.ArticleHighlight {
+	var h model.ArticleHighlight
+
+	formatted, ok := m["_formatted"].(map[string]interface{})
+	if !ok {
+		return h
+	}
+	if v, ok := formatted["title"].(string); ok {
+		h.Title = highlight.ToHTML(v)
+	}
+	if v, ok := formatted["lead"].(string); ok {
+		h.Lead = highlight.ToHTML(v)
+	}
+	if v, ok := formatted["content"].(string); ok {
+		h.Content = highlight.ToHTML(v)
+	}
+
+	return h
+}
+
+// facetDistribution converts the raw facetDistribution of a search response
+func facetDistribution(raw interface{}) map[string]map[string]int64 {
+	result := make(map[string]map[string]int64)
+
+	facets, ok := raw.(map[string]interface{})
+	if !ok {
+		return result
+	}
+	for attr, values := range facets {
+		counts, ok := values.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		result[attr] = make(map[string]int64, len(counts))
+		for value, count := range counts {
+			if n, ok := count.(float64); ok {
+				result[attr][value] = int64(n)
+			}
+		}
+	}
+
+	return result
+}
+
 func mapToSearchableUser(m map[string]interface{}) SearchableUser {
 	user := SearchableUser{}
 
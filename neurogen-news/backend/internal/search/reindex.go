@@ -0,0 +1,175 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/meilisearch/meilisearch-go"
+	"go.uber.org/zap"
+)
+
+// Suffix of the shadow index a rebuild fills before it is swapped in
+const rebuildSuffix = "_rebuild"
+
+// Rebuild is an empty, fully configured copy of an index that is filled from
+// scratch and then atomically swapped with the live index.
+type Rebuild struct {
+	client *Client
+	name   string
+	uid    string
+	count  int
+}
+
+// BeginRebuild creates the shadow index for name, dropping leftovers of an
+// interrupted run
+func (c *Client) BeginRebuild(ctx context.Context, name string) (*Rebuild, error) {
+	settings, ok := indexSettings[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown index %q", name)
+	}
+
+	uid := name + rebuildSuffix
+
+	// Leftover from a previous run; failure means there was nothing to delete
+	if info, err := c.client.DeleteIndex(uid); err == nil {
+		_, _ = c.client.WaitForTask(info.TaskUID, c.waitParams(ctx))
+	}
+
+	if err := c.wait(ctx)(c.client.CreateIndex(&meilisearch.IndexConfig{Uid: uid, PrimaryKey: "id"})); err != nil {
+		return nil, fmt.Errorf("create %s: %w", uid, err)
+	}
+	if err := c.wait(ctx)(c.client.Index(uid).UpdateSettings(settings)); err != nil {
+		return nil, fmt.Errorf("configure %s: %w", uid, err)
+	}
+
+	return &Rebuild{client: c, name: name, uid: uid}, nil
+}
+
+// Add loads a batch of documents into the shadow index and waits until they are indexed
+func (r *Rebuild) Add(ctx context.Context, documents interface{}, n int) error {
+	if n == 0 {
+		return nil
+	}
+	if err := r.client.wait(ctx)(r.client.client.Index(r.uid).AddDocuments(documents, "id")); err != nil {
+		return fmt.Errorf("add documents to %s: %w", r.uid, err)
+	}
+	r.count += n
+	return nil
+}
+
+// Count returns the number of documents loaded so far
+func (r *Rebuild) Count() int {
+	return r.count
+}
+
+// Commit swaps the shadow index with the live one in a single Meilisearch task
+// and drops the old documents
+func (r *Rebuild) Commit(ctx context.Context) error {
+	c := r.client
+
+	// Swapping requires both indexes to exist
+	if _, err := c.client.GetIndex(r.name); err != nil {
+		if err := c.wait(ctx)(c.client.CreateIndex(&meilisearch.IndexConfig{Uid: r.name, PrimaryKey: "id"})); err != nil {
+			return fmt.Errorf("create %s: %w", r.name, err)
+		}
+	}
+
+	swap := []meilisearch.SwapIndexesParams{{Indexes: []string{r.name, r.uid}}}
+	if err := c.wait(ctx)(c.client.SwapIndexes(swap)); err != nil {
+		return fmt.Errorf("swap %s: %w", r.name, err)
+	}
+
+	// After the swap the shadow index holds the previous documents
+	if err := c.wait(ctx)(c.client.DeleteIndex(r.uid)); err != nil {
+		c.logger.Warn("Failed to drop previous index", zap.String("index", r.uid), zap.Error(err))
+	}
+
+	c.logger.Info("Index rebuilt", zap.String("index", r.name), zap.Int("documents", r.count))
+	return nil
+}
+
+// Abort drops the shadow index, leaving the live index untouched
+func (r *Rebuild) Abort(ctx context.Context) {
+	if err := r.client.wait(ctx)(r.client.client.DeleteIndex(r.uid)); err != nil {
+		r.client.logger.Warn("Failed to drop rebuild index", zap.String("index", r.uid), zap.Error(err))
+	}
+}
+
+// ForEachArticle pages through every document of the live articles index
+func (c *Client) ForEachArticle(ctx context.Context, batchSize int64, fn func([]SearchableArticle) error) error {
+	return forEachDocument(ctx, c, IndexArticles, batchSize, fn)
+}
+
+// ForEachUser pages through every document of the live users index
+func (c *Client) ForEachUser(ctx context.Context, batchSize int64, fn func([]SearchableUser) error) error {
+	return forEachDocument(ctx, c, IndexUsers, batchSize, fn)
+}
+
+// ForEachTag pages through every document of the live tags index
+func (c *Client) ForEachTag(ctx context.Context, batchSize int64, fn func([]SearchableTag) error) error {
+	return forEachDocument(ctx, c, IndexTags, batchSize, fn)
+}
+
+func forEachDocument[T any](ctx context.Context, c *Client, index string, batchSize int64, fn func([]T) error) error {
+	for offset := int64(0); ; offset += batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var page meilisearch.DocumentsResult
+		err := c.client.Index(index).GetDocuments(&meilisearch.DocumentsQuery{
+			Offset: offset,
+			Limit:  batchSize,
+		}, &page)
+		if err != nil {
+			return fmt.Errorf("get documents from %s: %w", index, err)
+		}
+
+		// Round-trip through JSON so documents decode exactly like they were indexed
+		raw, err := json.Marshal(page.Results)
+		if err != nil {
+			return err
+		}
+		var docs []T
+		if err := json.Unmarshal(raw, &docs); err != nil {
+			return err
+		}
+
+		if len(docs) > 0 {
+			if err := fn(docs); err != nil {
+				return err
+			}
+		}
+
+		if int64(len(page.Results)) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (c *Client) waitParams(ctx context.Context) meilisearch.WaitParams {
+	return meilisearch.WaitParams{Context: ctx, Interval: 100 * time.Millisecond}
+}
+
+// wait returns a function that blocks until an enqueued task finishes and
+// reports failed tasks as errors. It takes the (TaskInfo, error) pair of the
+// enqueueing call directly: c.wait(ctx)(index.AddDocuments(...)).
+func (c *Client) wait(ctx context.Context) func(*meilisearch.TaskInfo, error) error {
+	return func(info *meilisearch.TaskInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		task, err := c.client.WaitForTask(info.TaskUID, c.waitParams(ctx))
+		if err != nil {
+			return err
+		}
+		if task.Status != meilisearch.TaskStatusSucceeded {
+			return fmt.Errorf("task %d (%s) %s: %s", task.UID, task.Type, task.Status, task.Error.Message)
+		}
+
+		return nil
+	}
+}
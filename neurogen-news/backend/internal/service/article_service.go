@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"regexp"
 	"strings"
 	"time"
@@ -10,24 +11,31 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/neurogen-news/backend/internal/markdown"
 	"github.com/neurogen-news/backend/internal/model"
 	"github.com/neurogen-news/backend/internal/repository"
 )
 
 type ArticleService interface {
 	Create(ctx context.Context, userID uuid.UUID, input CreateArticleInput) (*model.Article, error)
-	GetByID(ctx context.Context, id uuid.UUID) (*model.Article, error)
-	GetBySlug(ctx context.Context, categorySlug, articleSlug string) (*model.Article, error)
+	GetByID(ctx context.Context, id uuid.UUID, viewerID *uuid.UUID) (*model.Article, error)
+	GetBySlug(ctx context.Context, categorySlug, articleSlug string, viewerID *uuid.UUID) (*model.Article, error)
 	Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input UpdateArticleInput) (*model.Article, error)
 	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
 	List(ctx context.Context, params ArticleListParams) (*ArticleListResult, error)
 	
 	// Reactions
-	AddReaction(ctx context.Context, userID, articleID uuid.UUID, emoji string) error
-	RemoveReaction(ctx context.Context, userID, articleID uuid.UUID) error
+	AddReaction(ctx context.Context, userID, articleID uuid.UUID, emoji string) ([]model.ReactionCount, error)
+	RemoveReaction(ctx context.Context, userID, articleID uuid.UUID) ([]model.ReactionCount, error)
 	
 	// View count
 	RecordView(ctx context.Context, articleID uuid.UUID, userIP string) error
+	
+	// Versions
+	ListVersions(ctx context.Context, userID, articleID uuid.UUID, page, pageSize int) (*ArticleVersionListResult, error)
+	GetVersion(ctx context.Context, userID, articleID uuid.UUID, version int) (*model.ArticleVersion, error)
+	DiffVersions(ctx context.Context, userID, articleID uuid.UUID, from, to int, mode DiffMode) (*ArticleVersionDiff, error)
+	RestoreVersion(ctx context.Context, userID, articleID uuid.UUID, version int) (*model.Article, error)
 }
 
 type CreateArticleInput struct {
@@ -38,7 +46,7 @@ type CreateArticleInput struct {
 	Level           model.ArticleLevel `json:"level" validate:"required,oneof=beginner intermediate advanced"`
 	ContentType     model.ContentType  `json:"contentType" validate:"required,oneof=article news post question discussion"`
 	CategoryID      uuid.UUID          `json:"categoryId" validate:"required"`
-	Tags            []string           `json:"tags,omitempty" validate:"max=10"`
+	Tags            []string           `json:"tags,omitempty"` // up to the maxArticleTags setting
 	IsNSFW          bool               `json:"isNsfw"`
 	CommentsEnabled bool               `json:"commentsEnabled"`
 	Status          model.ArticleStatus `json:"status" validate:"oneof=draft published"`
@@ -56,7 +64,7 @@ type UpdateArticleInput struct {
 	Level           *model.ArticleLevel `json:"level,omitempty" validate:"omitempty,oneof=beginner intermediate advanced"`
 	ContentType     *model.ContentType  `json:"contentType,omitempty" validate:"omitempty,oneof=article news post question discussion"`
 	CategoryID      *uuid.UUID          `json:"categoryId,omitempty"`
-	Tags            []string            `json:"tags,omitempty" validate:"max=10"`
+	Tags            []string            `json:"tags,omitempty"` // up to the maxArticleTags setting
 	IsNSFW          *bool               `json:"isNsfw,omitempty"`
 	CommentsEnabled *bool               `json:"commentsEnabled,omitempty"`
 	Status          *model.ArticleStatus `json:"status,omitempty" validate:"omitempty,oneof=draft published archived"`
@@ -73,6 +81,9 @@ type ArticleListParams struct {
 	TimeRange   string `query:"timeRange" validate:"omitempty,oneof=24h 7d 30d all"`
 	Page        int    `query:"page" validate:"min=1"`
 	PageSize    int    `query:"pageSize" validate:"min=1,max=50"`
+	
+	// Viewer is used to mark the user's own reactions
+	ViewerID *uuid.UUID `query:"-"`
 }
 
 type ArticleListResult struct {
@@ -84,27 +95,66 @@ type ArticleListResult struct {
 }
 
 type articleService struct {
-	articleRepo repository.ArticleRepository
-	tagRepo     repository.TagRepository
-	redis       *repository.RedisClient
-	logger      *zap.Logger
+	articleRepo   repository.ArticleRepository
+	versionRepo   repository.ArticleVersionRepository
+	userRepo      repository.UserRepository
+	tagRepo       repository.TagRepository
+	reactionRepo  repository.ReactionRepository
+	outboxRepo    repository.OutboxRepository
+	tx            repository.Transactor
+	premoderation PremoderationPolicy
+	automod       AutomodService
+	settings      SettingsService
+	permissions   PermissionService
+	moderation    ModerationService
+	redis         *repository.RedisClient
+	hub           Broadcaster
+	markdown      *markdown.Renderer
+	logger        *zap.Logger
 }
 
 func NewArticleService(
 	articleRepo repository.ArticleRepository,
+	versionRepo repository.ArticleVersionRepository,
+	userRepo repository.UserRepository,
 	tagRepo repository.TagRepository,
+	reactionRepo repository.ReactionRepository,
+	outboxRepo repository.OutboxRepository,
+	tx repository.Transactor,
+	premoderation PremoderationPolicy,
+	automod AutomodService,
+	settings SettingsService,
+	permissions PermissionService,
+	moderation ModerationService,
 	redis *repository.RedisClient,
+	hub Broadcaster,
 	logger *zap.Logger,
 ) ArticleService {
 	return &articleService{
-		articleRepo: articleRepo,
-		tagRepo:     tagRepo,
-		redis:       redis,
-		logger:      logger,
+		articleRepo:   articleRepo,
+		versionRepo:   versionRepo,
+		userRepo:      userRepo,
+		tagRepo:       tagRepo,
+		reactionRepo:  reactionRepo,
+		outboxRepo:    outboxRepo,
+		tx:            tx,
+		premoderation: premoderation,
+		automod:       automod,
+		settings:      settings,
+		permissions:   permissions,
+		moderation:    moderation,
+		redis:         redis,
+		hub:           hub,
+		markdown:      markdown.New(markdown.Config{}),
+		logger:        logger,
 	}
 }
 
 func (s *articleService) Create(ctx context.Context, userID uuid.UUID, input CreateArticleInput) (*model.Article, error) {
+	if len(input.Tags) > s.settings.Current().MaxArticleTags {
+		return nil, ErrTooManyTags
+	}
+	
 	// Generate slug
 	slug := generateSlug(input.Title)
 	
@@ -115,8 +165,11 @@ func (s *articleService) Create(ctx context.Context, userID uuid.UUID, input Cre
 		readingTime = 1
 	}
 	
-	// Convert markdown to HTML (simplified)
-	htmlContent := convertToHTML(input.Content)
+	// Convert markdown to sanitized HTML
+	htmlContent, err := s.markdown.Render(input.Content)
+	if err != nil {
+		return nil, err
+	}
 	
 	article := &model.Article{
 		Title:           input.Title,
@@ -137,67 +190,129 @@ func (s *articleService) Create(ctx context.Context, userID uuid.UUID, input Cre
 		MetaDescription: input.MetaDescription,
 	}
 	
+	var verdict *AutomodVerdict
 	if input.Status == model.StatusPublished {
-		now := time.Now()
-		article.PublishedAt = &now
-	}
-	
-	if err := s.articleRepo.Create(ctx, article); err != nil {
-		return nil, err
+		if err := s.submit(ctx, article); err != nil {
+			return nil, err
+		}
+		if verdict, err = s.automoderate(ctx, article, nil); err != nil {
+			return nil, err
+		}
 	}
 	
-	// Add tags
+	// Resolve tags before the article transaction (new tags commit on their own)
+	var tagIDs []uuid.UUID
 	if len(input.Tags) > 0 {
-		tagIDs, err := s.getOrCreateTags(ctx, input.Tags)
+		tagIDs, err = s.getOrCreateTags(ctx, input.Tags)
 		if err != nil {
 			s.logger.Error("Failed to create tags", zap.Error(err))
-		} else {
-			_ = s.articleRepo.AddTags(ctx, article.ID, tagIDs)
 		}
 	}
 	
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.articleRepo.Create(ctx, article); err != nil {
+			return err
+		}
+		if err := s.recordVersion(ctx, article, userID, nil); err != nil {
+			return err
+		}
+		if err := s.articleRepo.AddTags(ctx, article.ID, tagIDs); err != nil {
+			return err
+		}
+		if err := s.automod.Apply(ctx, verdict, articleAutomodInput(article), article.ID); err != nil {
+			return err
+		}
+		return s.enqueueArticleEvents(ctx, article.ID, tagIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
 	// Invalidate cache
 	s.invalidateCache(ctx)
 	
 	return article, nil
 }
 
-func (s *articleService) GetByID(ctx context.Context, id uuid.UUID) (*model.Article, error) {
+func (s *articleService) GetByID(ctx context.Context, id uuid.UUID, viewerID *uuid.UUID) (*model.Article, error) {
 	article, err := s.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if shadowHiddenFrom(article, viewerID) {
+		return nil, repository.ErrArticleNotFound
+	}
 	
 	// Get tags
 	tags, _ := s.articleRepo.GetTags(ctx, id)
 	article.Tags = tags
 	
+	// Get reactions
+	reactions, err := s.reactionRepo.GetArticleReactions(ctx, id, viewerID)
+	if err != nil {
+		s.logger.Warn("Failed to load article reactions", zap.Error(err))
+	}
+	article.Reactions = reactions
+	
 	return article, nil
 }
 
-func (s *articleService) GetBySlug(ctx context.Context, categorySlug, articleSlug string) (*model.Article, error) {
+func (s *articleService) GetBySlug(ctx context.Context, categorySlug, articleSlug string, viewerID *uuid.UUID) (*model.Article, error) {
 	article, err := s.articleRepo.GetBySlug(ctx, categorySlug, articleSlug)
 	if err != nil {
 		return nil, err
 	}
+	if shadowHiddenFrom(article, viewerID) {
+		return nil, repository.ErrArticleNotFound
+	}
 	
 	// Get tags
 	tags, _ := s.articleRepo.GetTags(ctx, article.ID)
 	article.Tags = tags
 	
+	// Get reactions
+	reactions, err := s.reactionRepo.GetArticleReactions(ctx, article.ID, viewerID)
+	if err != nil {
+		s.logger.Warn("Failed to load article reactions", zap.Error(err))
+	}
+	article.Reactions = reactions
+	
 	return article, nil
 }
 
 func (s *articleService) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input UpdateArticleInput) (*model.Article, error) {
+	return s.update(ctx, userID, id, input, nil)
+}
+
+// update applies an edit; restoredFrom is set when the edit restores a version
+func (s *articleService) update(ctx context.Context, userID uuid.UUID, id uuid.UUID, input UpdateArticleInput, restoredFrom *int) (*model.Article, error) {
 	article, err := s.articleRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	
-	// Check ownership (or admin)
-	if article.AuthorID != userID {
-		return nil, ErrForbidden
+	// Editors and moderators of the category may edit other authors' articles
+	byStaff := article.AuthorID != userID
+	if byStaff {
+		if err := s.authorize(ctx, userID, model.PermArticleEditAny, article.CategoryID); err != nil {
+			return nil, err
+		}
+		// Moving the article needs the same rights in the target category
+		if input.CategoryID != nil && *input.CategoryID != article.CategoryID {
+			if err := s.authorize(ctx, userID, model.PermArticleEditAny, *input.CategoryID); err != nil {
+				return nil, err
+			}
+		}
 	}
+	before := *article
+	if len(input.Tags) > s.settings.Current().MaxArticleTags {
+		return nil, ErrTooManyTags
+	}
+	
+	// Edits of public text and publishing go through automoderation
+	contentChanged := (input.Title != nil && *input.Title != article.Title) ||
+		(input.Content != nil && *input.Content != article.Content)
+	publishedAt := article.PublishedAt
 	
 	// Update fields
 	if input.Title != nil {
@@ -206,7 +321,11 @@ func (s *articleService) Update(ctx context.Context, userID uuid.UUID, id uuid.U
 	}
 	if input.Content != nil {
 		article.Content = *input.Content
-		article.HTMLContent = convertToHTML(*input.Content)
+		htmlContent, err := s.markdown.Render(*input.Content)
+		if err != nil {
+			return nil, err
+		}
+		article.HTMLContent = htmlContent
 		wordCount := len(strings.Fields(*input.Content))
 		article.ReadingTime = (wordCount + 199) / 200
 	}
@@ -231,11 +350,19 @@ func (s *articleService) Update(ctx context.Context, userID uuid.UUID, id uuid.U
 	if input.CommentsEnabled != nil {
 		article.CommentsEnabled = *input.CommentsEnabled
 	}
-	if input.Status != nil {
-		article.Status = *input.Status
-		if *input.Status == model.StatusPublished && article.PublishedAt == nil {
-			now := time.Now()
-			article.PublishedAt = &now
+	publishing := false
+	if input.Status != nil && *input.Status != article.Status {
+		// Publishing goes through pre-moderation unless the article is already out
+		if *input.Status == model.StatusPublished {
+			if article.ReviewDecision != nil && *article.ReviewDecision == model.ReviewRejected {
+				return nil, ErrArticleRejected
+			}
+			if err := s.submit(ctx, article); err != nil {
+				return nil, err
+			}
+			publishing = true
+		} else {
+			article.Status = *input.Status
 		}
 	}
 	if input.MetaTitle != nil {
@@ -245,19 +372,66 @@ func (s *articleService) Update(ctx context.Context, userID uuid.UUID, id uuid.U
 		article.MetaDescription = input.MetaDescription
 	}
 	
-	if err := s.articleRepo.Update(ctx, article); err != nil {
-		return nil, err
+	var verdict *AutomodVerdict
+	public := article.Status == model.StatusPublished || article.Status == model.StatusPending
+	if public && (publishing || contentChanged) {
+		if verdict, err = s.automoderate(ctx, article, publishedAt); err != nil {
+			return nil, err
+		}
 	}
 	
-	// Update tags
+	// Resolve tags before the article transaction (new tags commit on their own)
+	var tagIDs, affectedTagIDs []uuid.UUID
 	if input.Tags != nil {
-		_ = s.articleRepo.RemoveTags(ctx, article.ID)
 		if len(input.Tags) > 0 {
-			tagIDs, err := s.getOrCreateTags(ctx, input.Tags)
-			if err == nil {
-				_ = s.articleRepo.AddTags(ctx, article.ID, tagIDs)
+			tagIDs, err = s.getOrCreateTags(ctx, input.Tags)
+			if err != nil {
+				s.logger.Error("Failed to create tags", zap.Error(err))
+			}
+		}
+		
+		// Tags that lose the article need their counts re-indexed too
+		oldTags, _ := s.articleRepo.GetTags(ctx, article.ID)
+		for _, t := range oldTags {
+			affectedTagIDs = append(affectedTagIDs, t.ID)
+		}
+		affectedTagIDs = append(affectedTagIDs, tagIDs...)
+	}
+	
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.articleRepo.Update(ctx, article); err != nil {
+			return err
+		}
+		if contentChanged {
+			if err := s.recordVersion(ctx, article, userID, restoredFrom); err != nil {
+				return err
+			}
+		}
+		if input.Tags != nil {
+			if err := s.articleRepo.RemoveTags(ctx, article.ID); err != nil {
+				return err
+			}
+			if err := s.articleRepo.AddTags(ctx, article.ID, tagIDs); err != nil {
+				return err
+			}
+		}
+		if err := s.automod.Apply(ctx, verdict, articleAutomodInput(article), article.ID); err != nil {
+			return err
+		}
+		if byStaff {
+			if err := s.moderation.Record(ctx, &model.ModerationAction{
+				ModeratorID: userID,
+				TargetType:  model.ReportTargetArticle,
+				TargetID:    article.ID,
+				Action:      model.ModerationUpdate,
+			}, &before, article); err != nil {
+				return err
 			}
 		}
+		return s.enqueueArticleEvents(ctx, article.ID, affectedTagIDs)
+	})
+	if err != nil {
+		return nil, err
 	}
 	
 	s.invalidateCache(ctx)
@@ -271,11 +445,31 @@ func (s *articleService) Delete(ctx context.Context, userID uuid.UUID, id uuid.U
 		return err
 	}
 	
+	// Staff deletions go through moderation so they reach the audit log
 	if article.AuthorID != userID {
-		return ErrForbidden
+		if err := s.authorize(ctx, userID, model.PermArticleDeleteAny, article.CategoryID); err != nil {
+			return err
+		}
+		return s.moderation.DeleteArticle(ctx, userID, id, "")
 	}
 	
-	if err := s.articleRepo.Delete(ctx, id); err != nil {
+	tags, _ := s.articleRepo.GetTags(ctx, id)
+	
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.articleRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if err := s.outboxRepo.Enqueue(ctx, model.OutboxArticleDeleted, id); err != nil {
+			return err
+		}
+		for _, t := range tags {
+			if err := s.outboxRepo.Enqueue(ctx, model.OutboxTagUpserted, t.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 	
@@ -322,6 +516,8 @@ func (s *articleService) List(ctx context.Context, params ArticleListParams) (*A
 		return nil, err
 	}
 	
+	s.attachReactions(ctx, articles, params.ViewerID)
+	
 	return &ArticleListResult{
 		Items:    articles,
 		Total:    total,
@@ -331,14 +527,83 @@ func (s *articleService) List(ctx context.Context, params ArticleListParams) (*A
 	}, nil
 }
 
-func (s *articleService) AddReaction(ctx context.Context, userID, articleID uuid.UUID, emoji string) error {
-	// TODO: Implement reaction logic
-	return nil
+func (s *articleService) AddReaction(ctx context.Context, userID, articleID uuid.UUID, emoji string) ([]model.ReactionCount, error) {
+	reactionType, err := s.reactionRepo.GetByEmoji(ctx, emoji)
+	if err != nil {
+		if errors.Is(err, repository.ErrReactionTypeNotFound) {
+			return nil, ErrInvalidReaction
+		}
+		return nil, err
+	}
+	
+	article, err := s.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	// Only published articles the user can see take reactions
+	if article.Status != model.StatusPublished || shadowHiddenFrom(article, &userID) {
+		return nil, repository.ErrArticleNotFound
+	}
+	
+	// One reaction per user: adding a different emoji replaces the previous one
+	if err := s.reactionRepo.AddArticleReaction(ctx, articleID, userID, reactionType.ID); err != nil {
+		return nil, err
+	}
+	
+	return s.publishReactions(ctx, articleID, reactionType.Emoji)
 }
 
-func (s *articleService) RemoveReaction(ctx context.Context, userID, articleID uuid.UUID) error {
-	// TODO: Implement reaction removal
-	return nil
+func (s *articleService) RemoveReaction(ctx context.Context, userID, articleID uuid.UUID) ([]model.ReactionCount, error) {
+	if err := s.reactionRepo.RemoveArticleReaction(ctx, articleID, userID); err != nil {
+		return nil, err
+	}
+	
+	return s.publishReactions(ctx, articleID, "")
+}
+
+// publishReactions broadcasts fresh counts and returns them marked for the acting user
+func (s *articleService) publishReactions(ctx context.Context, articleID uuid.UUID, userEmoji string) ([]model.ReactionCount, error) {
+	reactions, err := s.reactionRepo.GetArticleReactions(ctx, articleID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if reactions == nil {
+		reactions = []model.ReactionCount{}
+	}
+	
+	if s.hub != nil {
+		s.hub.BroadcastReaction(articleID, reactions)
+	}
+	
+	result := make([]model.ReactionCount, len(reactions))
+	for i, rc := range reactions {
+		rc.IsReacted = rc.Emoji == userEmoji
+		result[i] = rc
+	}
+	
+	return result, nil
+}
+
+// attachReactions fills reaction counts for feed cards in one query
+func (s *articleService) attachReactions(ctx context.Context, articles []model.ArticleCard, viewerID *uuid.UUID) {
+	if len(articles) == 0 {
+		return
+	}
+	
+	ids := make([]uuid.UUID, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	
+	reactions, err := s.reactionRepo.GetArticlesReactions(ctx, ids, viewerID)
+	if err != nil {
+		s.logger.Warn("Failed to load article reactions", zap.Error(err))
+		return
+	}
+	
+	for i := range articles {
+		articles[i].Reactions = reactions[articles[i].ID]
+	}
 }
 
 func (s *articleService) RecordView(ctx context.Context, articleID uuid.UUID, userIP string) error {
@@ -354,12 +619,81 @@ func (s *articleService) RecordView(ctx context.Context, articleID uuid.UUID, us
 		if err := s.redis.Set(ctx, key, "1", 24*time.Hour).Err(); err != nil {
 			return err
 		}
+		if err := trackDailyView(ctx, s.redis); err != nil {
+			s.logger.Debug("Failed to count daily view", zap.Error(err))
+		}
 		return s.articleRepo.IncrementViewCount(ctx, articleID)
 	}
 	
 	return nil
 }
 
+// submit publishes the article, or sends it to the review queue when its
+// author is subject to pre-moderation
+func (s *articleService) submit(ctx context.Context, article *model.Article) error {
+	author, err := s.userRepo.GetByID(ctx, article.AuthorID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if s.premoderation.Requires(author, now) {
+		article.Status = model.StatusPending
+		article.SubmittedAt = &now
+		return nil
+	}
+
+	article.Status = model.StatusPublished
+	if article.PublishedAt == nil {
+		article.PublishedAt = &now
+	}
+	return nil
+}
+
+// automoderate checks an article that is being published. Held articles go to
+// the review queue and shadow-hidden ones stay visible only to their author;
+// rejected ones are logged and refused with ErrContentRejected. publishedAt is
+// the publication time before this change, restored when the article is held.
+func (s *articleService) automoderate(ctx context.Context, article *model.Article, publishedAt *time.Time) (*AutomodVerdict, error) {
+	input := articleAutomodInput(article)
+	verdict := s.automod.Check(ctx, input)
+	
+	switch verdict.Action {
+	case model.AutomodReject:
+		if err := s.automod.Apply(ctx, verdict, input, article.ID); err != nil {
+			s.logger.Warn("Failed to record automod rejection", zap.Error(err))
+		}
+		return nil, ErrContentRejected
+	case model.AutomodHold:
+		if article.Status == model.StatusPublished {
+			now := time.Now()
+			article.Status = model.StatusPending
+			article.SubmittedAt = &now
+			article.PublishedAt = publishedAt
+		}
+	case model.AutomodShadowHide:
+		article.AutomodState = verdict.State()
+	}
+	
+	return verdict, nil
+}
+
+func articleAutomodInput(article *model.Article) AutomodInput {
+	return AutomodInput{
+		Target:   model.ReportTargetArticle,
+		AuthorID: article.AuthorID,
+		Text:     article.Title + "\n\n" + article.Content,
+	}
+}
+
+// shadowHiddenFrom reports whether automoderation hid the article from the viewer
+func shadowHiddenFrom(article *model.Article, viewerID *uuid.UUID) bool {
+	if article.AutomodState == nil {
+		return false
+	}
+	return viewerID == nil || *viewerID != article.AuthorID
+}
+
 func (s *articleService) getOrCreateTags(ctx context.Context, tagNames []string) ([]uuid.UUID, error) {
 	var tagIDs []uuid.UUID
 	
@@ -377,7 +711,13 @@ func (s *articleService) getOrCreateTags(ctx context.Context, tagNames []string)
 				Name: name,
 				Slug: generateSlug(name),
 			}
-			if err := s.tagRepo.Create(ctx, tag); err != nil {
+			err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+				if err := s.tagRepo.Create(ctx, tag); err != nil {
+					return err
+				}
+				return s.outboxRepo.Enqueue(ctx, model.OutboxTagUpserted, tag.ID)
+			})
+			if err != nil {
 				continue
 			}
 		}
@@ -387,6 +727,19 @@ func (s *articleService) getOrCreateTags(ctx context.Context, tagNames []string)
 	return tagIDs, nil
 }
 
+// enqueueArticleEvents records search re-index events for an article and its tags
+func (s *articleService) enqueueArticleEvents(ctx context.Context, articleID uuid.UUID, tagIDs []uuid.UUID) error {
+	if err := s.outboxRepo.Enqueue(ctx, model.OutboxArticleUpserted, articleID); err != nil {
+		return err
+	}
+	for _, tagID := range tagIDs {
+		if err := s.outboxRepo.Enqueue(ctx, model.OutboxTagUpserted, tagID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *articleService) invalidateCache(ctx context.Context) {
 	// Delete cached article lists
 	s.redis.Del(ctx, "articles:popular", "articles:new", "articles:hot")
@@ -434,24 +787,28 @@ func generateSlug(title string) string {
 	return slug
 }
 
-func convertToHTML(markdown string) string {
-	// Simplified conversion - in production, use a proper markdown parser
-	// This is just a placeholder
-	html := markdown
-	
-	// Basic conversions
-	html = strings.ReplaceAll(html, "\n\n", "</p><p>")
-	html = "<p>" + html + "</p>"
-	
-	return html
+// authorize returns ErrForbidden unless the user may act on content of the category
+func (s *articleService) authorize(ctx context.Context, userID uuid.UUID, perm model.Permission, categoryID uuid.UUID) error {
+	allowed, err := s.permissions.Authorize(ctx, userID, perm, &categoryID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrForbidden
+	}
+	return nil
 }
 
 // Errors
 var ErrForbidden = &AppError{Code: "FORBIDDEN", Message: "You don't have permission to perform this action"}
+var ErrInvalidReaction = &AppError{Code: "INVALID_REACTION", Message: "Unknown reaction"}
+var ErrTooManyTags = &AppError{Code: "TOO_MANY_TAGS", Message: "Too many tags"}
 
 type AppError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
+	// Details carries structured data for errors that need more than a message
+	Details any `json:"details,omitempty"`
 }
 
 func (e *AppError) Error() string {
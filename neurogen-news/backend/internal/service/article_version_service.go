@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+	"github.com/neurogen-news/backend/internal/textdiff"
+)
+
+// DiffMode is the unit two versions are compared by
+type DiffMode string
+
+const (
+	DiffLines DiffMode = "line"
+	DiffWords DiffMode = "word"
+)
+
+type ArticleVersionListResult struct {
+	Items    []model.ArticleVersion `json:"items"`
+	Total    int                    `json:"total"`
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"pageSize"`
+	HasMore  bool                   `json:"hasMore"`
+}
+
+// ArticleVersionDiff compares the title and content of two versions. From and
+// To come without content, which the chunks already carry.
+type ArticleVersionDiff struct {
+	From    *model.ArticleVersion `json:"from"`
+	To      *model.ArticleVersion `json:"to"`
+	Mode    DiffMode              `json:"mode"`
+	Title   []textdiff.Chunk      `json:"title"`
+	Content []textdiff.Chunk      `json:"content"`
+}
+
+var (
+	ErrArticleVersionNotFound = &AppError{Code: "VERSION_NOT_FOUND", Message: "Version not found"}
+	ErrInvalidDiffMode        = &AppError{Code: "INVALID_DIFF_MODE", Message: "Diff mode must be line or word"}
+	ErrVersionIsCurrent       = &AppError{Code: "VERSION_IS_CURRENT", Message: "The article already has the text of this version"}
+)
+
+func (s *articleService) ListVersions(ctx context.Context, userID, articleID uuid.UUID, page, pageSize int) (*ArticleVersionListResult, error) {
+	if _, err := s.historyArticle(ctx, userID, articleID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	offset := (page - 1) * pageSize
+
+	items, total, err := s.versionRepo.List(ctx, articleID, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []model.ArticleVersion{}
+	}
+
+	return &ArticleVersionListResult{
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		HasMore:  offset+len(items) < total,
+	}, nil
+}
+
+func (s *articleService) GetVersion(ctx context.Context, userID, articleID uuid.UUID, version int) (*model.ArticleVersion, error) {
+	if _, err := s.historyArticle(ctx, userID, articleID); err != nil {
+		return nil, err
+	}
+	return s.getVersion(ctx, articleID, version)
+}
+
+func (s *articleService) DiffVersions(ctx context.Context, userID, articleID uuid.UUID, from, to int, mode DiffMode) (*ArticleVersionDiff, error) {
+	var diff func(a, b string) []textdiff.Chunk
+	switch mode {
+	case DiffLines:
+		diff = textdiff.Lines
+	case DiffWords:
+		diff = textdiff.Words
+	default:
+		return nil, ErrInvalidDiffMode
+	}
+
+	if _, err := s.historyArticle(ctx, userID, articleID); err != nil {
+		return nil, err
+	}
+	fromVersion, err := s.getVersion(ctx, articleID, from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := s.getVersion(ctx, articleID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ArticleVersionDiff{
+		Mode:    mode,
+		Title:   diff(fromVersion.Title, toVersion.Title),
+		Content: diff(fromVersion.Content, toVersion.Content),
+	}
+	fromVersion.Content, toVersion.Content = "", ""
+	result.From, result.To = fromVersion, toVersion
+
+	return result, nil
+}
+
+// RestoreVersion brings back the title and content of a version as a new
+// edit, so it is authorized, moderated and recorded like any other
+func (s *articleService) RestoreVersion(ctx context.Context, userID, articleID uuid.UUID, version int) (*model.Article, error) {
+	article, err := s.historyArticle(ctx, userID, articleID)
+	if err != nil {
+		return nil, err
+	}
+	v, err := s.getVersion(ctx, articleID, version)
+	if err != nil {
+		return nil, err
+	}
+	if v.Title == article.Title && v.Content == article.Content {
+		return nil, ErrVersionIsCurrent
+	}
+
+	return s.update(ctx, userID, articleID, UpdateArticleInput{
+		Title:   &v.Title,
+		Content: &v.Content,
+	}, &v.Version)
+}
+
+// historyArticle loads an article whose history the user may see: their own,
+// or one they may edit as staff
+func (s *articleService) historyArticle(ctx context.Context, userID, articleID uuid.UUID) (*model.Article, error) {
+	article, err := s.articleRepo.GetByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if article.AuthorID != userID {
+		if err := s.authorize(ctx, userID, model.PermArticleEditAny, article.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+	return article, nil
+}
+
+func (s *articleService) getVersion(ctx context.Context, articleID uuid.UUID, version int) (*model.ArticleVersion, error) {
+	v, err := s.versionRepo.Get(ctx, articleID, version)
+	if errors.Is(err, repository.ErrArticleVersionNotFound) {
+		return nil, ErrArticleVersionNotFound
+	}
+	return v, err
+}
+
+// recordVersion stores the current title and content as the next version
+func (s *articleService) recordVersion(ctx context.Context, article *model.Article, editorID uuid.UUID, restoredFrom *int) error {
+	return s.versionRepo.Create(ctx, &model.ArticleVersion{
+		ArticleID:    article.ID,
+		Title:        article.Title,
+		Content:      article.Content,
+		EditedBy:     editorID,
+		RestoredFrom: restoredFrom,
+	})
+}
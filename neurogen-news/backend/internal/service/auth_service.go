@@ -27,6 +27,8 @@ type AuthService interface {
 	RefreshToken(ctx context.Context, refreshToken string) (*AuthResult, error)
 	Logout(ctx context.Context, userID uuid.UUID, refreshToken string) error
 	ValidateToken(ctx context.Context, token string) (*TokenClaims, error)
+	CheckBan(ctx context.Context, userID uuid.UUID) (*BanInfo, error)
+	CurrentRole(ctx context.Context, userID uuid.UUID) (model.UserRole, error)
 	ForgotPassword(ctx context.Context, email string) error
 	ResetPassword(ctx context.Context, token, newPassword string) error
 }
@@ -57,24 +59,33 @@ type TokenClaims struct {
 	jwt.RegisteredClaims
 }
 
+// accessTokenTTL bounds how long a token can carry a stale role or outlive a ban
+const accessTokenTTL = 15 * time.Minute
+
 type authService struct {
-	userRepo  repository.UserRepository
-	redis     *repository.RedisClient
-	jwtSecret []byte
-	logger    *zap.Logger
+	userRepo   repository.UserRepository
+	outboxRepo repository.OutboxRepository
+	tx         repository.Transactor
+	redis      *repository.RedisClient
+	jwtSecret  []byte
+	logger     *zap.Logger
 }
 
 func NewAuthService(
 	userRepo repository.UserRepository,
+	outboxRepo repository.OutboxRepository,
+	tx repository.Transactor,
 	redis *repository.RedisClient,
 	jwtSecret string,
 	logger *zap.Logger,
 ) AuthService {
 	return &authService{
-		userRepo:  userRepo,
-		redis:     redis,
-		jwtSecret: []byte(jwtSecret),
-		logger:    logger,
+		userRepo:   userRepo,
+		outboxRepo: outboxRepo,
+		tx:         tx,
+		redis:      redis,
+		jwtSecret:  []byte(jwtSecret),
+		logger:     logger,
 	}
 }
 
@@ -94,7 +105,13 @@ func (s *authService) Register(ctx context.Context, input RegisterInput) (*AuthR
 		Role:         model.RoleUser,
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		return s.outboxRepo.Enqueue(ctx, model.OutboxUserUpserted, user.ID)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -113,7 +130,7 @@ func (s *authService) Login(ctx context.Context, input LoginInput) (*AuthResult,
 	}
 
 	// Check if user is banned
-	if user.IsBanned {
+	if user.IsBannedAt(time.Now()) {
 		return nil, ErrUserBanned
 	}
 
@@ -138,7 +155,8 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*A
 		return nil, err
 	}
 
-	if user.IsBanned {
+	if user.IsBannedAt(time.Now()) {
+		_ = s.userRepo.DeleteUserSessions(ctx, user.ID)
 		return nil, ErrUserBanned
 	}
 
@@ -185,6 +203,19 @@ func (s *authService) ValidateToken(ctx context.Context, tokenString string) (*T
 	return claims, nil
 }
 
+// CheckBan returns the active ban of a user, nil when there is none. Access
+// tokens outlive a ban by up to their lifetime, so authenticated requests check it;
+// an error means the ban state is unknown and the caller should refuse access.
+func (s *authService) CheckBan(ctx context.Context, userID uuid.UUID) (*BanInfo, error) {
+	return activeBan(ctx, s.redis, s.userRepo, s.logger, userID)
+}
+
+// CurrentRole returns the role of a user when it changed after their tokens
+// were issued, empty otherwise
+func (s *authService) CurrentRole(ctx context.Context, userID uuid.UUID) (model.UserRole, error) {
+	return changedRole(ctx, s.redis, userID)
+}
+
 func (s *authService) ForgotPassword(ctx context.Context, email string) error {
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
@@ -245,7 +276,7 @@ func (s *authService) ResetPassword(ctx context.Context, token, newPassword stri
 
 func (s *authService) generateTokens(ctx context.Context, user *model.User, userAgent, ip string) (*AuthResult, error) {
 	// Access token - 15 minutes
-	accessExpiry := time.Now().Add(15 * time.Minute)
+	accessExpiry := time.Now().Add(accessTokenTTL)
 	accessClaims := TokenClaims{
 		UserID:   user.ID,
 		Username: user.Username,
@@ -0,0 +1,743 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+)
+
+// AutomodService checks new comments and articles against admin-managed rules.
+// Rules are read through a short Redis cache that edits clear, so changes
+// apply on every instance without a deploy.
+type AutomodService interface {
+	// Check evaluates the enabled rules without changing any state. It fails
+	// open: when rules cannot be loaded the content is allowed and the error
+	// is logged.
+	Check(ctx context.Context, input AutomodInput) *AutomodVerdict
+	// Apply records a verdict in the audit log and files a report for held
+	// and shadow-hidden content. targetID is the stored content; rejected
+	// content is never stored and is logged against its author. For stored
+	// content, allowed included, it also records what later checks compare
+	// against, once the transaction commits.
+	Apply(ctx context.Context, verdict *AutomodVerdict, input AutomodInput, targetID uuid.UUID) error
+
+	// Rules
+	ListRules(ctx context.Context) ([]model.AutomodRule, error)
+	CreateRule(ctx context.Context, moderatorID uuid.UUID, input AutomodRuleInput) (*model.AutomodRule, error)
+	UpdateRule(ctx context.Context, moderatorID, id uuid.UUID, input AutomodRuleInput) (*model.AutomodRule, error)
+	DeleteRule(ctx context.Context, moderatorID, id uuid.UUID) error
+}
+
+// AutomodInput is the content being written
+type AutomodInput struct {
+	Target   string // comment or article
+	AuthorID uuid.UUID
+	Text     string
+}
+
+type AutomodMatch struct {
+	RuleID uuid.UUID             `json:"ruleId"`
+	Rule   string                `json:"rule"`
+	Kind   model.AutomodRuleKind `json:"kind"`
+	Action model.AutomodAction   `json:"action"`
+	Detail string                `json:"detail,omitempty"`
+}
+
+// AutomodVerdict is the most severe action among the matched rules
+type AutomodVerdict struct {
+	Action  model.AutomodAction `json:"action"`
+	Matches []AutomodMatch      `json:"matches,omitempty"`
+
+	// State the rules keep about stored content, written by Apply
+	records []automodRecord
+}
+
+// State is the automod_state to store with the content
+func (v *AutomodVerdict) State() *string {
+	var state string
+	switch v.Action {
+	case model.AutomodHold:
+		state = model.AutomodStateHeld
+	case model.AutomodShadowHide:
+		state = model.AutomodStateShadow
+	default:
+		return nil
+	}
+	return &state
+}
+
+type AutomodRuleInput struct {
+	Name      string                `json:"name"`
+	Kind      model.AutomodRuleKind `json:"kind"`
+	Target    string                `json:"target"`
+	Action    model.AutomodAction   `json:"action"`
+	Config    json.RawMessage       `json:"config"`
+	Priority  int                   `json:"priority"`
+	IsEnabled *bool                 `json:"isEnabled"`
+}
+
+// Rule configs, see model.AutomodRule
+type (
+	stopwordsConfig struct {
+		Words []string `json:"words"`
+	}
+	regexConfig struct {
+		Patterns []string `json:"patterns"`
+	}
+	linksConfig struct {
+		Max int `json:"max"`
+	}
+	capsConfig struct {
+		Ratio      float64 `json:"ratio"`
+		MinLetters int     `json:"minLetters"`
+	}
+	duplicateConfig struct {
+		WindowMinutes int  `json:"windowMinutes"`
+		Global        bool `json:"global"`
+	}
+	newAccountConfig struct {
+		MaxAgeHours   int `json:"maxAgeHours"`
+		Limit         int `json:"limit"`
+		WindowMinutes int `json:"windowMinutes"`
+	}
+)
+
+const (
+	automodRulesKey      = "automod:rules"
+	automodRulesCacheTTL = 5 * time.Minute
+
+	// Short texts ("спасибо!") repeat naturally and are not checked for duplicates
+	automodDuplicateMinLength = 20
+)
+
+var (
+	ErrContentRejected     = &AppError{Code: "CONTENT_REJECTED", Message: "Content violates community rules"}
+	ErrInvalidAutomodRule  = &AppError{Code: "INVALID_AUTOMOD_RULE", Message: "Invalid automoderation rule"}
+	ErrAutomodRuleNotFound = &AppError{Code: "AUTOMOD_RULE_NOT_FOUND", Message: "Automoderation rule not found"}
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\bhttps?://|\bwww\.`)
+
+// automodSeverity orders actions; the verdict takes the most severe match
+var automodSeverity = map[model.AutomodAction]int{
+	model.AutomodAllow:      0,
+	model.AutomodHold:       1,
+	model.AutomodShadowHide: 2,
+	model.AutomodReject:     3,
+}
+
+// automodSubject is the content with derived data the matchers share
+type automodSubject struct {
+	AutomodInput
+	normalized string
+	words      map[string]bool
+	author     *model.User
+
+	// Matchers queue their state changes here rather than making them
+	records []automodRecord
+}
+
+// automodRecord stores what a rule remembers about content once it is saved,
+// such as a content hash or a rate counter
+type automodRecord func(ctx context.Context) error
+
+// automodMatcher reports whether the content matches and, if so, what matched
+type automodMatcher func(ctx context.Context, subject *automodSubject) (bool, string, error)
+
+type compiledRule struct {
+	updatedAt time.Time
+	match     automodMatcher
+}
+
+type automodService struct {
+	automodRepo repository.AutomodRepository
+	reportRepo  repository.ReportRepository
+	userRepo    repository.UserRepository
+	tx          repository.Transactor
+	moderation  ModerationService
+	redis       *repository.RedisClient
+	logger      *zap.Logger
+
+	mu       sync.Mutex
+	compiled map[uuid.UUID]*compiledRule
+}
+
+func NewAutomodService(
+	automodRepo repository.AutomodRepository,
+	reportRepo repository.ReportRepository,
+	userRepo repository.UserRepository,
+	tx repository.Transactor,
+	moderation ModerationService,
+	redis *repository.RedisClient,
+	logger *zap.Logger,
+) AutomodService {
+	return &automodService{
+		automodRepo: automodRepo,
+		reportRepo:  reportRepo,
+		userRepo:    userRepo,
+		tx:          tx,
+		moderation:  moderation,
+		redis:       redis,
+		logger:      logger,
+		compiled:    make(map[uuid.UUID]*compiledRule),
+	}
+}
+
+// Check runs the rules in priority order. A matching allow rule ends the
+// evaluation and lets the content through.
+func (s *automodService) Check(ctx context.Context, input AutomodInput) *AutomodVerdict {
+	verdict := &AutomodVerdict{Action: model.AutomodAllow}
+
+	rules, err := s.loadRules(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load automod rules", zap.Error(err))
+		return verdict
+	}
+
+	subject := newAutomodSubject(input)
+	for _, rule := range rules {
+		if !rule.IsEnabled || (rule.Target != model.AutomodTargetAll && rule.Target != input.Target) {
+			continue
+		}
+
+		matcher, err := s.matcher(rule)
+		if err != nil {
+			s.logger.Warn("Skipping invalid automod rule", zap.String("rule_id", rule.ID.String()), zap.Error(err))
+			continue
+		}
+
+		matched, detail, err := matcher(ctx, subject)
+		if err != nil {
+			s.logger.Warn("Automod rule failed", zap.String("rule_id", rule.ID.String()), zap.Error(err))
+			continue
+		}
+		if !matched {
+			continue
+		}
+
+		if rule.Action == model.AutomodAllow {
+			return &AutomodVerdict{Action: model.AutomodAllow, records: subject.records}
+		}
+
+		verdict.Matches = append(verdict.Matches, AutomodMatch{
+			RuleID: rule.ID,
+			Rule:   rule.Name,
+			Kind:   rule.Kind,
+			Action: rule.Action,
+			Detail: detail,
+		})
+		if automodSeverity[rule.Action] > automodSeverity[verdict.Action] {
+			verdict.Action = rule.Action
+		}
+	}
+
+	verdict.records = subject.records
+	return verdict
+}
+
+func (s *automodService) Apply(ctx context.Context, verdict *AutomodVerdict, input AutomodInput, targetID uuid.UUID) error {
+	if verdict == nil {
+		return nil
+	}
+	if verdict.Action != model.AutomodReject {
+		records := verdict.records
+		repository.AfterCommit(ctx, func(ctx context.Context) {
+			for _, record := range records {
+				if err := record(ctx); err != nil {
+					s.logger.Warn("Failed to record automod state", zap.Error(err))
+				}
+			}
+		})
+	}
+	if verdict.Action == model.AutomodAllow {
+		return nil
+	}
+
+	reason := verdict.summary()
+	action := &model.ModerationAction{
+		ModeratorID: model.SystemUserID,
+		TargetType:  input.Target,
+		TargetID:    targetID,
+		Reason:      &reason,
+	}
+	var after interface{} = verdict
+
+	switch verdict.Action {
+	case model.AutomodHold:
+		action.Action = model.ModerationHold
+	case model.AutomodShadowHide:
+		action.Action = model.ModerationShadowHide
+	case model.AutomodReject:
+		action.Action = model.ModerationReject
+		action.TargetType = model.ReportTargetUser
+		action.TargetID = input.AuthorID
+		after = map[string]interface{}{
+			"target":  input.Target,
+			"excerpt": excerpt(input.Text, 500),
+			"verdict": verdict,
+		}
+	}
+
+	if err := s.moderation.Record(ctx, action, nil, after); err != nil {
+		return err
+	}
+	if verdict.Action == model.AutomodReject {
+		return nil
+	}
+
+	// Held and hidden content waits in the report queue for a moderator
+	report := &model.Report{
+		ReporterID:  model.SystemUserID,
+		TargetType:  input.Target,
+		TargetID:    targetID,
+		Reason:      model.ReportReasonAutomod,
+		Description: &reason,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil && !errors.Is(err, repository.ErrReportExists) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *automodService) ListRules(ctx context.Context) ([]model.AutomodRule, error) {
+	rules, err := s.automodRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []model.AutomodRule{}
+	}
+	return rules, nil
+}
+
+func (s *automodService) CreateRule(ctx context.Context, moderatorID uuid.UUID, input AutomodRuleInput) (*model.AutomodRule, error) {
+	rule := &model.AutomodRule{IsEnabled: true, CreatedBy: &moderatorID}
+	if err := s.applyRuleInput(rule, input); err != nil {
+		return nil, err
+	}
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.automodRepo.Create(ctx, rule); err != nil {
+			return err
+		}
+		return s.moderation.Record(ctx, &model.ModerationAction{
+			ModeratorID: moderatorID,
+			TargetType:  model.AuditTargetAutomodRule,
+			TargetID:    rule.ID,
+			Action:      model.ModerationCreate,
+		}, nil, rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateRules(ctx)
+	return rule, nil
+}
+
+func (s *automodService) UpdateRule(ctx context.Context, moderatorID, id uuid.UUID, input AutomodRuleInput) (*model.AutomodRule, error) {
+	before, err := s.automodRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAutomodRuleNotFound) {
+			return nil, ErrAutomodRuleNotFound
+		}
+		return nil, err
+	}
+
+	rule := *before
+	if err := s.applyRuleInput(&rule, input); err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.automodRepo.Update(ctx, &rule); err != nil {
+			return err
+		}
+		return s.moderation.Record(ctx, &model.ModerationAction{
+			ModeratorID: moderatorID,
+			TargetType:  model.AuditTargetAutomodRule,
+			TargetID:    id,
+			Action:      model.ModerationUpdate,
+		}, before, rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateRules(ctx)
+	return &rule, nil
+}
+
+func (s *automodService) DeleteRule(ctx context.Context, moderatorID, id uuid.UUID) error {
+	before, err := s.automodRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAutomodRuleNotFound) {
+			return ErrAutomodRuleNotFound
+		}
+		return err
+	}
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.automodRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.moderation.Record(ctx, &model.ModerationAction{
+			ModeratorID: moderatorID,
+			TargetType:  model.AuditTargetAutomodRule,
+			TargetID:    id,
+			Action:      model.ModerationDelete,
+		}, before, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateRules(ctx)
+	return nil
+}
+
+// applyRuleInput validates the input and copies it onto the rule
+func (s *automodService) applyRuleInput(rule *model.AutomodRule, input AutomodRuleInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > 100 {
+		return ErrInvalidAutomodRule
+	}
+	if _, ok := automodSeverity[input.Action]; !ok {
+		return ErrInvalidAutomodRule
+	}
+
+	target := input.Target
+	if target == "" {
+		target = model.AutomodTargetAll
+	}
+	if target != model.AutomodTargetAll && target != model.AutomodTargetComment && target != model.AutomodTargetArticle {
+		return ErrInvalidAutomodRule
+	}
+
+	config := input.Config
+	if len(config) == 0 {
+		config = json.RawMessage(`{}`)
+	}
+
+	candidate := *rule
+	candidate.Name = name
+	candidate.Kind = input.Kind
+	candidate.Target = target
+	candidate.Action = input.Action
+	candidate.Config = config
+	candidate.Priority = input.Priority
+	if input.IsEnabled != nil {
+		candidate.IsEnabled = *input.IsEnabled
+	}
+
+	if _, err := s.compile(candidate); err != nil {
+		return &AppError{Code: ErrInvalidAutomodRule.Code, Message: ErrInvalidAutomodRule.Message + ": " + err.Error()}
+	}
+
+	*rule = candidate
+	return nil
+}
+
+func (s *automodService) loadRules(ctx context.Context) ([]model.AutomodRule, error) {
+	var rules []model.AutomodRule
+	err := s.redis.GetJSON(ctx, automodRulesKey, &rules)
+	if err == nil {
+		return rules, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		s.logger.Warn("Failed to read cached automod rules", zap.Error(err))
+	}
+
+	rules, err = s.automodRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []model.AutomodRule{}
+	}
+
+	if err := s.redis.SetJSON(ctx, automodRulesKey, rules, automodRulesCacheTTL); err != nil {
+		s.logger.Warn("Failed to cache automod rules", zap.Error(err))
+	}
+	return rules, nil
+}
+
+func (s *automodService) invalidateRules(ctx context.Context) {
+	if err := s.redis.Del(ctx, automodRulesKey).Err(); err != nil {
+		s.logger.Warn("Failed to clear automod rules cache", zap.Error(err))
+	}
+}
+
+// matcher returns the compiled rule, recompiling it after an edit
+func (s *automodService) matcher(rule model.AutomodRule) (automodMatcher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.compiled[rule.ID]; ok && c.updatedAt.Equal(rule.UpdatedAt) {
+		return c.match, nil
+	}
+
+	match, err := s.compile(rule)
+	if err != nil {
+		return nil, err
+	}
+	s.compiled[rule.ID] = &compiledRule{updatedAt: rule.UpdatedAt, match: match}
+	return match, nil
+}
+
+func (s *automodService) compile(rule model.AutomodRule) (automodMatcher, error) {
+	switch rule.Kind {
+	case model.AutomodStopwords:
+		var cfg stopwordsConfig
+		if err := json.Unmarshal(rule.Config, &cfg); err != nil {
+			return nil, err
+		}
+		if len(cfg.Words) == 0 {
+			return nil, errors.New("words are required")
+		}
+
+		var words, phrases []string
+		for _, w := range cfg.Words {
+			w = normalizeText(w)
+			switch {
+			case w == "":
+			case strings.Contains(w, " "):
+				phrases = append(phrases, w)
+			default:
+				words = append(words, w)
+			}
+		}
+
+		return func(ctx context.Context, subject *automodSubject) (bool, string, error) {
+			for _, w := range words {
+				if subject.words[w] {
+					return true, w, nil
+				}
+			}
+			for _, p := range phrases {
+				if strings.Contains(subject.normalized, p) {
+					return true, p, nil
+				}
+			}
+			return false, "", nil
+		}, nil
+
+	case model.AutomodRegex:
+		var cfg regexConfig
+		if err := json.Unmarshal(rule.Config, &cfg); err != nil {
+			return nil, err
+		}
+		if len(cfg.Patterns) == 0 {
+			return nil, errors.New("patterns are required")
+		}
+
+		patterns := make([]*regexp.Regexp, len(cfg.Patterns))
+		for i, p := range cfg.Patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, err
+			}
+			patterns[i] = re
+		}
+
+		return func(ctx context.Context, subject *automodSubject) (bool, string, error) {
+			for _, re := range patterns {
+				if m := re.FindString(subject.Text); m != "" {
+					return true, excerpt(m, 100), nil
+				}
+			}
+			return false, "", nil
+		}, nil
+
+	case model.AutomodLinks:
+		var cfg linksConfig
+		if err := json.Unmarshal(rule.Config, &cfg); err != nil {
+			return nil, err
+		}
+		if cfg.Max < 0 {
+			return nil, errors.New("max must not be negative")
+		}
+
+		return func(ctx context.Context, subject *automodSubject) (bool, string, error) {
+			count := len(linkPattern.FindAllStringIndex(subject.Text, -1))
+			if count > cfg.Max {
+				return true, fmt.Sprintf("%d links", count), nil
+			}
+			return false, "", nil
+		}, nil
+
+	case model.AutomodCaps:
+		var cfg capsConfig
+		if err := json.Unmarshal(rule.Config, &cfg); err != nil {
+			return nil, err
+		}
+		if cfg.Ratio <= 0 || cfg.Ratio > 1 {
+			return nil, errors.New("ratio must be in (0, 1]")
+		}
+
+		return func(ctx context.Context, subject *automodSubject) (bool, string, error) {
+			letters, upper := 0, 0
+			for _, r := range subject.Text {
+				if unicode.IsLetter(r) {
+					letters++
+					if unicode.IsUpper(r) {
+						upper++
+					}
+				}
+			}
+			if letters == 0 || letters < cfg.MinLetters {
+				return false, "", nil
+			}
+			if ratio := float64(upper) / float64(letters); ratio >= cfg.Ratio {
+				return true, fmt.Sprintf("%.0f%% caps", ratio*100), nil
+			}
+			return false, "", nil
+		}, nil
+
+	case model.AutomodDuplicate:
+		var cfg duplicateConfig
+		if err := json.Unmarshal(rule.Config, &cfg); err != nil {
+			return nil, err
+		}
+		if cfg.WindowMinutes <= 0 {
+			return nil, errors.New("windowMinutes must be positive")
+		}
+		window := time.Duration(cfg.WindowMinutes) * time.Minute
+		ruleID := rule.ID.String()
+
+		// The first stored copy claims the content hash; repeats within the window match
+		return func(ctx context.Context, subject *automodSubject) (bool, string, error) {
+			if len([]rune(subject.normalized)) < automodDuplicateMinLength {
+				return false, "", nil
+			}
+
+			scope := subject.AuthorID.String()
+			if cfg.Global {
+				scope = "all"
+			}
+			sum := sha256.Sum256([]byte(subject.normalized))
+			key := "automod:dup:" + ruleID + ":" + subject.Target + ":" + scope + ":" + hex.EncodeToString(sum[:])
+
+			seen, err := s.redis.Exists(ctx, key).Result()
+			if err != nil {
+				return false, "", err
+			}
+			if seen > 0 {
+				return true, "repeated content", nil
+			}
+			subject.records = append(subject.records, func(ctx context.Context) error {
+				return s.redis.SetNX(ctx, key, 1, window).Err()
+			})
+			return false, "", nil
+		}, nil
+
+	case model.AutomodNewAccount:
+		var cfg newAccountConfig
+		if err := json.Unmarshal(rule.Config, &cfg); err != nil {
+			return nil, err
+		}
+		if cfg.MaxAgeHours <= 0 || cfg.Limit <= 0 || cfg.WindowMinutes <= 0 {
+			return nil, errors.New("maxAgeHours, limit and windowMinutes must be positive")
+		}
+		maxAge := time.Duration(cfg.MaxAgeHours) * time.Hour
+		window := time.Duration(cfg.WindowMinutes) * time.Minute
+		ruleID := rule.ID.String()
+
+		return func(ctx context.Context, subject *automodSubject) (bool, string, error) {
+			author, err := s.author(ctx, subject)
+			if err != nil {
+				return false, "", err
+			}
+			if time.Since(author.CreatedAt) >= maxAge {
+				return false, "", nil
+			}
+
+			key := "automod:throttle:" + ruleID + ":" + subject.Target + ":" + subject.AuthorID.String()
+			count, err := s.redis.Get(ctx, key).Int()
+			if err != nil && !errors.Is(err, redis.Nil) {
+				return false, "", err
+			}
+			// Held content is stored too, so it counts as well
+			subject.records = append(subject.records, func(ctx context.Context) error {
+				_, err := s.redis.CheckRateLimit(ctx, key, cfg.Limit, window)
+				return err
+			})
+			if count >= cfg.Limit {
+				return true, fmt.Sprintf("more than %d in %d min from a new account", cfg.Limit, cfg.WindowMinutes), nil
+			}
+			return false, "", nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown rule kind %q", rule.Kind)
+}
+
+// author loads the author once per check
+func (s *automodService) author(ctx context.Context, subject *automodSubject) (*model.User, error) {
+	if subject.author == nil {
+		author, err := s.userRepo.GetByID(ctx, subject.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		subject.author = author
+	}
+	return subject.author, nil
+}
+
+func newAutomodSubject(input AutomodInput) *automodSubject {
+	normalized := normalizeText(input.Text)
+
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(normalized, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[w] = true
+	}
+
+	return &automodSubject{
+		AutomodInput: input,
+		normalized:   normalized,
+		words:        words,
+	}
+}
+
+// normalizeText lowercases and collapses whitespace
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// summary lists the matched rules for reports and the audit log
+func (v *AutomodVerdict) summary() string {
+	parts := make([]string, len(v.Matches))
+	for i, m := range v.Matches {
+		parts[i] = m.Rule
+		if m.Detail != "" {
+			parts[i] += " (" + m.Detail + ")"
+		}
+	}
+	return "Автомодерация: " + strings.Join(parts, "; ")
+}
+
+func excerpt(text string, maxRunes int) string {
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+	return string(runes[:maxRunes]) + "…"
+}
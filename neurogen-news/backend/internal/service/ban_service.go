@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+)
+
+type BanService interface {
+	Ban(ctx context.Context, moderatorID, userID uuid.UUID, input BanInput) (*model.UserBan, error)
+	Unban(ctx context.Context, moderatorID, userID uuid.UUID, reason string) error
+	GetActive(ctx context.Context, userID uuid.UUID) (*model.UserBan, error)
+	GetHistory(ctx context.Context, userID uuid.UUID, limit, offset int) (*BanListResult, error)
+}
+
+// BanInput describes a ban; without Days or Permanent it is rejected
+type BanInput struct {
+	Reason    string `json:"reason" validate:"required,max=500"`
+	Days      int    `json:"days" validate:"min=0"`
+	Permanent bool   `json:"permanent"`
+}
+
+type BanListResult struct {
+	Items   []model.UserBan `json:"items"`
+	Total   int             `json:"total"`
+	HasMore bool            `json:"hasMore"`
+}
+
+// BanInfo is what request-time checks know about an active ban
+type BanInfo struct {
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until,omitempty"` // nil for permanent bans
+}
+
+const (
+	banKeyPrefix      = "ban:"
+	noBanKeyPrefix    = "ban:none:"
+	banExpiryInterval = time.Minute
+	maxBanDays        = 3650
+
+	// Markers are a cache of users.is_banned; the database stays the source of truth
+	banMarkerTTL   = 24 * time.Hour
+	noBanMarkerTTL = time.Minute
+)
+
+var (
+	ErrInvalidBan    = &AppError{Code: "INVALID_BAN", Message: "A ban needs a reason and a duration"}
+	ErrCannotBanUser = &AppError{Code: "CANNOT_BAN_USER", Message: "This user cannot be banned"}
+	ErrUserNotBanned = &AppError{Code: "USER_NOT_BANNED", Message: "User is not banned"}
+)
+
+type banService struct {
+	banRepo        repository.BanRepository
+	userRepo       repository.UserRepository
+	moderationRepo repository.ModerationRepository
+	tx             repository.Transactor
+	redis          *repository.RedisClient
+	hub            Broadcaster
+	logger         *zap.Logger
+}
+
+func NewBanService(
+	banRepo repository.BanRepository,
+	userRepo repository.UserRepository,
+	moderationRepo repository.ModerationRepository,
+	tx repository.Transactor,
+	redis *repository.RedisClient,
+	hub Broadcaster,
+	logger *zap.Logger,
+) BanService {
+	return &banService{
+		banRepo:        banRepo,
+		userRepo:       userRepo,
+		moderationRepo: moderationRepo,
+		tx:             tx,
+		redis:          redis,
+		hub:            hub,
+		logger:         logger,
+	}
+}
+
+// Ban records the ban, mirrors it on the user and revokes their sessions.
+// Called inside another transaction it joins it; the ban marker is set and
+// open WebSocket connections are closed only once the outermost one commits.
+func (s *banService) Ban(ctx context.Context, moderatorID, userID uuid.UUID, input BanInput) (*model.UserBan, error) {
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" || (!input.Permanent && input.Days <= 0) || input.Days > maxBanDays {
+		return nil, ErrInvalidBan
+	}
+	if moderatorID == userID {
+		return nil, ErrCannotBanUser
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Staff lose their role before they can be banned
+	if isStaff(user.Role) {
+		return nil, ErrCannotBanUser
+	}
+
+	ban := &model.UserBan{
+		UserID:      userID,
+		BannedBy:    moderatorID,
+		Reason:      reason,
+		IsPermanent: input.Permanent,
+	}
+	if !input.Permanent {
+		expiresAt := time.Now().AddDate(0, 0, input.Days)
+		ban.ExpiresAt = &expiresAt
+	}
+
+	previous, err := s.activeBanRecord(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		// A new ban replaces whatever is in force
+		if _, err := s.banRepo.RevokeActive(ctx, userID, moderatorID); err != nil {
+			return err
+		}
+		if err := s.banRepo.Create(ctx, ban); err != nil {
+			return err
+		}
+		if err := s.userRepo.SetBanned(ctx, userID, &ban.Reason, ban.ExpiresAt); err != nil {
+			return err
+		}
+		if err := s.userRepo.DeleteUserSessions(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.moderationRepo.CreateAction(ctx, &model.ModerationAction{
+			ModeratorID: moderatorID,
+			TargetType:  model.ReportTargetUser,
+			TargetID:    userID,
+			Action:      model.ModerationBan,
+			Reason:      &ban.Reason,
+			Before:      snapshot(previous),
+			After:       snapshot(ban),
+		}); err != nil {
+			return err
+		}
+
+		repository.AfterCommit(ctx, func(ctx context.Context) {
+			setBanMarker(ctx, s.redis, s.logger, userID, &BanInfo{Reason: ban.Reason, Until: ban.ExpiresAt})
+			s.hub.DisconnectUser(userID)
+			s.logger.Info("User banned",
+				zap.String("user_id", userID.String()),
+				zap.String("moderator_id", moderatorID.String()),
+				zap.Bool("permanent", ban.IsPermanent),
+			)
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ban, nil
+}
+
+func (s *banService) Unban(ctx context.Context, moderatorID, userID uuid.UUID, reason string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.IsBannedAt(time.Now()) {
+		return ErrUserNotBanned
+	}
+
+	previous, err := s.activeBanRecord(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.banRepo.RevokeActive(ctx, userID, moderatorID); err != nil {
+			return err
+		}
+		if err := s.userRepo.SetBanned(ctx, userID, nil, nil); err != nil {
+			return err
+		}
+		if err := s.moderationRepo.CreateAction(ctx, &model.ModerationAction{
+			ModeratorID: moderatorID,
+			TargetType:  model.ReportTargetUser,
+			TargetID:    userID,
+			Action:      model.ModerationUnban,
+			Reason:      optionalString(reason),
+			Before:      snapshot(previous),
+		}); err != nil {
+			return err
+		}
+
+		repository.AfterCommit(ctx, func(ctx context.Context) {
+			if err := s.redis.Del(ctx, banKey(userID)).Err(); err != nil {
+				s.logger.Warn("Failed to clear ban marker", zap.String("user_id", userID.String()), zap.Error(err))
+			}
+		})
+		return nil
+	})
+}
+
+func (s *banService) GetActive(ctx context.Context, userID uuid.UUID) (*model.UserBan, error) {
+	return s.banRepo.GetActive(ctx, userID)
+}
+
+func (s *banService) GetHistory(ctx context.Context, userID uuid.UUID, limit, offset int) (*BanListResult, error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	bans, total, err := s.banRepo.ListByUser(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BanListResult{
+		Items:   bans,
+		Total:   total,
+		HasMore: offset+len(bans) < total,
+	}, nil
+}
+
+// activeBanRecord is the audit snapshot of the ban in force, if any
+func (s *banService) activeBanRecord(ctx context.Context, userID uuid.UUID) (*model.UserBan, error) {
+	ban, err := s.banRepo.GetActive(ctx, userID)
+	if errors.Is(err, repository.ErrBanNotFound) {
+		return nil, nil
+	}
+	return ban, err
+}
+
+// setBanMarker lets request-time checks see the ban without a database query.
+// The marker of a temporary ban expires together with the ban.
+func setBanMarker(ctx context.Context, rdb *repository.RedisClient, logger *zap.Logger, userID uuid.UUID, info *BanInfo) {
+	ttl := banMarkerTTL
+	if info.Until != nil && time.Until(*info.Until) < ttl {
+		ttl = time.Until(*info.Until)
+	}
+
+	if err := rdb.SetJSON(ctx, banKey(userID), info, ttl); err != nil {
+		logger.Warn("Failed to set ban marker", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+	rdb.Del(ctx, noBanKey(userID))
+}
+
+func banKey(userID uuid.UUID) string {
+	return banKeyPrefix + userID.String()
+}
+
+func noBanKey(userID uuid.UUID) string {
+	return noBanKeyPrefix + userID.String()
+}
+
+// activeBan returns the ban in force for a user; nil means not banned. The
+// Redis markers answer most checks; on a miss or a Redis error the user row
+// decides, so bans older than the markers or lost to a flush still apply.
+func activeBan(ctx context.Context, rdb *repository.RedisClient, userRepo repository.UserRepository, logger *zap.Logger, userID uuid.UUID) (*BanInfo, error) {
+	var info BanInfo
+	err := rdb.GetJSON(ctx, banKey(userID), &info)
+	if err == nil {
+		return &info, nil
+	}
+	if errors.Is(err, redis.Nil) {
+		if n, err := rdb.Exists(ctx, noBanKey(userID)).Result(); err == nil && n > 0 {
+			return nil, nil
+		}
+	} else {
+		logger.Warn("Failed to read ban marker", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsBannedAt(time.Now()) {
+		if err := rdb.Set(ctx, noBanKey(userID), 1, noBanMarkerTTL).Err(); err != nil {
+			logger.Debug("Failed to cache ban check", zap.String("user_id", userID.String()), zap.Error(err))
+		}
+		return nil, nil
+	}
+
+	info = BanInfo{Until: user.BannedUntil}
+	if user.BanReason != nil {
+		info.Reason = *user.BanReason
+	}
+	setBanMarker(ctx, rdb, logger, userID, &info)
+	return &info, nil
+}
+
+// BanExpirer clears users.is_banned once temporary bans run out. Request-time
+// checks already treat expired bans as lifted; this keeps the table honest.
+type BanExpirer struct {
+	banRepo repository.BanRepository
+	logger  *zap.Logger
+}
+
+func NewBanExpirer(banRepo repository.BanRepository, logger *zap.Logger) *BanExpirer {
+	return &BanExpirer{
+		banRepo: banRepo,
+		logger:  logger,
+	}
+}
+
+// Run clears expired bans until ctx is cancelled
+func (e *BanExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(banExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			ids, err := e.banRepo.ClearExpired(ctx)
+			if err != nil {
+				e.logger.Warn("Failed to clear expired bans", zap.Error(err))
+				continue
+			}
+			for _, id := range ids {
+				e.logger.Info("Ban expired", zap.String("user_id", id.String()))
+			}
+		}
+	}
+}
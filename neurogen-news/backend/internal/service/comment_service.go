@@ -2,7 +2,12 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -13,15 +18,19 @@ import (
 
 type CommentService interface {
 	Create(ctx context.Context, userID uuid.UUID, input CreateCommentInput) (*model.Comment, error)
-	GetByID(ctx context.Context, id uuid.UUID) (*model.Comment, error)
+	GetByID(ctx context.Context, id uuid.UUID, viewerID *uuid.UUID) (*model.Comment, error)
 	Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, content string) (*model.Comment, error)
 	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
 
 	// Lists
 	GetByArticle(ctx context.Context, articleID uuid.UUID, params CommentListParams) (*CommentListResult, error)
-	GetReplies(ctx context.Context, parentID uuid.UUID, limit, offset int) ([]model.Comment, error)
+	GetReplies(ctx context.Context, parentID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]model.Comment, error)
 	GetByUser(ctx context.Context, userID uuid.UUID, limit, offset int) (*CommentListResult, error)
 
+	// Tree
+	GetTree(ctx context.Context, articleID uuid.UUID, viewerID *uuid.UUID, params CommentTreeParams) (*CommentTreeResult, error)
+	GetThread(ctx context.Context, parentID uuid.UUID, viewerID *uuid.UUID, params CommentTreeParams) (*CommentTreeResult, error)
+
 	// Reactions
 	AddReaction(ctx context.Context, userID, commentID uuid.UUID, emoji string) error
 	RemoveReaction(ctx context.Context, userID, commentID uuid.UUID) error
@@ -30,13 +39,16 @@ type CommentService interface {
 type CreateCommentInput struct {
 	ArticleID uuid.UUID  `json:"articleId" validate:"required"`
 	ParentID  *uuid.UUID `json:"parentId,omitempty"`
-	Content   string     `json:"content" validate:"required,min=1,max=10000"`
+	Content   string     `json:"content" validate:"required,min=1"` // up to the maxCommentLength setting
 }
 
 type CommentListParams struct {
 	Sort     string `query:"sort" validate:"omitempty,oneof=new popular old"`
 	Page     int    `query:"page" validate:"min=1"`
 	PageSize int    `query:"pageSize" validate:"min=1,max=100"`
+
+	// Authors see their own held comments
+	ViewerID *uuid.UUID `query:"-"`
 }
 
 type CommentListResult struct {
@@ -47,31 +59,98 @@ type CommentListResult struct {
 	HasMore  bool            `json:"hasMore"`
 }
 
+// CommentTreeParams controls how much of a comment tree is loaded at once
+type CommentTreeParams struct {
+	Sort    string `query:"sort" validate:"omitempty,oneof=new popular old"`
+	Cursor  string `query:"cursor"`
+	Limit   int    `query:"limit"`   // comments on the top level of the page
+	Depth   int    `query:"depth"`   // levels of replies below the top level
+	Replies int    `query:"replies"` // replies per comment
+}
+
+type CommentTreeResult struct {
+	Items      []model.Comment `json:"items"`
+	Total      int             `json:"total"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+	HasMore    bool            `json:"hasMore"`
+}
+
+// Bounds of a tree request
+const (
+	commentTreeMaxLimit   = 50
+	commentTreeMaxDepth   = 6
+	commentTreeMaxReplies = 20
+	commentTreeMaxNodes   = 500
+)
+
+var (
+	ErrInvalidCursor  = &AppError{Code: "INVALID_CURSOR", Message: "Invalid cursor"}
+	ErrCommentTooLong = &AppError{Code: "COMMENT_TOO_LONG", Message: "Comment is too long"}
+)
+
+// Usernames are 3-20 latin letters or digits; a mention must not be glued to a
+// preceding word, so e-mail addresses are not picked up
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@.])@([A-Za-z0-9]{3,20})\b`)
+
+// Upper bound on mentions notified per comment
+const maxCommentMentions = 10
+
 type commentService struct {
-	commentRepo      repository.CommentRepository
-	notificationRepo repository.NotificationRepository
-	reactionRepo     repository.ReactionRepository
-	redis            *repository.RedisClient
-	logger           *zap.Logger
+	commentRepo  repository.CommentRepository
+	articleRepo  repository.ArticleRepository
+	reactionRepo repository.ReactionRepository
+	tx           repository.Transactor
+	automod      AutomodService
+	settings     SettingsService
+	permissions  PermissionService
+	moderation   ModerationService
+	publisher    *commentPublisher
+	redis        *repository.RedisClient
+	logger       *zap.Logger
 }
 
 func NewCommentService(
 	commentRepo repository.CommentRepository,
-	notificationRepo repository.NotificationRepository,
+	articleRepo repository.ArticleRepository,
+	userRepo repository.UserRepository,
 	reactionRepo repository.ReactionRepository,
+	tx repository.Transactor,
+	automod AutomodService,
+	settings SettingsService,
+	notifications NotificationService,
+	permissions PermissionService,
+	moderation ModerationService,
 	redis *repository.RedisClient,
+	hub Broadcaster,
 	logger *zap.Logger,
 ) CommentService {
 	return &commentService{
-		commentRepo:      commentRepo,
-		notificationRepo: notificationRepo,
-		reactionRepo:     reactionRepo,
-		redis:            redis,
-		logger:           logger,
+		commentRepo:  commentRepo,
+		articleRepo:  articleRepo,
+		reactionRepo: reactionRepo,
+		tx:           tx,
+		automod:      automod,
+		settings:     settings,
+		permissions:  permissions,
+		moderation:   moderation,
+		publisher: &commentPublisher{
+			commentRepo:   commentRepo,
+			articleRepo:   articleRepo,
+			userRepo:      userRepo,
+			notifications: notifications,
+			hub:           hub,
+			logger:        logger,
+		},
+		redis:  redis,
+		logger: logger,
 	}
 }
 
 func (s *commentService) Create(ctx context.Context, userID uuid.UUID, input CreateCommentInput) (*model.Comment, error) {
+	if utf8.RuneCountInString(input.Content) > s.settings.Current().MaxCommentLength {
+		return nil, ErrCommentTooLong
+	}
+
 	// Convert content to HTML (basic)
 	htmlContent := convertCommentToHTML(input.Content)
 
@@ -83,7 +162,23 @@ func (s *commentService) Create(ctx context.Context, userID uuid.UUID, input Cre
 		ParentID:    input.ParentID,
 	}
 
-	if err := s.commentRepo.Create(ctx, comment); err != nil {
+	check := AutomodInput{Target: model.ReportTargetComment, AuthorID: userID, Text: input.Content}
+	verdict := s.automod.Check(ctx, check)
+	if verdict.Action == model.AutomodReject {
+		if err := s.automod.Apply(ctx, verdict, check, uuid.Nil); err != nil {
+			s.logger.Warn("Failed to record automod rejection", zap.Error(err))
+		}
+		return nil, ErrContentRejected
+	}
+	comment.AutomodState = verdict.State()
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.commentRepo.Create(ctx, comment); err != nil {
+			return err
+		}
+		return s.automod.Apply(ctx, verdict, check, comment.ID)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -93,16 +188,135 @@ func (s *commentService) Create(ctx context.Context, userID uuid.UUID, input Cre
 		return nil, err
 	}
 
-	// TODO: Send notification to article author or parent comment author
+	// Held and shadow-hidden comments reach nobody but their author
+	if fullComment.AutomodState != nil {
+		return fullComment, nil
+	}
+
+	s.publisher.publish(ctx, fullComment)
 
 	return fullComment, nil
 }
 
-func (s *commentService) GetByID(ctx context.Context, id uuid.UUID) (*model.Comment, error) {
+// commentPublisher announces a comment once it becomes visible: on creation,
+// or when a moderator releases it from automoderation
+type commentPublisher struct {
+	commentRepo   repository.CommentRepository
+	articleRepo   repository.ArticleRepository
+	userRepo      repository.UserRepository
+	notifications NotificationService
+	hub           Broadcaster
+	logger        *zap.Logger
+}
+
+// publish pushes the comment to readers of the article and notifies its
+// recipients
+func (p *commentPublisher) publish(ctx context.Context, comment *model.Comment) {
+	if p.hub != nil {
+		p.hub.BroadcastNewComment(comment.ArticleID, comment)
+	}
+
+	p.notifyRecipients(ctx, comment)
+}
+
+// notifyRecipients tells the parent comment author, the article author and
+// mentioned users about a new comment. Everyone gets at most one notification,
+// in that order of precedence, and the commenter never notifies themselves.
+// Failures are logged; the comment itself is already saved.
+func (p *commentPublisher) notifyRecipients(ctx context.Context, comment *model.Comment) {
+	article, err := p.articleRepo.GetByID(ctx, comment.ArticleID)
+	if err != nil {
+		p.logger.Warn("Failed to load article for comment notifications", zap.String("comment_id", comment.ID.String()), zap.Error(err))
+		return
+	}
+
+	notified := map[uuid.UUID]bool{comment.AuthorID: true}
+	notify := func(recipientID uuid.UUID, send func() error) {
+		if notified[recipientID] {
+			return
+		}
+		notified[recipientID] = true
+
+		if err := send(); err != nil {
+			p.logger.Warn("Failed to send comment notification",
+				zap.String("comment_id", comment.ID.String()),
+				zap.String("user_id", recipientID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if comment.ParentID != nil {
+		if parent, err := p.commentRepo.GetByID(ctx, *comment.ParentID); err == nil {
+			notify(parent.AuthorID, func() error {
+				return p.notifications.NotifyCommentReply(ctx, parent.AuthorID, comment.AuthorID, article.ID, comment.ID)
+			})
+		}
+	}
+
+	notify(article.AuthorID, func() error {
+		return p.notifications.NotifyNewComment(ctx, article.AuthorID, comment.AuthorID, article.ID, comment.ID, article.Title)
+	})
+
+	usernames := parseMentions(comment.Content)
+	if len(usernames) == 0 {
+		return
+	}
+
+	ids, err := p.userRepo.GetIDsByUsernames(ctx, usernames)
+	if err != nil {
+		p.logger.Warn("Failed to resolve mentions", zap.String("comment_id", comment.ID.String()), zap.Error(err))
+		return
+	}
+
+	for _, username := range usernames {
+		mentionedID, ok := ids[username]
+		if !ok {
+			continue
+		}
+		notify(mentionedID, func() error {
+			return p.notifications.NotifyMention(ctx, mentionedID, comment.AuthorID, article.ID, comment.ID, article.Title)
+		})
+	}
+}
+
+// parseMentions returns the distinct @usernames in a comment in order of appearance
+func parseMentions(content string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		username := match[1]
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+
+		usernames = append(usernames, username)
+		if len(usernames) == maxCommentMentions {
+			break
+		}
+	}
+
+	return usernames
+}
+
+// GetByID returns a comment. Held and shadow-hidden comments exist only for
+// their author and for moderators who may delete them.
+func (s *commentService) GetByID(ctx context.Context, id uuid.UUID, viewerID *uuid.UUID) (*model.Comment, error) {
 	comment, err := s.commentRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if comment.AutomodState != nil {
+		visible, err := s.canSeeHidden(ctx, comment, viewerID)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			return nil, repository.ErrCommentNotFound
+		}
+	}
 
 	// Get reactions
 	reactions, _ := s.commentRepo.GetReactions(ctx, id, nil)
@@ -111,6 +325,21 @@ func (s *commentService) GetByID(ctx context.Context, id uuid.UUID) (*model.Comm
 	return comment, nil
 }
 
+// canSeeHidden reports whether the viewer may see a comment automod hid
+func (s *commentService) canSeeHidden(ctx context.Context, comment *model.Comment, viewerID *uuid.UUID) (bool, error) {
+	if viewerID == nil {
+		return false, nil
+	}
+	if *viewerID == comment.AuthorID {
+		return true, nil
+	}
+	article, err := s.articleRepo.GetByID(ctx, comment.ArticleID)
+	if err != nil {
+		return false, err
+	}
+	return s.permissions.Authorize(ctx, *viewerID, model.PermCommentDeleteAny, &article.CategoryID)
+}
+
 func (s *commentService) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, content string) (*model.Comment, error) {
 	comment, err := s.commentRepo.GetByID(ctx, id)
 	if err != nil {
@@ -121,6 +350,9 @@ func (s *commentService) Update(ctx context.Context, userID uuid.UUID, id uuid.U
 	if comment.AuthorID != userID {
 		return nil, ErrForbidden
 	}
+	if utf8.RuneCountInString(content) > s.settings.Current().MaxCommentLength {
+		return nil, ErrCommentTooLong
+	}
 
 	comment.Content = content
 	comment.HTMLContent = convertCommentToHTML(content)
@@ -139,9 +371,20 @@ func (s *commentService) Delete(ctx context.Context, userID uuid.UUID, id uuid.U
 		return err
 	}
 
-	// Check ownership (or moderation rights in the future)
+	// Moderators delete through moderation so it reaches the audit log
 	if comment.AuthorID != userID {
-		return ErrForbidden
+		article, err := s.articleRepo.GetByID(ctx, comment.ArticleID)
+		if err != nil {
+			return err
+		}
+		allowed, err := s.permissions.Authorize(ctx, userID, model.PermCommentDeleteAny, &article.CategoryID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ErrForbidden
+		}
+		return s.moderation.DeleteComment(ctx, userID, id, "")
 	}
 
 	return s.commentRepo.Delete(ctx, id)
@@ -162,9 +405,10 @@ func (s *commentService) GetByArticle(ctx context.Context, articleID uuid.UUID,
 	offset := (params.Page - 1) * params.PageSize
 
 	comments, total, err := s.commentRepo.GetByArticle(ctx, articleID, repository.CommentListParams{
-		Sort:   params.Sort,
-		Limit:  params.PageSize,
-		Offset: offset,
+		Sort:     params.Sort,
+		ViewerID: params.ViewerID,
+		Limit:    params.PageSize,
+		Offset:   offset,
 	})
 	if err != nil {
 		return nil, err
@@ -173,7 +417,7 @@ func (s *commentService) GetByArticle(ctx context.Context, articleID uuid.UUID,
 	// Load first 3 replies for each comment and reactions
 	for i := range comments {
 		if comments[i].ReplyCount > 0 {
-			replies, _ := s.commentRepo.GetReplies(ctx, comments[i].ID, 3, 0)
+			replies, _ := s.commentRepo.GetReplies(ctx, comments[i].ID, params.ViewerID, 3, 0)
 			comments[i].Replies = replies
 		}
 		reactions, _ := s.commentRepo.GetReactions(ctx, comments[i].ID, nil)
@@ -189,8 +433,8 @@ func (s *commentService) GetByArticle(ctx context.Context, articleID uuid.UUID,
 	}, nil
 }
 
-func (s *commentService) GetReplies(ctx context.Context, parentID uuid.UUID, limit, offset int) ([]model.Comment, error) {
-	replies, err := s.commentRepo.GetReplies(ctx, parentID, limit, offset)
+func (s *commentService) GetReplies(ctx context.Context, parentID uuid.UUID, viewerID *uuid.UUID, limit, offset int) ([]model.Comment, error) {
+	replies, err := s.commentRepo.GetReplies(ctx, parentID, viewerID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -239,6 +483,170 @@ func (s *commentService) RemoveReaction(ctx context.Context, userID, commentID u
 	return s.commentRepo.RemoveReaction(ctx, commentID, userID)
 }
 
+func (s *commentService) GetTree(ctx context.Context, articleID uuid.UUID, viewerID *uuid.UUID, params CommentTreeParams) (*CommentTreeResult, error) {
+	if params.Sort == "" {
+		params.Sort = "new"
+	}
+
+	total, err := s.commentRepo.CountRoots(ctx, articleID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.loadTree(ctx, repository.CommentThreadParams{
+		ArticleID: articleID,
+		Sort:      params.Sort,
+		ViewerID:  viewerID,
+	}, 0, viewerID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Total = total
+	return result, nil
+}
+
+// GetThread continues a branch: the replies of parentID after the cursor, with their subtrees
+func (s *commentService) GetThread(ctx context.Context, parentID uuid.UUID, viewerID *uuid.UUID, params CommentTreeParams) (*CommentTreeResult, error) {
+	parent, err := s.commentRepo.GetByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	depth, err := s.commentRepo.GetDepth(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.loadTree(ctx, repository.CommentThreadParams{
+		ArticleID: parent.ArticleID,
+		ParentID:  &parentID,
+		ViewerID:  viewerID,
+	}, depth+1, viewerID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Total = parent.ReplyCount
+	return result, nil
+}
+
+// loadTree loads one page of a level plus up to params.Depth levels of replies
+// below it, using one query per level page and one recursive query for the rest
+func (s *commentService) loadTree(ctx context.Context, page repository.CommentThreadParams, baseDepth int, viewerID *uuid.UUID, params CommentTreeParams) (*CommentTreeResult, error) {
+	if params.Limit < 1 || params.Limit > commentTreeMaxLimit {
+		params.Limit = 20
+	}
+	if params.Depth < 0 || params.Depth > commentTreeMaxDepth {
+		params.Depth = 3
+	}
+	if params.Replies < 1 || params.Replies > commentTreeMaxReplies {
+		params.Replies = 3
+	}
+
+	if params.Cursor != "" {
+		cursor, err := decodeCommentCursor(params.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		page.After = cursor
+	}
+
+	// One extra row tells whether another page exists
+	page.Limit = params.Limit + 1
+	items, err := s.commentRepo.GetThreadPage(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CommentTreeResult{Items: items}
+	if len(items) > params.Limit {
+		result.Items = items[:params.Limit]
+		result.HasMore = true
+		result.NextCursor = encodeCommentCursor(result.Items[len(result.Items)-1])
+	}
+	if result.Items == nil {
+		result.Items = []model.Comment{}
+	}
+
+	ids := make([]uuid.UUID, len(result.Items))
+	for i := range result.Items {
+		ids[i] = result.Items[i].ID
+	}
+
+	descendants, err := s.commentRepo.GetDescendants(ctx, ids, viewerID, params.Depth, params.Replies, commentTreeMaxNodes)
+	if err != nil {
+		return nil, err
+	}
+
+	allIDs := ids
+	for _, c := range descendants {
+		allIDs = append(allIDs, c.ID)
+	}
+	reactions, err := s.commentRepo.GetCommentsReactions(ctx, allIDs, viewerID)
+	if err != nil {
+		s.logger.Warn("Failed to load comment reactions", zap.Error(err))
+	}
+
+	// Descendants arrive ordered by depth and age, so appending keeps sibling order
+	children := make(map[uuid.UUID][]model.Comment)
+	for _, c := range descendants {
+		c.Depth += baseDepth
+		c.Reactions = reactions[c.ID]
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	for i := range result.Items {
+		result.Items[i].Depth = baseDepth
+		result.Items[i].Reactions = reactions[result.Items[i].ID]
+		attachReplies(&result.Items[i], children)
+	}
+
+	return result, nil
+}
+
+// attachReplies links loaded replies into the tree and marks branches with more to load
+func attachReplies(comment *model.Comment, children map[uuid.UUID][]model.Comment) {
+	replies := children[comment.ID]
+	for i := range replies {
+		attachReplies(&replies[i], children)
+	}
+	comment.Replies = replies
+
+	if comment.ReplyCount > len(replies) {
+		comment.HasMoreReplies = true
+		if len(replies) > 0 {
+			comment.RepliesCursor = encodeCommentCursor(replies[len(replies)-1])
+		}
+	}
+}
+
+func encodeCommentCursor(c model.Comment) string {
+	data, _ := json.Marshal(repository.CommentCursor{
+		CreatedAt:  c.CreatedAt,
+		ReplyCount: c.ReplyCount,
+		ID:         c.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCommentCursor(s string) (*repository.CommentCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor repository.CommentCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.ID == uuid.Nil {
+		return nil, errors.New("cursor without id")
+	}
+
+	return &cursor, nil
+}
+
 // convertCommentToHTML converts markdown to simple HTML
 func convertCommentToHTML(content string) string {
 	// Basic conversion - in production use a proper markdown parser
@@ -258,4 +666,3 @@ func convertCommentToHTML(content string) string {
 
 	return html
 }
-
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+	"github.com/neurogen-news/backend/internal/storage"
+)
+
+// MediaService is the user-facing media library
+type MediaService interface {
+	List(ctx context.Context, userID uuid.UUID, params MediaListParams) (*MediaListResult, error)
+	Delete(ctx context.Context, userID, mediaID uuid.UUID) error
+}
+
+type MediaListParams struct {
+	Query    string
+	Type     string
+	Page     int
+	PageSize int
+}
+
+type MediaListResult struct {
+	Items    []model.Media `json:"items"`
+	Total    int           `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"pageSize"`
+	HasMore  bool          `json:"hasMore"`
+}
+
+var (
+	ErrMediaNotFound = &AppError{Code: "MEDIA_NOT_FOUND", Message: "Media not found"}
+	ErrMediaInUse    = &AppError{Code: "MEDIA_IN_USE", Message: "Media is used in articles, their history, drafts or a profile"}
+)
+
+const (
+	// mediaGracePeriod protects new uploads that are not saved into content yet
+	mediaGracePeriod      = 24 * time.Hour
+	mediaCollectInterval  = time.Hour
+	mediaCollectBatchSize = 100
+)
+
+type mediaService struct {
+	mediaRepo repository.MediaRepository
+	storage   storage.Storage
+	logger    *zap.Logger
+}
+
+func NewMediaService(mediaRepo repository.MediaRepository, store storage.Storage, logger *zap.Logger) MediaService {
+	return &mediaService{
+		mediaRepo: mediaRepo,
+		storage:   store,
+		logger:    logger,
+	}
+}
+
+// List pages through the user's media, newest first, optionally filtered by
+// a name search and upload type
+func (s *mediaService) List(ctx context.Context, userID uuid.UUID, params MediaListParams) (*MediaListResult, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 || params.PageSize > 100 {
+		params.PageSize = 20
+	}
+	offset := (params.Page - 1) * params.PageSize
+
+	items, total, err := s.mediaRepo.List(ctx, userID, params.Query, params.Type, params.PageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) > 0 {
+		ids := make([]uuid.UUID, len(items))
+		byID := make(map[uuid.UUID]*model.Media, len(items))
+		for i := range items {
+			ids[i] = items[i].ID
+			byID[items[i].ID] = &items[i]
+			items[i].URL = s.storage.URL(items[i].Key)
+			items[i].References = []model.MediaReference{}
+		}
+
+		refs, err := s.mediaRepo.ListReferences(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			byID[ref.MediaID].References = append(byID[ref.MediaID].References, ref)
+		}
+	}
+
+	return &MediaListResult{
+		Items:    items,
+		Total:    total,
+		Page:     params.Page,
+		PageSize: params.PageSize,
+		HasMore:  offset+len(items) < total,
+	}, nil
+}
+
+// Delete removes an unused media item of the user
+func (s *mediaService) Delete(ctx context.Context, userID, mediaID uuid.UUID) error {
+	media, err := s.mediaRepo.GetByID(ctx, mediaID)
+	if err != nil {
+		if err == repository.ErrMediaNotFound {
+			return ErrMediaNotFound
+		}
+		return err
+	}
+	if media.UserID != userID {
+		return ErrMediaNotFound
+	}
+
+	removed, err := removeUnusedMedia(ctx, s.mediaRepo, s.storage, media, s.logger)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrMediaInUse
+	}
+	return nil
+}
+
+// removeMedia deletes the stored files of a media item, then its record
+func removeMedia(ctx context.Context, mediaRepo repository.MediaRepository, store storage.Storage, media *model.Media, logger *zap.Logger) error {
+	if err := store.DeleteFiles(ctx, derivedKeys(media.Key)); err != nil {
+		return err
+	}
+	if err := mediaRepo.Delete(ctx, media.ID); err != nil {
+		return err
+	}
+
+	logger.Info("Media deleted", zap.String("user_id", media.UserID.String()), zap.String("key", media.Key))
+	return nil
+}
+
+// removeUnusedMedia deletes the record of a media item only if nothing uses
+// it, then its stored files; it reports whether the media was removed
+func removeUnusedMedia(ctx context.Context, mediaRepo repository.MediaRepository, store storage.Storage, media *model.Media, logger *zap.Logger) (bool, error) {
+	removed, err := mediaRepo.DeleteUnreferenced(ctx, media.ID)
+	if err != nil || !removed {
+		return false, err
+	}
+	if err := store.DeleteFiles(ctx, derivedKeys(media.Key)); err != nil {
+		logger.Warn("Failed to delete media files", zap.String("key", media.Key), zap.Error(err))
+	}
+
+	logger.Info("Media deleted", zap.String("user_id", media.UserID.String()), zap.String("key", media.Key))
+	return true, nil
+}
+
+// MediaCollector deletes media nothing has used since the grace period
+type MediaCollector struct {
+	mediaRepo repository.MediaRepository
+	storage   storage.Storage
+	logger    *zap.Logger
+}
+
+func NewMediaCollector(mediaRepo repository.MediaRepository, store storage.Storage, logger *zap.Logger) *MediaCollector {
+	return &MediaCollector{
+		mediaRepo: mediaRepo,
+		storage:   store,
+		logger:    logger,
+	}
+}
+
+// Run collects unused media until ctx is cancelled
+func (c *MediaCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(mediaCollectInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := c.Collect(ctx); err != nil {
+				c.logger.Warn("Failed to collect unused media", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Collect runs one pass, deleting unreferenced media past the grace period
+// batch by batch
+func (c *MediaCollector) Collect(ctx context.Context) error {
+	for {
+		unused, err := c.mediaRepo.ListUnreferenced(ctx, time.Now().Add(-mediaGracePeriod), mediaCollectBatchSize)
+		if err != nil {
+			return err
+		}
+
+		removed := 0
+		for i := range unused {
+			media := &unused[i]
+			// Content may have started using it since the listing
+			ok, err := removeUnusedMedia(ctx, c.mediaRepo, c.storage, media, c.logger)
+			if err != nil {
+				c.logger.Warn("Failed to delete unused media", zap.String("key", media.Key), zap.Error(err))
+				continue
+			}
+			if ok {
+				removed++
+			}
+		}
+
+		if len(unused) < mediaCollectBatchSize || removed == 0 {
+			return nil
+		}
+	}
+}
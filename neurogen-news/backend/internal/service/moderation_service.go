@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+)
+
+// ModerationService performs moderator actions on content and keeps the audit
+// log. Every action writes a moderation_actions row with before/after snapshots
+// of the entity; called inside another transaction the methods join it.
+type ModerationService interface {
+	DeleteArticle(ctx context.Context, moderatorID, id uuid.UUID, reason string) error
+	HideArticle(ctx context.Context, moderatorID, id uuid.UUID, reason string) error
+	DeleteComment(ctx context.Context, moderatorID, id uuid.UUID, reason string) error
+	HideComment(ctx context.Context, moderatorID, id uuid.UUID, reason string) error
+	Warn(ctx context.Context, moderatorID, userID uuid.UUID, targetType string, targetID uuid.UUID, reason string) error
+	// Release makes content held or shadow-hidden by automoderation visible
+	Release(ctx context.Context, moderatorID uuid.UUID, targetType string, targetID uuid.UUID) error
+
+	// Record writes an audit entry for actions performed elsewhere
+	Record(ctx context.Context, action *model.ModerationAction, before, after interface{}) error
+
+	// Audit log
+	GetAuditLog(ctx context.Context, params AuditLogParams) (*AuditLogResult, error)
+	ExportAuditLog(ctx context.Context, params AuditLogParams, w io.Writer) (int, error)
+}
+
+type AuditLogParams struct {
+	ModeratorID *uuid.UUID
+	TargetType  string
+	TargetID    *uuid.UUID
+	Action      string
+	From        *time.Time
+	To          *time.Time
+	Page        int
+	PageSize    int
+}
+
+type AuditLogResult struct {
+	Items    []model.ModerationAction `json:"items"`
+	Total    int                      `json:"total"`
+	Page     int                      `json:"page"`
+	PageSize int                      `json:"pageSize"`
+	HasMore  bool                     `json:"hasMore"`
+}
+
+const (
+	auditExportBatch   = 500
+	auditExportMaxRows = 50000
+)
+
+type moderationService struct {
+	moderationRepo repository.ModerationRepository
+	articleRepo    repository.ArticleRepository
+	commentRepo    repository.CommentRepository
+	outboxRepo     repository.OutboxRepository
+	tx             repository.Transactor
+	notifications  NotificationService
+	publisher      *commentPublisher
+	redis          *repository.RedisClient
+	logger         *zap.Logger
+}
+
+func NewModerationService(
+	moderationRepo repository.ModerationRepository,
+	articleRepo repository.ArticleRepository,
+	commentRepo repository.CommentRepository,
+	outboxRepo repository.OutboxRepository,
+	userRepo repository.UserRepository,
+	tx repository.Transactor,
+	notifications NotificationService,
+	redis *repository.RedisClient,
+	hub Broadcaster,
+	logger *zap.Logger,
+) ModerationService {
+	return &moderationService{
+		moderationRepo: moderationRepo,
+		articleRepo:    articleRepo,
+		commentRepo:    commentRepo,
+		outboxRepo:     outboxRepo,
+		tx:             tx,
+		notifications:  notifications,
+		publisher: &commentPublisher{
+			commentRepo:   commentRepo,
+			articleRepo:   articleRepo,
+			userRepo:      userRepo,
+			notifications: notifications,
+			hub:           hub,
+			logger:        logger,
+		},
+		redis:  redis,
+		logger: logger,
+	}
+}
+
+func (s *moderationService) DeleteArticle(ctx context.Context, moderatorID, id uuid.UUID, reason string) error {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	tags, _ := s.articleRepo.GetTags(ctx, id)
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.articleRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if err := s.outboxRepo.Enqueue(ctx, model.OutboxArticleDeleted, id); err != nil {
+			return err
+		}
+		for _, t := range tags {
+			if err := s.outboxRepo.Enqueue(ctx, model.OutboxTagUpserted, t.ID); err != nil {
+				return err
+			}
+		}
+		return s.Record(ctx, &model.ModerationAction{
+			ModeratorID: moderatorID,
+			TargetType:  model.ReportTargetArticle,
+			TargetID:    id,
+			Action:      model.ModerationDelete,
+			Reason:      optionalString(reason),
+		}, article, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateArticleCache(ctx)
+	return nil
+}
+
+func (s *moderationService) HideArticle(ctx context.Context, moderatorID, id uuid.UUID, reason string) error {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.articleRepo.UpdateStatus(ctx, id, model.StatusArchived); err != nil {
+			return err
+		}
+		// The dispatcher drops unpublished articles from the search index
+		if err := s.outboxRepo.Enqueue(ctx, model.OutboxArticleUpserted, id); err != nil {
+			return err
+		}
+
+		after, err := s.articleRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.Record(ctx, &model.ModerationAction{
+			ModeratorID: moderatorID,
+			TargetType:  model.ReportTargetArticle,
+			TargetID:    id,
+			Action:      model.ModerationHide,
+			Reason:      optionalString(reason),
+		}, article, after)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateArticleCache(ctx)
+	return nil
+}
+
+func (s *moderationService) DeleteComment(ctx context.Context, moderatorID, id uuid.UUID, reason string) error {
+	return s.changeComment(ctx, moderatorID, id, reason, model.ModerationDelete, s.commentRepo.Delete)
+}
+
+func (s *moderationService) HideComment(ctx context.Context, moderatorID, id uuid.UUID, reason string) error {
+	return s.changeComment(ctx, moderatorID, id, reason, model.ModerationHide, s.commentRepo.Hide)
+}
+
+func (s *moderationService) changeComment(ctx context.Context, moderatorID, id uuid.UUID, reason string, actionType model.ModerationActionType, change func(context.Context, uuid.UUID) error) error {
+	comment, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := change(ctx, id); err != nil {
+			return err
+		}
+
+		after, err := s.commentRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.Record(ctx, &model.ModerationAction{
+			ModeratorID: moderatorID,
+			TargetType:  model.ReportTargetComment,
+			TargetID:    id,
+			Action:      actionType,
+			Reason:      optionalString(reason),
+		}, comment, after)
+	})
+}
+
+// Warn records a warning about the user's content and notifies them once the
+// surrounding transaction commits
+func (s *moderationService) Warn(ctx context.Context, moderatorID, userID uuid.UUID, targetType string, targetID uuid.UUID, reason string) error {
+	reason = strings.TrimSpace(reason)
+
+	err := s.Record(ctx, &model.ModerationAction{
+		ModeratorID: moderatorID,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Action:      model.ModerationWarn,
+		Reason:      optionalString(reason),
+	}, nil, map[string]interface{}{"userId": userID})
+	if err != nil {
+		return err
+	}
+
+	repository.AfterCommit(ctx, func(ctx context.Context) {
+		if err := s.notifications.NotifyWarning(ctx, userID, reason); err != nil {
+			s.logger.Warn("Failed to notify warned user", zap.String("user_id", userID.String()), zap.Error(err))
+		}
+	})
+	return nil
+}
+
+func (s *moderationService) Release(ctx context.Context, moderatorID uuid.UUID, targetType string, targetID uuid.UUID) error {
+	var released bool
+	var before, after interface{}
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		switch targetType {
+		case model.ReportTargetArticle:
+			if before, err = s.articleRepo.GetByID(ctx, targetID); err != nil {
+				// Nothing to release once the content is gone
+				if errors.Is(err, repository.ErrArticleNotFound) {
+					return nil
+				}
+				return err
+			}
+			if released, err = s.articleRepo.ClearAutomodState(ctx, targetID); err != nil || !released {
+				return err
+			}
+			if err := s.outboxRepo.Enqueue(ctx, model.OutboxArticleUpserted, targetID); err != nil {
+				return err
+			}
+			after, err = s.articleRepo.GetByID(ctx, targetID)
+		case model.ReportTargetComment:
+			if before, err = s.commentRepo.GetByID(ctx, targetID); err != nil {
+				if errors.Is(err, repository.ErrCommentNotFound) {
+					return nil
+				}
+				return err
+			}
+			if released, err = s.commentRepo.ClearAutomodState(ctx, targetID); err != nil || !released {
+				return err
+			}
+			var comment *model.Comment
+			if comment, err = s.commentRepo.GetByID(ctx, targetID); err != nil {
+				return err
+			}
+			after = comment
+
+			// Readers and recipients hear about the comment only now
+			repository.AfterCommit(ctx, func(ctx context.Context) {
+				s.publisher.publish(ctx, comment)
+			})
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.Record(ctx, &model.ModerationAction{
+			ModeratorID: moderatorID,
+			TargetType:  targetType,
+			TargetID:    targetID,
+			Action:      model.ModerationRelease,
+		}, before, after)
+	})
+	if err != nil {
+		return err
+	}
+
+	if released && targetType == model.ReportTargetArticle {
+		s.invalidateArticleCache(ctx)
+	}
+	return nil
+}
+
+func (s *moderationService) Record(ctx context.Context, action *model.ModerationAction, before, after interface{}) error {
+	action.Before = snapshot(before)
+	action.After = snapshot(after)
+	return s.moderationRepo.CreateAction(ctx, action)
+}
+
+func (s *moderationService) GetAuditLog(ctx context.Context, params AuditLogParams) (*AuditLogResult, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 || params.PageSize > 100 {
+		params.PageSize = 50
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+
+	actions, total, err := s.moderationRepo.ListActions(ctx, auditLogQuery(params, params.PageSize, offset))
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditLogResult{
+		Items:    actions,
+		Total:    total,
+		Page:     params.Page,
+		PageSize: params.PageSize,
+		HasMore:  offset+len(actions) < total,
+	}, nil
+}
+
+// ExportAuditLog writes matching entries as CSV, newest first, and returns the
+// number of rows written. Exports are capped at auditExportMaxRows; entries
+// recorded while the export runs are left out.
+func (s *moderationService) ExportAuditLog(ctx context.Context, params AuditLogParams, w io.Writer) (int, error) {
+	cw := csv.NewWriter(w)
+	header := []string{
+		"id", "created_at", "moderator_id", "moderator_username", "action",
+		"target_type", "target_id", "reason", "before", "after",
+	}
+	if err := cw.Write(header); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	var cursor *repository.AuditLogCursor
+	for written < auditExportMaxRows {
+		actions, err := s.moderationRepo.ListActionsBefore(ctx, auditLogQuery(params, auditExportBatch, 0), cursor)
+		if err != nil {
+			return written, err
+		}
+
+		for _, a := range actions {
+			moderator := ""
+			if a.Moderator != nil {
+				moderator = a.Moderator.Username
+			}
+			reason := ""
+			if a.Reason != nil {
+				reason = *a.Reason
+			}
+
+			err := cw.Write([]string{
+				a.ID.String(),
+				a.CreatedAt.UTC().Format(time.RFC3339),
+				a.ModeratorID.String(),
+				moderator,
+				string(a.Action),
+				a.TargetType,
+				a.TargetID.String(),
+				reason,
+				string(a.Before),
+				string(a.After),
+			})
+			if err != nil {
+				return written, err
+			}
+		}
+
+		written += len(actions)
+		if len(actions) < auditExportBatch {
+			break
+		}
+		last := actions[len(actions)-1]
+		cursor = &repository.AuditLogCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	cw.Flush()
+	return written, cw.Error()
+}
+
+func (s *moderationService) invalidateArticleCache(ctx context.Context) {
+	s.redis.Del(ctx, "articles:popular", "articles:new", "articles:hot")
+}
+
+func auditLogQuery(params AuditLogParams, limit, offset int) repository.AuditLogParams {
+	return repository.AuditLogParams{
+		ModeratorID: params.ModeratorID,
+		TargetType:  params.TargetType,
+		TargetID:    params.TargetID,
+		Action:      params.Action,
+		From:        params.From,
+		To:          params.To,
+		Limit:       limit,
+		Offset:      offset,
+	}
+}
+
+// snapshot serializes an entity for the audit log; nil stays empty
+func snapshot(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return nil
+	}
+	return data
+}
+
+func optionalString(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -22,8 +23,11 @@ type NotificationService interface {
 	// Notification creation helpers
 	NotifyNewComment(ctx context.Context, articleAuthorID, commentAuthorID, articleID, commentID uuid.UUID, articleTitle string) error
 	NotifyCommentReply(ctx context.Context, parentAuthorID, replyAuthorID, articleID, commentID uuid.UUID) error
+	NotifyMention(ctx context.Context, mentionedID, authorID, articleID, commentID uuid.UUID, articleTitle string) error
 	NotifyNewFollower(ctx context.Context, userID, followerID uuid.UUID) error
 	NotifyReaction(ctx context.Context, authorID, reactorID, articleID uuid.UUID, emoji string) error
+	NotifyWarning(ctx context.Context, userID uuid.UUID, reason string) error
+	NotifyArticleReview(ctx context.Context, authorID, reviewerID, articleID uuid.UUID, articleTitle, decision, note string) error
 }
 
 type NotificationListResult struct {
@@ -37,6 +41,7 @@ type notificationService struct {
 	notificationRepo repository.NotificationRepository
 	userRepo         repository.UserRepository
 	redis            *repository.RedisClient
+	hub              Broadcaster
 	logger           *zap.Logger
 }
 
@@ -44,12 +49,14 @@ func NewNotificationService(
 	notificationRepo repository.NotificationRepository,
 	userRepo repository.UserRepository,
 	redis *repository.RedisClient,
+	hub Broadcaster,
 	logger *zap.Logger,
 ) NotificationService {
 	return &notificationService{
 		notificationRepo: notificationRepo,
 		userRepo:         userRepo,
 		redis:            redis,
+		hub:              hub,
 		logger:           logger,
 	}
 }
@@ -119,7 +126,7 @@ func (s *notificationService) NotifyNewComment(ctx context.Context, articleAutho
 	link := "/article/" + articleID.String() + "#comment-" + commentID.String()
 	notification.Link = &link
 
-	return s.notificationRepo.Create(ctx, notification)
+	return s.send(ctx, notification)
 }
 
 func (s *notificationService) NotifyCommentReply(ctx context.Context, parentAuthorID, replyAuthorID, articleID, commentID uuid.UUID) error {
@@ -141,7 +148,29 @@ func (s *notificationService) NotifyCommentReply(ctx context.Context, parentAuth
 	link := "/article/" + articleID.String() + "#comment-" + commentID.String()
 	notification.Link = &link
 
-	return s.notificationRepo.Create(ctx, notification)
+	return s.send(ctx, notification)
+}
+
+func (s *notificationService) NotifyMention(ctx context.Context, mentionedID, authorID, articleID, commentID uuid.UUID, articleTitle string) error {
+	// Don't notify if user mentions themselves
+	if mentionedID == authorID {
+		return nil
+	}
+
+	notification := &model.Notification{
+		UserID:    mentionedID,
+		Type:      model.NotificationMention,
+		Title:     "Вас упомянули",
+		Message:   "Вас упомянули в комментарии к статье \"" + articleTitle + "\"",
+		ActorID:   &authorID,
+		ArticleID: &articleID,
+		CommentID: &commentID,
+	}
+
+	link := "/article/" + articleID.String() + "#comment-" + commentID.String()
+	notification.Link = &link
+
+	return s.send(ctx, notification)
 }
 
 func (s *notificationService) NotifyNewFollower(ctx context.Context, userID, followerID uuid.UUID) error {
@@ -156,7 +185,7 @@ func (s *notificationService) NotifyNewFollower(ctx context.Context, userID, fol
 	link := "/user/" + followerID.String()
 	notification.Link = &link
 
-	return s.notificationRepo.Create(ctx, notification)
+	return s.send(ctx, notification)
 }
 
 func (s *notificationService) NotifyReaction(ctx context.Context, authorID, reactorID, articleID uuid.UUID, emoji string) error {
@@ -177,6 +206,81 @@ func (s *notificationService) NotifyReaction(ctx context.Context, authorID, reac
 	link := "/article/" + articleID.String()
 	notification.Link = &link
 
-	return s.notificationRepo.Create(ctx, notification)
+	return s.send(ctx, notification)
+}
+
+func (s *notificationService) NotifyWarning(ctx context.Context, userID uuid.UUID, reason string) error {
+	message := "Модератор вынес вам предупреждение"
+	if reason != "" {
+		message += ": " + reason
+	}
+
+	notification := &model.Notification{
+		UserID:  userID,
+		Type:    model.NotificationSystem,
+		Title:   "Предупреждение",
+		Message: message,
+	}
+
+	return s.send(ctx, notification)
 }
 
+// NotifyArticleReview tells the author how the review of their article ended
+func (s *notificationService) NotifyArticleReview(ctx context.Context, authorID, reviewerID, articleID uuid.UUID, articleTitle, decision, note string) error {
+	var title, message string
+	switch decision {
+	case model.ReviewApproved:
+		title = "Статья опубликована"
+		message = "Ваша статья \"" + articleTitle + "\" прошла модерацию и опубликована"
+	case model.ReviewRejected:
+		title = "Статья отклонена"
+		message = "Ваша статья \"" + articleTitle + "\" отклонена модератором"
+	case model.ReviewChangesRequested:
+		title = "Статья отправлена на доработку"
+		message = "Модератор просит доработать статью \"" + articleTitle + "\""
+	default:
+		return nil
+	}
+	if note != "" {
+		message += ": " + note
+	}
+
+	notification := &model.Notification{
+		UserID:    authorID,
+		Type:      model.NotificationArticleReview,
+		Title:     title,
+		Message:   message,
+		ActorID:   &reviewerID,
+		ArticleID: &articleID,
+	}
+
+	link := "/article/" + articleID.String()
+	notification.Link = &link
+
+	return s.send(ctx, notification)
+}
+
+// send stores a notification and pushes it to the recipient's open connections
+func (s *notificationService) send(ctx context.Context, notification *model.Notification) error {
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+	if s.hub == nil {
+		return nil
+	}
+
+	notification.CreatedAt = time.Now()
+	if notification.ActorID != nil {
+		if actor, err := s.userRepo.GetByID(ctx, *notification.ActorID); err == nil {
+			notification.Actor = &model.NotificationActor{
+				ID:          actor.ID,
+				Username:    actor.Username,
+				DisplayName: actor.DisplayName,
+				AvatarURL:   actor.AvatarURL,
+			}
+		}
+	}
+
+	s.hub.SendNotification(notification.UserID, notification)
+	return nil
+}
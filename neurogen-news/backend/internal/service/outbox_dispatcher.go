@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+	"github.com/neurogen-news/backend/internal/search"
+)
+
+const (
+	outboxPollInterval = 2 * time.Second
+	outboxBatchSize    = 50
+	outboxLease        = time.Minute
+	outboxMaxAttempts  = 8
+	outboxBaseBackoff  = 5 * time.Second
+	outboxMaxBackoff   = 30 * time.Minute
+	outboxRetention    = 7 * 24 * time.Hour
+)
+
+// OutboxDispatcher delivers outbox events to the search index.
+// Each event only carries the aggregate ID; the current state is reloaded from
+// Postgres, so replays and out-of-order delivery converge on the latest data.
+type OutboxDispatcher struct {
+	outboxRepo   repository.OutboxRepository
+	articleRepo  repository.ArticleRepository
+	userRepo     repository.UserRepository
+	categoryRepo repository.CategoryRepository
+	tagRepo      repository.TagRepository
+	searchClient *search.Client
+	logger       *zap.Logger
+}
+
+func NewOutboxDispatcher(
+	outboxRepo repository.OutboxRepository,
+	articleRepo repository.ArticleRepository,
+	userRepo repository.UserRepository,
+	categoryRepo repository.CategoryRepository,
+	tagRepo repository.TagRepository,
+	searchClient *search.Client,
+	logger *zap.Logger,
+) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outboxRepo:   outboxRepo,
+		articleRepo:  articleRepo,
+		userRepo:     userRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		searchClient: searchClient,
+		logger:       logger,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			// Drain full batches before waiting for the next tick
+			for d.dispatchBatch(ctx) == outboxBatchSize {
+				if ctx.Err() != nil {
+					return
+				}
+			}
+
+		case <-cleanup.C:
+			deleted, err := d.outboxRepo.DeleteProcessedBefore(ctx, time.Now().Add(-outboxRetention))
+			if err != nil {
+				d.logger.Warn("Failed to clean up outbox", zap.Error(err))
+			} else if deleted > 0 {
+				d.logger.Debug("Outbox cleaned up", zap.Int64("deleted", deleted))
+			}
+		}
+	}
+}
+
+// dispatchBatch processes one claimed batch and returns its size
+func (d *OutboxDispatcher) dispatchBatch(ctx context.Context) int {
+	events, err := d.outboxRepo.Claim(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		d.logger.Error("Failed to claim outbox events", zap.Error(err))
+		return 0
+	}
+
+	for _, event := range events {
+		if err := d.handle(ctx, event); err != nil {
+			d.fail(ctx, event, err)
+			continue
+		}
+		if err := d.outboxRepo.MarkProcessed(ctx, event.ID); err != nil {
+			d.logger.Error("Failed to mark outbox event processed", zap.String("id", event.ID.String()), zap.Error(err))
+		}
+	}
+
+	return len(events)
+}
+
+// fail schedules a retry with exponential backoff or dead-letters the event
+func (d *OutboxDispatcher) fail(ctx context.Context, event model.OutboxEvent, cause error) {
+	fields := []zap.Field{
+		zap.String("id", event.ID.String()),
+		zap.String("type", string(event.EventType)),
+		zap.String("aggregateId", event.AggregateID.String()),
+		zap.Int("attempts", event.Attempts),
+		zap.Error(cause),
+	}
+
+	if event.Attempts >= outboxMaxAttempts {
+		d.logger.Error("Outbox event dead-lettered", fields...)
+		if err := d.outboxRepo.MarkDead(ctx, event.ID, cause.Error()); err != nil {
+			d.logger.Error("Failed to dead-letter outbox event", zap.Error(err))
+		}
+		return
+	}
+
+	backoff := outboxBaseBackoff << (event.Attempts - 1)
+	if backoff <= 0 || backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+
+	d.logger.Warn("Outbox event failed, will retry", append(fields, zap.Duration("retryIn", backoff))...)
+	if err := d.outboxRepo.MarkFailed(ctx, event.ID, cause.Error(), time.Now().Add(backoff)); err != nil {
+		d.logger.Error("Failed to reschedule outbox event", zap.Error(err))
+	}
+}
+
+func (d *OutboxDispatcher) handle(ctx context.Context, event model.OutboxEvent) error {
+	// Search is optional; without it there is nothing to deliver
+	if d.searchClient == nil {
+		return nil
+	}
+
+	switch event.EventType {
+	case model.OutboxArticleUpserted:
+		return d.indexArticle(ctx, event)
+	case model.OutboxArticleDeleted:
+		return d.searchClient.DeleteArticle(ctx, event.AggregateID.String())
+	case model.OutboxUserUpserted:
+		return d.indexUser(ctx, event)
+	case model.OutboxTagUpserted:
+		return d.indexTag(ctx, event)
+	default:
+		return fmt.Errorf("unknown outbox event type %q", event.EventType)
+	}
+}
+
+func (d *OutboxDispatcher) indexArticle(ctx context.Context, event model.OutboxEvent) error {
+	article, err := d.articleRepo.GetByID(ctx, event.AggregateID)
+	if err != nil {
+		if errors.Is(err, repository.ErrArticleNotFound) {
+			return d.searchClient.DeleteArticle(ctx, event.AggregateID.String())
+		}
+		return err
+	}
+
+	// Only published articles are searchable; shadow-hidden ones stay out
+	if article.Status != model.StatusPublished || article.AutomodState != nil {
+		return d.searchClient.DeleteArticle(ctx, article.ID.String())
+	}
+
+	searchable, err := BuildSearchableArticle(ctx, article, d.userRepo, d.categoryRepo, d.articleRepo)
+	if err != nil {
+		return err
+	}
+
+	return d.searchClient.IndexArticle(ctx, searchable)
+}
+
+func (d *OutboxDispatcher) indexUser(ctx context.Context, event model.OutboxEvent) error {
+	user, err := d.userRepo.GetByID(ctx, event.AggregateID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return d.searchClient.DeleteUser(ctx, event.AggregateID.String())
+		}
+		return err
+	}
+
+	return d.searchClient.IndexUser(ctx, search.UserToSearchable(user))
+}
+
+func (d *OutboxDispatcher) indexTag(ctx context.Context, event model.OutboxEvent) error {
+	tag, err := d.tagRepo.GetByID(ctx, event.AggregateID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTagNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return d.searchClient.IndexTag(ctx, search.TagToSearchable(tag))
+}
+
+type OutboxEventListResult struct {
+	Items    []model.OutboxEvent `json:"items"`
+	Total    int                 `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+	HasMore  bool                `json:"hasMore"`
+}
+
+var ErrOutboxEventNotFound = &AppError{Code: "OUTBOX_EVENT_NOT_FOUND", Message: "Dead-lettered event not found"}
+
+// ListDead returns dead-lettered events, most recent first
+func (d *OutboxDispatcher) ListDead(ctx context.Context, page, pageSize int) (*OutboxEventListResult, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	offset := (page - 1) * pageSize
+
+	events, total, err := d.outboxRepo.ListDead(ctx, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []model.OutboxEvent{}
+	}
+
+	return &OutboxEventListResult{
+		Items:    events,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		HasMore:  offset+len(events) < total,
+	}, nil
+}
+
+// Requeue hands a dead-lettered event back to the dispatcher with fresh attempts
+func (d *OutboxDispatcher) Requeue(ctx context.Context, id uuid.UUID) error {
+	if err := d.outboxRepo.Requeue(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrOutboxEventNotFound) {
+			return ErrOutboxEventNotFound
+		}
+		return err
+	}
+	d.logger.Info("Outbox event requeued", zap.String("id", id.String()))
+	return nil
+}
+
+// BuildSearchableArticle loads the author, category and tags an article document needs
+func BuildSearchableArticle(
+	ctx context.Context,
+	article *model.Article,
+	userRepo repository.UserRepository,
+	categoryRepo repository.CategoryRepository,
+	articleRepo repository.ArticleRepository,
+) (*search.SearchableArticle, error) {
+	author, err := userRepo.GetByID(ctx, article.AuthorID)
+	if err != nil {
+		return nil, fmt.Errorf("load author: %w", err)
+	}
+
+	category, err := categoryRepo.GetByID(ctx, article.CategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("load category: %w", err)
+	}
+
+	tags, err := articleRepo.GetTags(ctx, article.ID)
+	if err != nil {
+		return nil, fmt.Errorf("load tags: %w", err)
+	}
+
+	tagNames := make([]string, len(tags))
+	for i, t := range tags {
+		tagNames[i] = t.Name
+	}
+
+	return search.ArticleToSearchable(article, author.DisplayName, category.Name, category.Slug, tagNames), nil
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+)
+
+// PermissionService answers capability checks and manages who holds them:
+// roles from the permission matrix in model, plus per-category moderator grants.
+// Role changes and grants are written to the audit log.
+type PermissionService interface {
+	// Authorize reports whether the user holds the permission through their
+	// role or, when categoryID is set, through a grant for that category
+	Authorize(ctx context.Context, userID uuid.UUID, perm model.Permission, categoryID *uuid.UUID) (bool, error)
+	GetUserPermissions(ctx context.Context, userID uuid.UUID) (*UserPermissions, error)
+
+	SetRole(ctx context.Context, adminID, userID uuid.UUID, input SetRoleInput) (*model.User, error)
+	GrantCategory(ctx context.Context, adminID, userID, categoryID uuid.UUID) (*model.CategoryModerator, error)
+	RevokeCategory(ctx context.Context, adminID, userID, categoryID uuid.UUID) error
+	ListCategoryModerators(ctx context.Context, categoryID uuid.UUID) ([]model.CategoryModerator, error)
+}
+
+type SetRoleInput struct {
+	Role   model.UserRole `json:"role" validate:"required"`
+	Reason string         `json:"reason" validate:"max=500"`
+}
+
+// UserPermissions is everything a user may do, for admin views and the client
+type UserPermissions struct {
+	UserID      uuid.UUID                 `json:"userId"`
+	Role        model.UserRole            `json:"role"`
+	Permissions []model.Permission        `json:"permissions"`
+	Categories  []model.CategoryModerator `json:"categories"`
+}
+
+const roleKeyPrefix = "role:"
+
+var (
+	ErrInvalidRole        = &AppError{Code: "INVALID_ROLE", Message: "Unknown role"}
+	ErrCannotChangeRole   = &AppError{Code: "CANNOT_CHANGE_ROLE", Message: "The role of this user cannot be changed"}
+	ErrCannotGrantUser    = &AppError{Code: "CANNOT_GRANT_USER", Message: "This user cannot moderate categories"}
+	ErrCategoryNotGranted = &AppError{Code: "CATEGORY_NOT_GRANTED", Message: "User does not moderate this category"}
+)
+
+type permissionService struct {
+	permissionRepo repository.PermissionRepository
+	userRepo       repository.UserRepository
+	categoryRepo   repository.CategoryRepository
+	tx             repository.Transactor
+	moderation     ModerationService
+	redis          *repository.RedisClient
+	logger         *zap.Logger
+}
+
+func NewPermissionService(
+	permissionRepo repository.PermissionRepository,
+	userRepo repository.UserRepository,
+	categoryRepo repository.CategoryRepository,
+	tx repository.Transactor,
+	moderation ModerationService,
+	redis *repository.RedisClient,
+	logger *zap.Logger,
+) PermissionService {
+	return &permissionService{
+		permissionRepo: permissionRepo,
+		userRepo:       userRepo,
+		categoryRepo:   categoryRepo,
+		tx:             tx,
+		moderation:     moderation,
+		redis:          redis,
+		logger:         logger,
+	}
+}
+
+// Authorize reads the role from the database, so it is current even when
+// the caller's access token still carries an old one
+func (s *permissionService) Authorize(ctx context.Context, userID uuid.UUID, perm model.Permission, categoryID *uuid.UUID) (bool, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if user.Role.Can(perm) {
+		return true, nil
+	}
+	if categoryID == nil || !model.IsCategoryPermission(perm) || user.IsBannedAt(time.Now()) {
+		return false, nil
+	}
+
+	return s.permissionRepo.IsCategoryModerator(ctx, userID, *categoryID)
+}
+
+func (s *permissionService) GetUserPermissions(ctx context.Context, userID uuid.UUID) (*UserPermissions, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := s.permissionRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserPermissions{
+		UserID:      user.ID,
+		Role:        user.Role,
+		Permissions: append([]model.Permission{}, model.RolePermissions[user.Role]...),
+		Categories:  grants,
+	}, nil
+}
+
+// SetRole promotes or demotes a user. Tokens issued before the change keep
+// the old role, so a marker lets request-time checks see the new one until
+// they expire.
+func (s *permissionService) SetRole(ctx context.Context, adminID, userID uuid.UUID, input SetRoleInput) (*model.User, error) {
+	if !input.Role.Valid() {
+		return nil, ErrInvalidRole
+	}
+	// Admins cannot lock themselves out
+	if adminID == userID {
+		return nil, ErrCannotChangeRole
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == input.Role {
+		return user, nil
+	}
+	if isStaff(input.Role) && user.IsBannedAt(time.Now()) {
+		return nil, ErrCannotChangeRole
+	}
+
+	previous := user.Role
+	action := model.ModerationPromote
+	if input.Role.Rank() < previous.Rank() {
+		action = model.ModerationDemote
+	}
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.SetRole(ctx, userID, input.Role); err != nil {
+			return err
+		}
+		return s.moderation.Record(ctx, &model.ModerationAction{
+			ModeratorID: adminID,
+			TargetType:  model.ReportTargetUser,
+			TargetID:    userID,
+			Action:      action,
+			Reason:      optionalString(strings.TrimSpace(input.Reason)),
+		}, roleSnapshot{Role: previous}, roleSnapshot{Role: input.Role})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.redis.Set(ctx, roleKey(userID), string(input.Role), accessTokenTTL).Err(); err != nil {
+		s.logger.Warn("Failed to set role marker", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+
+	s.logger.Info("User role changed",
+		zap.String("user_id", userID.String()),
+		zap.String("admin_id", adminID.String()),
+		zap.String("from", string(previous)),
+		zap.String("to", string(input.Role)),
+	)
+
+	user.Role = input.Role
+	return user, nil
+}
+
+func (s *permissionService) GrantCategory(ctx context.Context, adminID, userID, categoryID uuid.UUID) (*model.CategoryModerator, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsBannedAt(time.Now()) {
+		return nil, ErrCannotGrantUser
+	}
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	grant := &model.CategoryModerator{
+		UserID:     userID,
+		CategoryID: categoryID,
+		GrantedBy:  &adminID,
+	}
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.permissionRepo.Grant(ctx, grant); err != nil {
+			return err
+		}
+		return s.moderation.Record(ctx, &model.ModerationAction{
+			ModeratorID: adminID,
+			TargetType:  model.ReportTargetUser,
+			TargetID:    userID,
+			Action:      model.ModerationGrant,
+		}, nil, grant)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	grant.CategoryName = category.Name
+	grant.User = &model.NotificationActor{
+		ID:          user.ID,
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		AvatarURL:   user.AvatarURL,
+	}
+	return grant, nil
+}
+
+func (s *permissionService) RevokeCategory(ctx context.Context, adminID, userID, categoryID uuid.UUID) error {
+	return s.tx.WithTx(ctx, func(ctx context.Context) error {
+		grant, err := s.permissionRepo.Revoke(ctx, userID, categoryID)
+		if err != nil {
+			if errors.Is(err, repository.ErrGrantNotFound) {
+				return ErrCategoryNotGranted
+			}
+			return err
+		}
+		return s.moderation.Record(ctx, &model.ModerationAction{
+			ModeratorID: adminID,
+			TargetType:  model.ReportTargetUser,
+			TargetID:    userID,
+			Action:      model.ModerationRevoke,
+		}, grant, nil)
+	})
+}
+
+func (s *permissionService) ListCategoryModerators(ctx context.Context, categoryID uuid.UUID) ([]model.CategoryModerator, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	return s.permissionRepo.ListByCategory(ctx, categoryID)
+}
+
+// roleSnapshot is what the audit log keeps of a role change
+type roleSnapshot struct {
+	Role model.UserRole `json:"role"`
+}
+
+func roleKey(userID uuid.UUID) string {
+	return roleKeyPrefix + userID.String()
+}
+
+// changedRole reads the role marker of a user; empty means the role in
+// their token is current
+func changedRole(ctx context.Context, rdb *repository.RedisClient, userID uuid.UUID) (model.UserRole, error) {
+	role, err := rdb.Get(ctx, roleKey(userID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", nil
+		}
+		return "", err
+	}
+	return model.UserRole(role), nil
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+)
+
+// QuotaService enforces upload quotas: a role default, raised for premium
+// users, unless an admin overrides it
+type QuotaService interface {
+	GetStatus(ctx context.Context, userID uuid.UUID) (*QuotaStatus, error)
+	// Check fails with a quota error when an upload of size bytes does not fit
+	Check(ctx context.Context, userID uuid.UUID, size int64) error
+	// Reserve is Check that also holds the space until Release, so
+	// concurrent uploads cannot together exceed the quota
+	Reserve(ctx context.Context, userID uuid.UUID, size int64) (uuid.UUID, error)
+	// Release frees a reservation once the upload is stored or has failed
+	Release(ctx context.Context, reservationID uuid.UUID)
+	SetOverride(ctx context.Context, adminID, userID uuid.UUID, input QuotaOverrideInput) (*QuotaStatus, error)
+}
+
+// QuotaStatus is a user's quota, usage and what is left. Limits are the
+// effective quota, Default the one the role and premium status give.
+type QuotaStatus struct {
+	Limits     model.UploadQuota `json:"limits"`
+	Default    model.UploadQuota `json:"default"`
+	Overridden bool              `json:"overridden"`
+
+	UsedBytes    int64 `json:"usedBytes"`
+	FileCount    int   `json:"fileCount"`
+	UploadsToday int   `json:"uploadsToday"`
+
+	RemainingBytes        int64     `json:"remainingBytes"`
+	RemainingUploadsToday int       `json:"remainingUploadsToday"`
+	ResetsAt              time.Time `json:"resetsAt"`
+}
+
+// QuotaOverrideInput replaces a user's overrides; a nil limit restores the default
+type QuotaOverrideInput struct {
+	MaxBytes        *int64 `json:"maxBytes"`
+	MaxDailyUploads *int   `json:"maxDailyUploads"`
+	Reason          string `json:"reason" validate:"max=500"`
+}
+
+// Quota error codes; the errors carry the QuotaStatus as details
+const (
+	CodeStorageQuotaExceeded = "STORAGE_QUOTA_EXCEEDED"
+	CodeDailyUploadLimit     = "DAILY_UPLOAD_LIMIT"
+)
+
+var ErrInvalidQuota = &AppError{Code: "INVALID_QUOTA", Message: "Quota limits cannot be negative"}
+
+// quotaReservationTTL bounds how long a reservation lost to a crash counts
+const quotaReservationTTL = 10 * time.Minute
+
+type quotaService struct {
+	quotaRepo  repository.QuotaRepository
+	userRepo   repository.UserRepository
+	tx         repository.Transactor
+	moderation ModerationService
+	logger     *zap.Logger
+}
+
+func NewQuotaService(
+	quotaRepo repository.QuotaRepository,
+	userRepo repository.UserRepository,
+	tx repository.Transactor,
+	moderation ModerationService,
+	logger *zap.Logger,
+) QuotaService {
+	return &quotaService{
+		quotaRepo:  quotaRepo,
+		userRepo:   userRepo,
+		tx:         tx,
+		moderation: moderation,
+		logger:     logger,
+	}
+}
+
+func (s *quotaService) GetStatus(ctx context.Context, userID uuid.UUID) (*QuotaStatus, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.quotaRepo.GetUsage(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return quotaStatus(model.UploadQuotaFor(user.Role, user.IsPremium), usage, time.Now()), nil
+}
+
+func (s *quotaService) Check(ctx context.Context, userID uuid.UUID, size int64) error {
+	status, err := s.GetStatus(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return quotaError(status, size)
+}
+
+func (s *quotaService) Reserve(ctx context.Context, userID uuid.UUID, size int64) (uuid.UUID, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var reservationID uuid.UUID
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		usage, err := s.quotaRepo.LockUsage(ctx, userID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := quotaError(quotaStatus(model.UploadQuotaFor(user.Role, user.IsPremium), usage, now), size); err != nil {
+			return err
+		}
+		reservationID, err = s.quotaRepo.Reserve(ctx, userID, size, now.Add(quotaReservationTTL))
+		return err
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return reservationID, nil
+}
+
+func (s *quotaService) Release(ctx context.Context, reservationID uuid.UUID) {
+	if err := s.quotaRepo.Release(ctx, reservationID); err != nil {
+		s.logger.Warn("Failed to release upload reservation", zap.String("reservation_id", reservationID.String()), zap.Error(err))
+	}
+}
+
+// quotaError is the quota error for an upload of size bytes, nil if it fits
+func quotaError(status *QuotaStatus, size int64) error {
+	if status.RemainingUploadsToday <= 0 {
+		return &AppError{
+			Code:    CodeDailyUploadLimit,
+			Message: fmt.Sprintf("Daily limit of %d uploads reached", status.Limits.MaxDailyUploads),
+			Details: status,
+		}
+	}
+	if size > status.RemainingBytes {
+		return &AppError{
+			Code:    CodeStorageQuotaExceeded,
+			Message: fmt.Sprintf("Storage quota exceeded, %s left", formatSize(status.RemainingBytes)),
+			Details: status,
+		}
+	}
+	return nil
+}
+
+// SetOverride changes a user's quota and records it in the audit log
+func (s *quotaService) SetOverride(ctx context.Context, adminID, userID uuid.UUID, input QuotaOverrideInput) (*QuotaStatus, error) {
+	if (input.MaxBytes != nil && *input.MaxBytes < 0) || (input.MaxDailyUploads != nil && *input.MaxDailyUploads < 0) {
+		return nil, ErrInvalidQuota
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	before, err := s.quotaRepo.GetUsage(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.quotaRepo.SetOverride(ctx, userID, input.MaxBytes, input.MaxDailyUploads); err != nil {
+			return err
+		}
+		return s.moderation.Record(ctx, &model.ModerationAction{
+			ModeratorID: adminID,
+			TargetType:  model.ReportTargetUser,
+			TargetID:    userID,
+			Action:      model.ModerationUpdate,
+			Reason:      optionalString(strings.TrimSpace(input.Reason)),
+		},
+			quotaSnapshot{MaxBytes: before.MaxBytes, MaxDailyUploads: before.MaxDailyUploads},
+			quotaSnapshot{MaxBytes: input.MaxBytes, MaxDailyUploads: input.MaxDailyUploads},
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Upload quota changed",
+		zap.String("user_id", userID.String()),
+		zap.String("admin_id", adminID.String()),
+	)
+
+	return s.GetStatus(ctx, userID)
+}
+
+// quotaStatus applies overrides to the default quota and works out what is left
+func quotaStatus(defaults model.UploadQuota, usage *model.UploadUsage, now time.Time) *QuotaStatus {
+	limits := defaults
+	if usage.MaxBytes != nil {
+		limits.MaxBytes = *usage.MaxBytes
+	}
+	if usage.MaxDailyUploads != nil {
+		limits.MaxDailyUploads = *usage.MaxDailyUploads
+	}
+
+	year, month, day := now.UTC().Date()
+	return &QuotaStatus{
+		Limits:                limits,
+		Default:               defaults,
+		Overridden:            usage.MaxBytes != nil || usage.MaxDailyUploads != nil,
+		UsedBytes:             usage.UsedBytes,
+		FileCount:             usage.FileCount,
+		UploadsToday:          usage.UploadsToday,
+		RemainingBytes:        max(limits.MaxBytes-usage.UsedBytes-usage.ReservedBytes, 0),
+		RemainingUploadsToday: max(limits.MaxDailyUploads-usage.UploadsToday-usage.ReservedUploads, 0),
+		ResetsAt:              time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// quotaSnapshot is what the audit log keeps of a quota override
+type quotaSnapshot struct {
+	MaxBytes        *int64 `json:"maxBytes"`
+	MaxDailyUploads *int   `json:"maxDailyUploads"`
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+	"github.com/neurogen-news/backend/internal/search"
+)
+
+// Indexes the reindexer knows how to rebuild, in the order they are processed
+var ReindexableIndexes = []string{search.IndexArticles, search.IndexUsers, search.IndexTags}
+
+// Outbox events that write to each index
+var indexEventTypes = map[string][]model.OutboxEventType{
+	search.IndexArticles: {model.OutboxArticleUpserted, model.OutboxArticleDeleted},
+	search.IndexUsers:    {model.OutboxUserUpserted},
+	search.IndexTags:     {model.OutboxTagUpserted},
+}
+
+// Replays reach back this much further than the rebuild started, covering
+// clock skew between this host and Postgres
+const reindexReplayMargin = time.Minute
+
+// IndexDiff compares what Postgres says an index should contain with what it does contain
+type IndexDiff struct {
+	Index    string
+	Expected int
+	Actual   int
+	Missing  []string // in Postgres but not in the index
+	Stale    []string // indexed with outdated data
+	Extra    []string // in the index but no longer in Postgres
+}
+
+// InSync reports whether the index matches Postgres
+func (d *IndexDiff) InSync() bool {
+	return len(d.Missing) == 0 && len(d.Stale) == 0 && len(d.Extra) == 0
+}
+
+// Reindexer rebuilds search indexes from Postgres. A rebuild fills a shadow index
+// and swaps it in atomically, so searches keep working during the run.
+// The outbox dispatcher keeps writing to the live index meanwhile; those writes
+// are lost with the swap, so the events behind them are replayed afterwards.
+type Reindexer struct {
+	outboxRepo   repository.OutboxRepository
+	articleRepo  repository.ArticleRepository
+	userRepo     repository.UserRepository
+	tagRepo      repository.TagRepository
+	searchClient *search.Client
+	batchSize    int
+	logger       *zap.Logger
+}
+
+func NewReindexer(
+	outboxRepo repository.OutboxRepository,
+	articleRepo repository.ArticleRepository,
+	userRepo repository.UserRepository,
+	tagRepo repository.TagRepository,
+	searchClient *search.Client,
+	batchSize int,
+	logger *zap.Logger,
+) *Reindexer {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	return &Reindexer{
+		outboxRepo:   outboxRepo,
+		articleRepo:  articleRepo,
+		userRepo:     userRepo,
+		tagRepo:      tagRepo,
+		searchClient: searchClient,
+		batchSize:    batchSize,
+		logger:       logger,
+	}
+}
+
+// Rebuild reloads an index from Postgres and returns the number of indexed documents
+func (r *Reindexer) Rebuild(ctx context.Context, index string) (int, error) {
+	started := time.Now().Add(-reindexReplayMargin)
+
+	rebuild, err := r.searchClient.BeginRebuild(ctx, index)
+	if err != nil {
+		return 0, err
+	}
+
+	switch index {
+	case search.IndexArticles:
+		err = r.streamArticles(ctx, func(docs []search.SearchableArticle) error {
+			return rebuild.Add(ctx, docs, len(docs))
+		})
+	case search.IndexUsers:
+		err = r.streamUsers(ctx, func(docs []search.SearchableUser) error {
+			return rebuild.Add(ctx, docs, len(docs))
+		})
+	case search.IndexTags:
+		err = r.streamTags(ctx, func(docs []search.SearchableTag) error {
+			return rebuild.Add(ctx, docs, len(docs))
+		})
+	}
+
+	// The live index is untouched until Commit, so a failed run just drops the copy
+	if err != nil {
+		rebuild.Abort(context.Background())
+		return rebuild.Count(), err
+	}
+
+	if err := rebuild.Commit(ctx); err != nil {
+		rebuild.Abort(context.Background())
+		return rebuild.Count(), err
+	}
+
+	// Deliver the changes the dispatcher made to the replaced index again
+	replayed, err := r.outboxRepo.Replay(ctx, indexEventTypes[index], started)
+	if err != nil {
+		return rebuild.Count(), fmt.Errorf("replay outbox events: %w", err)
+	}
+	r.logger.Info("Outbox events replayed", zap.String("index", index), zap.Int64("events", replayed))
+
+	return rebuild.Count(), nil
+}
+
+// Diff reports documents that are missing from, stale in or extra in an index
+// without changing it. Counters (views, comments, karma, article count) change
+// without outbox events and are ignored.
+func (r *Reindexer) Diff(ctx context.Context, index string) (*IndexDiff, error) {
+	d := newDiffer(index)
+	batch := int64(r.batchSize)
+
+	var err error
+	switch index {
+	case search.IndexArticles:
+		err = r.streamArticles(ctx, func(docs []search.SearchableArticle) error {
+			for _, doc := range docs {
+				d.expect(doc.ID, normalizeArticleDoc(doc))
+			}
+			return nil
+		})
+		if err == nil {
+			err = r.searchClient.ForEachArticle(ctx, batch, func(docs []search.SearchableArticle) error {
+				for _, doc := range docs {
+					d.check(doc.ID, normalizeArticleDoc(doc))
+				}
+				return nil
+			})
+		}
+	case search.IndexUsers:
+		err = r.streamUsers(ctx, func(docs []search.SearchableUser) error {
+			for _, doc := range docs {
+				doc.Karma = 0
+				d.expect(doc.ID, doc)
+			}
+			return nil
+		})
+		if err == nil {
+			err = r.searchClient.ForEachUser(ctx, batch, func(docs []search.SearchableUser) error {
+				for _, doc := range docs {
+					doc.Karma = 0
+					d.check(doc.ID, doc)
+				}
+				return nil
+			})
+		}
+	case search.IndexTags:
+		err = r.streamTags(ctx, func(docs []search.SearchableTag) error {
+			for _, doc := range docs {
+				doc.ArticleCount = 0
+				d.expect(doc.ID, doc)
+			}
+			return nil
+		})
+		if err == nil {
+			err = r.searchClient.ForEachTag(ctx, batch, func(docs []search.SearchableTag) error {
+				for _, doc := range docs {
+					doc.ArticleCount = 0
+					d.check(doc.ID, doc)
+				}
+				return nil
+			})
+		}
+	default:
+		err = fmt.Errorf("unknown index %q", index)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return d.result(), nil
+}
+
+func (r *Reindexer) streamArticles(ctx context.Context, fn func([]search.SearchableArticle) error) error {
+	after := uuid.Nil
+	for {
+		rows, err := r.articleRepo.ListPublishedForIndex(ctx, after, r.batchSize)
+		if err != nil {
+			return fmt.Errorf("load articles: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		docs := make([]search.SearchableArticle, len(rows))
+		for i := range rows {
+			row := &rows[i]
+			docs[i] = *search.ArticleToSearchable(&row.Article, row.AuthorName, row.CategoryName, row.CategorySlug, row.Tags)
+		}
+		if err := fn(docs); err != nil {
+			return err
+		}
+
+		r.logger.Debug("Articles batch streamed", zap.Int("count", len(rows)))
+		after = rows[len(rows)-1].Article.ID
+	}
+}
+
+func (r *Reindexer) streamUsers(ctx context.Context, fn func([]search.SearchableUser) error) error {
+	after := uuid.Nil
+	for {
+		users, err := r.userRepo.ListForIndex(ctx, after, r.batchSize)
+		if err != nil {
+			return fmt.Errorf("load users: %w", err)
+		}
+		if len(users) == 0 {
+			return nil
+		}
+
+		docs := make([]search.SearchableUser, len(users))
+		for i := range users {
+			docs[i] = *search.UserToSearchable(&users[i])
+		}
+		if err := fn(docs); err != nil {
+			return err
+		}
+
+		after = users[len(users)-1].ID
+	}
+}
+
+func (r *Reindexer) streamTags(ctx context.Context, fn func([]search.SearchableTag) error) error {
+	after := uuid.Nil
+	for {
+		tags, err := r.tagRepo.ListForIndex(ctx, after, r.batchSize)
+		if err != nil {
+			return fmt.Errorf("load tags: %w", err)
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+
+		docs := make([]search.SearchableTag, len(tags))
+		for i := range tags {
+			docs[i] = *search.TagToSearchable(&tags[i])
+		}
+		if err := fn(docs); err != nil {
+			return err
+		}
+
+		after = tags[len(tags)-1].ID
+	}
+}
+
+func normalizeArticleDoc(doc search.SearchableArticle) search.SearchableArticle {
+	doc.ViewCount = 0
+	doc.CommentCount = 0
+	if doc.Tags == nil {
+		doc.Tags = []string{}
+	}
+	sort.Strings(doc.Tags)
+	return doc
+}
+
+// differ keeps only a fingerprint per document, so large indexes fit in memory
+type differ struct {
+	index    string
+	expected map[string]string
+	seen     map[string]bool
+	actual   int
+	stale    []string
+	extra    []string
+}
+
+func newDiffer(index string) *differ {
+	return &differ{
+		index:    index,
+		expected: make(map[string]string),
+		seen:     make(map[string]bool),
+	}
+}
+
+func (d *differ) expect(id string, doc interface{}) {
+	d.expected[id] = fingerprint(doc)
+}
+
+func (d *differ) check(id string, doc interface{}) {
+	d.actual++
+	want, ok := d.expected[id]
+	if !ok {
+		d.extra = append(d.extra, id)
+		return
+	}
+	d.seen[id] = true
+	if fingerprint(doc) != want {
+		d.stale = append(d.stale, id)
+	}
+}
+
+func (d *differ) result() *IndexDiff {
+	diff := &IndexDiff{
+		Index:    d.index,
+		Expected: len(d.expected),
+		Actual:   d.actual,
+		Stale:    d.stale,
+		Extra:    d.extra,
+	}
+	for id := range d.expected {
+		if !d.seen[id] {
+			diff.Missing = append(diff.Missing, id)
+		}
+	}
+
+	sort.Strings(diff.Missing)
+	sort.Strings(diff.Stale)
+	sort.Strings(diff.Extra)
+	return diff
+}
+
+func fingerprint(doc interface{}) string {
+	data, _ := json.Marshal(doc)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
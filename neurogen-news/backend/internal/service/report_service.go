@@ -0,0 +1,416 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+)
+
+type ReportService interface {
+	Create(ctx context.Context, reporterID uuid.UUID, input CreateReportInput) (*model.Report, error)
+
+	// Moderator queue
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Report, error)
+	List(ctx context.Context, params ReportListParams) (*ReportListResult, error)
+	Claim(ctx context.Context, moderatorID, id uuid.UUID) (*model.Report, error)
+	Assign(ctx context.Context, id uuid.UUID, assigneeID *uuid.UUID) (*model.Report, error)
+	Resolve(ctx context.Context, moderatorID, id uuid.UUID, input ResolveReportInput) (*model.Report, error)
+}
+
+type CreateReportInput struct {
+	TargetType  string    `json:"targetType" validate:"required,oneof=article comment user"`
+	TargetID    uuid.UUID `json:"targetId" validate:"required"`
+	Reason      string    `json:"reason" validate:"required"`
+	Description string    `json:"description" validate:"max=1000"`
+}
+
+type ReportListParams struct {
+	Status     string     `query:"status" validate:"omitempty,oneof=pending resolved dismissed"`
+	TargetType string     `query:"targetType" validate:"omitempty,oneof=article comment user"`
+	Reason     string     `query:"reason"`
+	AssignedTo *uuid.UUID `query:"-"`
+	Unassigned bool       `query:"unassigned"`
+	Page       int        `query:"page" validate:"min=1"`
+	PageSize   int        `query:"pageSize" validate:"min=1,max=100"`
+}
+
+type ReportListResult struct {
+	Items    []model.Report `json:"items"`
+	Total    int            `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"pageSize"`
+	HasMore  bool           `json:"hasMore"`
+}
+
+// ResolveReportInput closes a report. Action is applied to the reported target:
+// delete or hide the article or comment, warn or ban its author (or the reported user).
+type ResolveReportInput struct {
+	Status  string                     `json:"status" validate:"required,oneof=resolved dismissed"`
+	Action  model.ModerationActionType `json:"action,omitempty" validate:"omitempty,oneof=delete hide warn ban"`
+	Note    string                     `json:"note,omitempty" validate:"max=1000"`
+	BanDays int                        `json:"banDays,omitempty" validate:"min=0"` // 0 bans permanently
+}
+
+// Reports a user may file per window
+const (
+	reportRateLimit  = 10
+	reportRateWindow = time.Hour
+)
+
+var (
+	ErrInvalidReportTarget  = &AppError{Code: "INVALID_REPORT_TARGET", Message: "Invalid report target"}
+	ErrReportTargetNotFound = &AppError{Code: "REPORT_TARGET_NOT_FOUND", Message: "Reported content not found"}
+	ErrInvalidReportReason  = &AppError{Code: "INVALID_REPORT_REASON", Message: "Invalid report reason"}
+	ErrReportRateLimited    = &AppError{Code: "RATE_LIMITED", Message: "Too many reports, try again later"}
+	ErrReportExists         = &AppError{Code: "REPORT_EXISTS", Message: "You have already reported this"}
+	ErrReportClosed         = &AppError{Code: "REPORT_CLOSED", Message: "Report is already closed"}
+	ErrReportClaimed        = &AppError{Code: "REPORT_CLAIMED", Message: "Report is assigned to another moderator"}
+	ErrInvalidResolution    = &AppError{Code: "INVALID_RESOLUTION", Message: "Action is not applicable to this report"}
+	ErrInvalidAssignee      = &AppError{Code: "INVALID_ASSIGNEE", Message: "Reports can only be assigned to staff"}
+)
+
+type reportService struct {
+	reportRepo  repository.ReportRepository
+	articleRepo repository.ArticleRepository
+	commentRepo repository.CommentRepository
+	userRepo    repository.UserRepository
+	tx          repository.Transactor
+	moderation  ModerationService
+	bans        BanService
+	redis       *repository.RedisClient
+	logger      *zap.Logger
+}
+
+func NewReportService(
+	reportRepo repository.ReportRepository,
+	articleRepo repository.ArticleRepository,
+	commentRepo repository.CommentRepository,
+	userRepo repository.UserRepository,
+	tx repository.Transactor,
+	moderation ModerationService,
+	bans BanService,
+	redis *repository.RedisClient,
+	logger *zap.Logger,
+) ReportService {
+	return &reportService{
+		reportRepo:  reportRepo,
+		articleRepo: articleRepo,
+		commentRepo: commentRepo,
+		userRepo:    userRepo,
+		tx:          tx,
+		moderation:  moderation,
+		bans:        bans,
+		redis:       redis,
+		logger:      logger,
+	}
+}
+
+func (s *reportService) Create(ctx context.Context, reporterID uuid.UUID, input CreateReportInput) (*model.Report, error) {
+	if !isReportReason(input.Reason) {
+		return nil, ErrInvalidReportReason
+	}
+
+	authorID, err := s.targetAuthor(ctx, input.TargetType, input.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if authorID == reporterID {
+		return nil, ErrInvalidReportTarget
+	}
+
+	allowed, err := s.redis.CheckRateLimit(ctx, "ratelimit:report:"+reporterID.String(), reportRateLimit, reportRateWindow)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrReportRateLimited
+	}
+
+	report := &model.Report{
+		ReporterID: reporterID,
+		TargetType: input.TargetType,
+		TargetID:   input.TargetID,
+		Reason:     input.Reason,
+	}
+	if description := strings.TrimSpace(input.Description); description != "" {
+		report.Description = &description
+	}
+
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		if errors.Is(err, repository.ErrReportExists) {
+			return nil, ErrReportExists
+		}
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *reportService) GetByID(ctx context.Context, id uuid.UUID) (*model.Report, error) {
+	return s.reportRepo.GetByID(ctx, id)
+}
+
+func (s *reportService) List(ctx context.Context, params ReportListParams) (*ReportListResult, error) {
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 || params.PageSize > 100 {
+		params.PageSize = 20
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+
+	reports, total, err := s.reportRepo.List(ctx, repository.ReportListParams{
+		Status:     params.Status,
+		TargetType: params.TargetType,
+		Reason:     params.Reason,
+		AssignedTo: params.AssignedTo,
+		Unassigned: params.Unassigned,
+		Limit:      params.PageSize,
+		Offset:     offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReportListResult{
+		Items:    reports,
+		Total:    total,
+		Page:     params.Page,
+		PageSize: params.PageSize,
+		HasMore:  offset+len(reports) < total,
+	}, nil
+}
+
+func (s *reportService) Claim(ctx context.Context, moderatorID, id uuid.UUID) (*model.Report, error) {
+	claimed, err := s.reportRepo.Claim(ctx, id, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.reportRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		if report.Status != model.ReportStatusPending {
+			return nil, ErrReportClosed
+		}
+		return nil, ErrReportClaimed
+	}
+
+	return report, nil
+}
+
+func (s *reportService) Assign(ctx context.Context, id uuid.UUID, assigneeID *uuid.UUID) (*model.Report, error) {
+	if assigneeID != nil {
+		assignee, err := s.userRepo.GetByID(ctx, *assigneeID)
+		if err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return nil, ErrInvalidAssignee
+			}
+			return nil, err
+		}
+		if !isStaff(assignee.Role) {
+			return nil, ErrInvalidAssignee
+		}
+	}
+
+	assigned, err := s.reportRepo.Assign(ctx, id, assigneeID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.reportRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !assigned {
+		return nil, ErrReportClosed
+	}
+
+	return report, nil
+}
+
+// Resolve closes a report and applies the chosen action in one transaction.
+// When an action is applied, other open reports about the same target are
+// closed with it.
+func (s *reportService) Resolve(ctx context.Context, moderatorID, id uuid.UUID, input ResolveReportInput) (*model.Report, error) {
+	report, err := s.reportRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != model.ReportStatusPending {
+		return nil, ErrReportClosed
+	}
+	if report.AssignedTo != nil && *report.AssignedTo != moderatorID {
+		return nil, ErrReportClaimed
+	}
+	if input.Action != "" && input.Status != model.ReportStatusResolved {
+		return nil, ErrInvalidResolution
+	}
+	if report.TargetType == model.ReportTargetUser && (input.Action == model.ModerationDelete || input.Action == model.ModerationHide) {
+		return nil, ErrInvalidResolution
+	}
+
+	var authorID uuid.UUID
+	if input.Action != "" {
+		if authorID, err = s.targetAuthor(ctx, report.TargetType, report.TargetID); err != nil {
+			return nil, err
+		}
+	}
+
+	resolution := repository.ReportResolution{
+		ID:            id,
+		Status:        input.Status,
+		ResolvedBy:    moderatorID,
+		IncludeTarget: input.Action != "",
+	}
+	if input.Action != "" {
+		resolution.Resolution = &input.Action
+	}
+	if note := strings.TrimSpace(input.Note); note != "" {
+		resolution.Note = &note
+	}
+
+	var resolved *model.Report
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		closed, err := s.reportRepo.Resolve(ctx, resolution)
+		if err != nil {
+			return err
+		}
+		if closed == 0 {
+			return ErrReportClosed
+		}
+
+		if resolved, err = s.reportRepo.GetByID(ctx, id); err != nil {
+			return err
+		}
+
+		auditAction := model.ModerationResolve
+		if input.Status == model.ReportStatusDismissed {
+			auditAction = model.ModerationDismiss
+		}
+		err = s.moderation.Record(ctx, &model.ModerationAction{
+			ModeratorID: moderatorID,
+			TargetType:  model.AuditTargetReport,
+			TargetID:    id,
+			Action:      auditAction,
+			Reason:      resolution.Note,
+		}, report, resolved)
+		if err != nil {
+			return err
+		}
+
+		// Closing without an action clears content automoderation held back
+		if input.Action == "" {
+			if report.TargetType == model.ReportTargetUser {
+				return nil
+			}
+			return s.moderation.Release(ctx, moderatorID, report.TargetType, report.TargetID)
+		}
+		return s.applyAction(ctx, moderatorID, report, authorID, input, resolution.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// applyAction runs inside the resolve transaction; the moderation and ban
+// services record the action in the audit log
+func (s *reportService) applyAction(ctx context.Context, moderatorID uuid.UUID, report *model.Report, authorID uuid.UUID, input ResolveReportInput, note *string) error {
+	reason := ""
+	if note != nil {
+		reason = *note
+	}
+
+	switch input.Action {
+	case model.ModerationDelete:
+		if report.TargetType == model.ReportTargetComment {
+			return s.moderation.DeleteComment(ctx, moderatorID, report.TargetID, reason)
+		}
+		return s.moderation.DeleteArticle(ctx, moderatorID, report.TargetID, reason)
+
+	case model.ModerationHide:
+		if report.TargetType == model.ReportTargetComment {
+			return s.moderation.HideComment(ctx, moderatorID, report.TargetID, reason)
+		}
+		return s.moderation.HideArticle(ctx, moderatorID, report.TargetID, reason)
+
+	case model.ModerationWarn:
+		return s.moderation.Warn(ctx, moderatorID, authorID, report.TargetType, report.TargetID, reason)
+
+	case model.ModerationBan:
+		// Bans apply to a person, not to the reported content
+		if reason == "" {
+			reason = "Нарушение правил сообщества"
+		}
+		_, err := s.bans.Ban(ctx, moderatorID, authorID, BanInput{
+			Reason:    reason,
+			Days:      input.BanDays,
+			Permanent: input.BanDays == 0,
+		})
+		return err
+	}
+
+	return ErrInvalidResolution
+}
+
+// targetAuthor returns the user responsible for the reported target
+func (s *reportService) targetAuthor(ctx context.Context, targetType string, targetID uuid.UUID) (uuid.UUID, error) {
+	switch targetType {
+	case model.ReportTargetArticle:
+		article, err := s.articleRepo.GetByID(ctx, targetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrArticleNotFound) {
+				return uuid.Nil, ErrReportTargetNotFound
+			}
+			return uuid.Nil, err
+		}
+		return article.AuthorID, nil
+
+	case model.ReportTargetComment:
+		comment, err := s.commentRepo.GetByID(ctx, targetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrCommentNotFound) {
+				return uuid.Nil, ErrReportTargetNotFound
+			}
+			return uuid.Nil, err
+		}
+		return comment.AuthorID, nil
+
+	case model.ReportTargetUser:
+		user, err := s.userRepo.GetByID(ctx, targetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				return uuid.Nil, ErrReportTargetNotFound
+			}
+			return uuid.Nil, err
+		}
+		return user.ID, nil
+	}
+
+	return uuid.Nil, ErrInvalidReportTarget
+}
+
+func isReportReason(reason string) bool {
+	for _, r := range model.ReportReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func isStaff(role model.UserRole) bool {
+	return role == model.RoleAdmin || role == model.RoleEditor || role == model.RoleModerator
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+)
+
+// ReviewService is the pre-moderation queue: editors approve pending
+// articles, reject them or send them back to the author for changes
+type ReviewService interface {
+	ListPending(ctx context.Context, page, pageSize int) (*ArticleListResult, error)
+	Approve(ctx context.Context, reviewerID, id uuid.UUID, note string) (*model.Article, error)
+	Reject(ctx context.Context, reviewerID, id uuid.UUID, note string) (*model.Article, error)
+	RequestChanges(ctx context.Context, reviewerID, id uuid.UUID, note string) (*model.Article, error)
+}
+
+// PremoderationPolicy decides which authors publish through the review queue
+type PremoderationPolicy struct {
+	Enabled       bool
+	MinKarma      int           // authors below it are reviewed
+	MinAccountAge time.Duration // accounts younger than it are reviewed
+}
+
+// Requires reports whether an article by the user has to be reviewed before
+// it is published. Staff publish directly.
+func (p PremoderationPolicy) Requires(user *model.User, now time.Time) bool {
+	if !p.Enabled || isStaff(user.Role) {
+		return false
+	}
+	return user.Karma < p.MinKarma || now.Sub(user.CreatedAt) < p.MinAccountAge
+}
+
+var (
+	ErrArticleNotPending  = &AppError{Code: "ARTICLE_NOT_PENDING", Message: "Article is not awaiting review"}
+	ErrReviewNoteRequired = &AppError{Code: "REVIEW_NOTE_REQUIRED", Message: "Explain the decision to the author"}
+	ErrArticleRejected    = &AppError{Code: "ARTICLE_REJECTED", Message: "Rejected articles cannot be published"}
+)
+
+type reviewService struct {
+	articleRepo   repository.ArticleRepository
+	outboxRepo    repository.OutboxRepository
+	tx            repository.Transactor
+	moderation    ModerationService
+	notifications NotificationService
+	redis         *repository.RedisClient
+	logger        *zap.Logger
+}
+
+func NewReviewService(
+	articleRepo repository.ArticleRepository,
+	outboxRepo repository.OutboxRepository,
+	tx repository.Transactor,
+	moderation ModerationService,
+	notifications NotificationService,
+	redis *repository.RedisClient,
+	logger *zap.Logger,
+) ReviewService {
+	return &reviewService{
+		articleRepo:   articleRepo,
+		outboxRepo:    outboxRepo,
+		tx:            tx,
+		moderation:    moderation,
+		notifications: notifications,
+		redis:         redis,
+		logger:        logger,
+	}
+}
+
+func (s *reviewService) ListPending(ctx context.Context, page, pageSize int) (*ArticleListResult, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 50 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+
+	articles, total, err := s.articleRepo.List(ctx, repository.ArticleListParams{
+		Statuses: []model.ArticleStatus{model.StatusPending},
+		Sort:     "submitted",
+		Limit:    pageSize,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArticleListResult{
+		Items:    articles,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		HasMore:  offset+len(articles) < total,
+	}, nil
+}
+
+func (s *reviewService) Approve(ctx context.Context, reviewerID, id uuid.UUID, note string) (*model.Article, error) {
+	return s.review(ctx, reviewerID, id, note, model.StatusPublished, model.ReviewApproved, model.ModerationApprove)
+}
+
+// Reject archives the article; the author cannot publish it again
+func (s *reviewService) Reject(ctx context.Context, reviewerID, id uuid.UUID, note string) (*model.Article, error) {
+	if strings.TrimSpace(note) == "" {
+		return nil, ErrReviewNoteRequired
+	}
+	return s.review(ctx, reviewerID, id, note, model.StatusArchived, model.ReviewRejected, model.ModerationReject)
+}
+
+// RequestChanges returns the article to the author as a draft
+func (s *reviewService) RequestChanges(ctx context.Context, reviewerID, id uuid.UUID, note string) (*model.Article, error) {
+	if strings.TrimSpace(note) == "" {
+		return nil, ErrReviewNoteRequired
+	}
+	return s.review(ctx, reviewerID, id, note, model.StatusDraft, model.ReviewChangesRequested, model.ModerationRequestChanges)
+}
+
+func (s *reviewService) review(ctx context.Context, reviewerID, id uuid.UUID, note string, status model.ArticleStatus, decision string, action model.ModerationActionType) (*model.Article, error) {
+	article, err := s.articleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if article.Status != model.StatusPending {
+		return nil, ErrArticleNotPending
+	}
+
+	var tags []model.Tag
+	if status == model.StatusPublished {
+		tags, _ = s.articleRepo.GetTags(ctx, id)
+	}
+
+	var reviewed *model.Article
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		ok, err := s.articleRepo.Review(ctx, repository.ArticleReview{
+			ID:         id,
+			Status:     status,
+			Decision:   decision,
+			Note:       optionalString(note),
+			ReviewerID: reviewerID,
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrArticleNotPending
+		}
+
+		// Published articles enter the search index and tag counts
+		if status == model.StatusPublished {
+			if err := s.outboxRepo.Enqueue(ctx, model.OutboxArticleUpserted, id); err != nil {
+				return err
+			}
+			for _, t := range tags {
+				if err := s.outboxRepo.Enqueue(ctx, model.OutboxTagUpserted, t.ID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if reviewed, err = s.articleRepo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return s.moderation.Record(ctx, &model.ModerationAction{
+			ModeratorID: reviewerID,
+			TargetType:  model.ReportTargetArticle,
+			TargetID:    id,
+			Action:      action,
+			Reason:      optionalString(note),
+		}, article, reviewed)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if status == model.StatusPublished {
+		s.redis.Del(ctx, "articles:popular", "articles:new", "articles:hot")
+	}
+
+	if err := s.notifications.NotifyArticleReview(ctx, reviewed.AuthorID, reviewerID, id, reviewed.Title, decision, strings.TrimSpace(note)); err != nil {
+		s.logger.Warn("Failed to notify author about review", zap.String("article_id", id.String()), zap.Error(err))
+	}
+
+	return reviewed, nil
+}
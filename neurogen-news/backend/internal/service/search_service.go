@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"sort"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/neurogen-news/backend/internal/model"
@@ -12,7 +14,7 @@ import (
 
 type SearchService interface {
 	Search(ctx context.Context, query string, params SearchParams) (*SearchResult, error)
-	SearchArticles(ctx context.Context, query string, limit, offset int) ([]model.ArticleCard, int, error)
+	SearchArticles(ctx context.Context, query string, params SearchParams) ([]model.ArticleCard, int, error)
 	SearchUsers(ctx context.Context, query string, limit, offset int) ([]model.User, int, error)
 	GetSuggestions(ctx context.Context, query string, limit int) ([]SearchSuggestion, error)
 
@@ -27,7 +29,8 @@ type SearchParams struct {
 	Sort        string // relevance, new, popular
 	Level       string
 	ContentType string
-	Category    string
+	Category    string // slug or ID
+	Tags        []string
 	Limit       int
 	Offset      int
 }
@@ -43,6 +46,21 @@ type ArticleSearchResult struct {
 	Items   []model.ArticleCard `json:"items"`
 	Total   int                 `json:"total"`
 	HasMore bool                `json:"hasMore"`
+	Facets  *SearchFacets       `json:"facets,omitempty"`
+}
+
+// SearchFacets holds the number of matching articles per filter value,
+// most frequent first
+type SearchFacets struct {
+	Level        []FacetCount `json:"level"`
+	ContentType  []FacetCount `json:"contentType"`
+	CategorySlug []FacetCount `json:"categorySlug"`
+	Tags         []FacetCount `json:"tags"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
 }
 
 type UserSearchResult struct {
@@ -97,7 +115,7 @@ func (s *searchService) Search(ctx context.Context, query string, params SearchP
 	// Fallback to PostgreSQL search
 	switch params.Type {
 	case "articles":
-		articles, total, err := s.SearchArticles(ctx, query, params.Limit, params.Offset)
+		articles, total, err := s.SearchArticles(ctx, query, params)
 		if err != nil {
 			return nil, err
 		}
@@ -108,6 +126,13 @@ func (s *searchService) Search(ctx context.Context, query string, params SearchP
 		}
 		result.Total = total
 
+		facets, err := s.articleRepo.SearchFacets(ctx, articleSearchParams(query, params))
+		if err != nil {
+			s.logger.Warn("Failed to count search facets", zap.Error(err))
+		} else {
+			result.Articles.Facets = newSearchFacets(facets)
+		}
+
 	case "users":
 		users, total, err := s.SearchUsers(ctx, query, params.Limit, params.Offset)
 		if err != nil {
@@ -120,9 +145,20 @@ func (s *searchService) Search(ctx context.Context, query string, params SearchP
 		}
 		result.Total = total
 
+	case "tags":
+		tags, err := s.tagRepo.Search(ctx, query, params.Limit)
+		if err != nil {
+			return nil, err
+		}
+		result.Tags = tags
+		result.Total = len(tags)
+
 	default: // all
-		articles, articlesTotal, _ := s.SearchArticles(ctx, query, 10, 0)
+		articleParams := params
+		articleParams.Limit, articleParams.Offset = 10, 0
+		articles, articlesTotal, _ := s.SearchArticles(ctx, query, articleParams)
 		users, usersTotal, _ := s.SearchUsers(ctx, query, 5, 0)
+		tags, _ := s.tagRepo.Search(ctx, query, 5)
 
 		result.Articles = &ArticleSearchResult{
 			Items:   articles,
@@ -134,7 +170,8 @@ func (s *searchService) Search(ctx context.Context, query string, params SearchP
 			Total:   usersTotal,
 			HasMore: usersTotal > 5,
 		}
-		result.Total = articlesTotal + usersTotal
+		result.Tags = tags
+		result.Total = articlesTotal + usersTotal + len(tags)
 	}
 
 	return result, nil
@@ -145,28 +182,18 @@ func (s *searchService) searchWithMeilisearch(ctx context.Context, query string,
 
 	switch params.Type {
 	case "articles":
-		searchResult, err := s.searchClient.SearchArticles(ctx, search.SearchArticleParams{
-			Query:       query,
-			Level:       params.Level,
-			ContentType: params.ContentType,
-			CategoryID:  params.Category,
-			Sort:        params.Sort,
-			Limit:       int64(params.Limit),
-			Offset:      int64(params.Offset),
-		})
+		articleParams := meiliArticleParams(query, params, int64(params.Limit), int64(params.Offset))
+		articleParams.Facets = true
+		searchResult, err := s.searchClient.SearchArticles(ctx, articleParams)
 		if err != nil {
 			return nil, err
 		}
 
-		articles := make([]model.ArticleCard, len(searchResult.Hits))
-		for i, hit := range searchResult.Hits {
-			articles[i] = searchHitToArticleCard(hit)
-		}
-
 		result.Articles = &ArticleSearchResult{
-			Items:   articles,
+			Items:   searchHitsToArticleCards(searchResult),
 			Total:   int(searchResult.Total),
-			HasMore: int64(params.Offset)+int64(len(articles)) < searchResult.Total,
+			HasMore: int64(params.Offset)+int64(len(searchResult.Hits)) < searchResult.Total,
+			Facets:  newSearchFacets(searchResult.Facets),
 		}
 		result.Total = int(searchResult.Total)
 
@@ -203,17 +230,10 @@ func (s *searchService) searchWithMeilisearch(ctx context.Context, query string,
 
 	default: // all
 		// Search articles
-		articlesResult, _ := s.searchClient.SearchArticles(ctx, search.SearchArticleParams{
-			Query: query,
-			Limit: 10,
-		})
+		articlesResult, _ := s.searchClient.SearchArticles(ctx, meiliArticleParams(query, params, 10, 0))
 		if articlesResult != nil {
-			articles := make([]model.ArticleCard, len(articlesResult.Hits))
-			for i, hit := range articlesResult.Hits {
-				articles[i] = searchHitToArticleCard(hit)
-			}
 			result.Articles = &ArticleSearchResult{
-				Items:   articles,
+				Items:   searchHitsToArticleCards(articlesResult),
 				Total:   int(articlesResult.Total),
 				HasMore: articlesResult.Total > 10,
 			}
@@ -256,13 +276,9 @@ func (s *searchService) searchWithMeilisearch(ctx context.Context, query string,
 	return result, nil
 }
 
-func (s *searchService) SearchArticles(ctx context.Context, query string, limit, offset int) ([]model.ArticleCard, int, error) {
-	// Use PostgreSQL full-text search as fallback
-	return s.articleRepo.List(ctx, repository.ArticleListParams{
-		Sort:   "popular",
-		Limit:  limit,
-		Offset: offset,
-	})
+// SearchArticles runs the PostgreSQL full-text search used without Meilisearch
+func (s *searchService) SearchArticles(ctx context.Context, query string, params SearchParams) ([]model.ArticleCard, int, error) {
+	return s.articleRepo.Search(ctx, articleSearchParams(query, params))
 }
 
 func (s *searchService) SearchUsers(ctx context.Context, query string, limit, offset int) ([]model.User, int, error) {
@@ -315,6 +331,50 @@ func (s *searchService) GetSuggestions(ctx context.Context, query string, limit
 				Slug: tag.Slug,
 			})
 		}
+
+		return suggestions, nil
+	}
+
+	// PostgreSQL fallback, matching the last word as a prefix while the user types
+	articles, _, _ := s.articleRepo.Search(ctx, repository.ArticleSearchParams{
+		Query:  query,
+		Prefix: true,
+		Limit:  limit,
+	})
+	for _, article := range articles {
+		var image string
+		if article.CoverImageURL != nil {
+			image = *article.CoverImageURL
+		}
+		suggestions = append(suggestions, SearchSuggestion{
+			Type:  "article",
+			Text:  article.Title,
+			Slug:  article.CategorySlug + "/" + article.Slug,
+			Image: image,
+		})
+	}
+
+	users, _, _ := s.userRepo.Search(ctx, query, limit, 0)
+	for _, user := range users {
+		var avatar string
+		if user.AvatarURL != nil {
+			avatar = *user.AvatarURL
+		}
+		suggestions = append(suggestions, SearchSuggestion{
+			Type:  "user",
+			Text:  user.DisplayName,
+			Slug:  user.Username,
+			Image: avatar,
+		})
+	}
+
+	tags, _ := s.tagRepo.Search(ctx, query, limit)
+	for _, tag := range tags {
+		suggestions = append(suggestions, SearchSuggestion{
+			Type: "tag",
+			Text: tag.Name,
+			Slug: tag.Slug,
+		})
 	}
 
 	return suggestions, nil
@@ -345,7 +405,88 @@ func (s *searchService) DeleteArticleIndex(ctx context.Context, id string) error
 	return s.searchClient.DeleteArticle(ctx, id)
 }
 
+// parseCategoryFilter accepts either a category ID or a slug
+func parseCategoryFilter(category string) (*uuid.UUID, string) {
+	if category == "" {
+		return nil, ""
+	}
+	if id, err := uuid.Parse(category); err == nil {
+		return &id, ""
+	}
+	return nil, category
+}
+
+func articleSearchParams(query string, params SearchParams) repository.ArticleSearchParams {
+	categoryID, categorySlug := parseCategoryFilter(params.Category)
+
+	return repository.ArticleSearchParams{
+		Query:        query,
+		Level:        params.Level,
+		ContentType:  params.ContentType,
+		CategoryID:   categoryID,
+		CategorySlug: categorySlug,
+		Tags:         params.Tags,
+		Sort:         params.Sort,
+		Limit:        params.Limit,
+		Offset:       params.Offset,
+	}
+}
+
+func meiliArticleParams(query string, params SearchParams, limit, offset int64) search.SearchArticleParams {
+	categoryID, categorySlug := parseCategoryFilter(params.Category)
+
+	p := search.SearchArticleParams{
+		Query:        query,
+		Level:        params.Level,
+		ContentType:  params.ContentType,
+		CategorySlug: categorySlug,
+		Tags:         params.Tags,
+		Sort:         params.Sort,
+		Limit:        limit,
+		Offset:       offset,
+	}
+	if categoryID != nil {
+		p.CategoryID = categoryID.String()
+	}
+	return p
+}
+
+func newSearchFacets(facets map[string]map[string]int64) *SearchFacets {
+	return &SearchFacets{
+		Level:        sortedFacetCounts(facets["level"]),
+		ContentType:  sortedFacetCounts(facets["contentType"]),
+		CategorySlug: sortedFacetCounts(facets["categorySlug"]),
+		Tags:         sortedFacetCounts(facets["tags"]),
+	}
+}
+
+func sortedFacetCounts(counts map[string]int64) []FacetCount {
+	result := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		result = append(result, FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
+
 // Helper functions to convert search hits to models
+func searchHitsToArticleCards(result *search.SearchArticleResult) []model.ArticleCard {
+	articles := make([]model.ArticleCard, len(result.Hits))
+	for i, hit := range result.Hits {
+		articles[i] = searchHitToArticleCard(hit)
+		if i < len(result.Highlights) {
+			highlight := result.Highlights[i]
+			articles[i].Formatted = &highlight
+		}
+	}
+	return articles
+}
+
 func searchHitToArticleCard(hit search.SearchableArticle) model.ArticleCard {
 	return model.ArticleCard{
 		// ID would need parsing from string
@@ -383,4 +524,3 @@ func searchHitToTag(hit search.SearchableTag) model.Tag {
 		ArticleCount: hit.ArticleCount,
 	}
 }
-
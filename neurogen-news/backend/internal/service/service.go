@@ -1,10 +1,22 @@
 package service
 
 import (
+	"github.com/google/uuid"
 	"github.com/neurogen-news/backend/internal/repository"
+	"github.com/neurogen-news/backend/internal/search"
+	"github.com/neurogen-news/backend/internal/storage"
 	"go.uber.org/zap"
 )
 
+// Broadcaster pushes live updates to WebSocket clients (implemented by websocket.Hub)
+type Broadcaster interface {
+	BroadcastReaction(articleID uuid.UUID, reactions interface{})
+	BroadcastNewComment(articleID uuid.UUID, comment interface{})
+	SendNotification(userID uuid.UUID, notification interface{})
+	// DisconnectUser closes the user's connections on every instance
+	DisconnectUser(userID uuid.UUID)
+}
+
 type Services struct {
 	Auth         AuthService
 	User         UserService
@@ -18,29 +30,75 @@ type Services struct {
 	Draft        DraftService
 	Search       SearchService
 	Upload       UploadService
+	Stats        StatsService
+	Report       ReportService
+	Ban          BanService
+	Moderation   ModerationService
+	Review       ReviewService
+	Automod      AutomodService
+	Settings     SettingsService
+	Permission   PermissionService
+	Media        MediaService
+	Quota        QuotaService
+
+	// Background workers
+	Outbox          *OutboxDispatcher
+	BanExpirer      *BanExpirer
+	UploadExpirer   *UploadExpirer
+	MediaCollector  *MediaCollector
+	SettingsWatcher *SettingsWatcher
 }
 
 type Deps struct {
 	Repos     *repository.Repositories
 	Redis     *repository.RedisClient
 	JWTSecret string
+	Search    *search.Client // nil when Meilisearch is not configured
+	Hub       Broadcaster
+	Storage   storage.Storage
 	Logger    *zap.Logger
+
+	Premoderation PremoderationPolicy
 }
 
 func NewServices(deps Deps) *Services {
+	notifications := NewNotificationService(deps.Repos.Notification, deps.Repos.User, deps.Redis, deps.Hub, deps.Logger)
+	bans := NewBanService(deps.Repos.Ban, deps.Repos.User, deps.Repos.Moderation, deps.Repos.Tx, deps.Redis, deps.Hub, deps.Logger)
+	moderation := NewModerationService(deps.Repos.Moderation, deps.Repos.Article, deps.Repos.Comment, deps.Repos.Outbox, deps.Repos.User, deps.Repos.Tx, notifications, deps.Redis, deps.Hub, deps.Logger)
+	settings := NewSettingsService(deps.Repos.Settings, deps.Repos.Tx, moderation, deps.Redis, deps.Logger)
+	permissions := NewPermissionService(deps.Repos.Permission, deps.Repos.User, deps.Repos.Category, deps.Repos.Tx, moderation, deps.Redis, deps.Logger)
+	quotas := NewQuotaService(deps.Repos.Quota, deps.Repos.User, deps.Repos.Tx, moderation, deps.Logger)
+	automod := NewAutomodService(deps.Repos.Automod, deps.Repos.Report, deps.Repos.User, deps.Repos.Tx, moderation, deps.Redis, deps.Logger)
+
 	return &Services{
-		Auth:         NewAuthService(deps.Repos.User, deps.Redis, deps.JWTSecret, deps.Logger),
-		User:         NewUserService(deps.Repos.User, deps.Repos.Article, deps.Redis, deps.Logger),
-		Article:      NewArticleService(deps.Repos.Article, deps.Repos.Tag, deps.Redis, deps.Logger),
-		Comment:      NewCommentService(deps.Repos.Comment, deps.Repos.Notification, deps.Repos.Reaction, deps.Redis, deps.Logger),
+		Auth:         NewAuthService(deps.Repos.User, deps.Repos.Outbox, deps.Repos.Tx, deps.Redis, deps.JWTSecret, deps.Logger),
+		User:         NewUserService(deps.Repos.User, deps.Repos.Article, deps.Repos.Outbox, deps.Repos.Tx, deps.Redis, deps.Logger),
+		Article:      NewArticleService(deps.Repos.Article, deps.Repos.Version, deps.Repos.User, deps.Repos.Tag, deps.Repos.Reaction, deps.Repos.Outbox, deps.Repos.Tx, deps.Premoderation, automod, settings, permissions, moderation, deps.Redis, deps.Hub, deps.Logger),
+		Comment:      NewCommentService(deps.Repos.Comment, deps.Repos.Article, deps.Repos.User, deps.Repos.Reaction, deps.Repos.Tx, automod, settings, notifications, permissions, moderation, deps.Redis, deps.Hub, deps.Logger),
 		Category:     NewCategoryService(deps.Repos.Category, deps.Redis, deps.Logger),
 		Tag:          NewTagService(deps.Repos.Tag, deps.Redis, deps.Logger),
-		Notification: NewNotificationService(deps.Repos.Notification, deps.Repos.User, deps.Redis, deps.Logger),
+		Notification: notifications,
 		Achievement:  NewAchievementService(deps.Repos.Achievement, deps.Logger),
 		Bookmark:     NewBookmarkService(deps.Repos.Bookmark, deps.Logger),
 		Draft:        NewDraftService(deps.Repos.Draft, deps.Logger),
-		Search:       NewSearchService(deps.Repos.Article, deps.Repos.User, deps.Logger),
-		Upload:       NewUploadService(deps.Logger),
+		Search:       NewSearchService(deps.Repos.Article, deps.Repos.User, deps.Repos.Tag, deps.Search, deps.Logger),
+		Upload:       NewUploadService(deps.Repos.Upload, deps.Repos.Media, deps.Storage, settings, quotas, deps.Logger),
+		Stats:        NewStatsService(deps.Repos.Stats, deps.Redis, deps.Logger),
+		Report:       NewReportService(deps.Repos.Report, deps.Repos.Article, deps.Repos.Comment, deps.Repos.User, deps.Repos.Tx, moderation, bans, deps.Redis, deps.Logger),
+		Ban:          bans,
+		Moderation:   moderation,
+		Automod:      automod,
+		Settings:     settings,
+		Permission:   permissions,
+		Media:        NewMediaService(deps.Repos.Media, deps.Storage, deps.Logger),
+		Quota:        quotas,
+		Review:       NewReviewService(deps.Repos.Article, deps.Repos.Outbox, deps.Repos.Tx, moderation, notifications, deps.Redis, deps.Logger),
+
+		Outbox:          NewOutboxDispatcher(deps.Repos.Outbox, deps.Repos.Article, deps.Repos.User, deps.Repos.Category, deps.Repos.Tag, deps.Search, deps.Logger),
+		BanExpirer:      NewBanExpirer(deps.Repos.Ban, deps.Logger),
+		UploadExpirer:   NewUploadExpirer(deps.Repos.Upload, deps.Storage, deps.Logger),
+		MediaCollector:  NewMediaCollector(deps.Repos.Media, deps.Storage, deps.Logger),
+		SettingsWatcher: NewSettingsWatcher(settings, deps.Redis, deps.Logger),
 	}
 }
 
@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+)
+
+// SettingsService holds platform limits that admins tune at runtime. Stored
+// values override the defaults of the registry below; every instance keeps
+// the effective settings in memory and reloads them when another instance
+// announces a change.
+type SettingsService interface {
+	// Current returns the effective settings without touching storage
+	Current() PlatformSettings
+	// Reload reads the settings from Redis, falling back to the database
+	Reload(ctx context.Context) error
+
+	List(ctx context.Context) ([]SettingValue, error)
+	Update(ctx context.Context, adminID uuid.UUID, values map[string]json.RawMessage) ([]SettingValue, error)
+	History(ctx context.Context, key string, page, pageSize int) (*SettingHistoryResult, error)
+}
+
+// PlatformSettings are the effective values, read by services on every use
+type PlatformSettings struct {
+	RateLimitPerMinute int   `json:"rateLimitPerMinute"`
+	MaxImageSize       int64 `json:"maxImageSize"`
+	MaxArticleTags     int   `json:"maxArticleTags"`
+	MaxCommentLength   int   `json:"maxCommentLength"`
+}
+
+// SettingDefinition describes one setting of the registry. All current
+// settings are integers bounded by Min and Max.
+type SettingDefinition struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	Default     int64  `json:"default"`
+	Min         int64  `json:"min"`
+	Max         int64  `json:"max"`
+	Description string `json:"description"`
+
+	get func(PlatformSettings) int64
+	set func(*PlatformSettings, int64)
+}
+
+// SettingValue is a setting as shown to admins
+type SettingValue struct {
+	SettingDefinition
+	Value     int64      `json:"value"`
+	UpdatedBy *uuid.UUID `json:"updatedBy,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+type SettingHistoryResult struct {
+	Items    []model.SettingChange `json:"items"`
+	Total    int                   `json:"total"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"pageSize"`
+	HasMore  bool                  `json:"hasMore"`
+}
+
+var settingsRegistry = []SettingDefinition{
+	{
+		Key: "rateLimitPerMinute", Type: "int", Default: 100, Min: 10, Max: 10000,
+		Description: "Requests per minute allowed from one IP address",
+		get:         func(p PlatformSettings) int64 { return int64(p.RateLimitPerMinute) },
+		set:         func(p *PlatformSettings, v int64) { p.RateLimitPerMinute = int(v) },
+	},
+	{
+		// Bounded by the server body limit
+		Key: "maxImageSize", Type: "int", Default: 10 * 1024 * 1024, Min: 100 * 1024, Max: 10 * 1024 * 1024,
+		Description: "Largest image upload in bytes",
+		get:         func(p PlatformSettings) int64 { return p.MaxImageSize },
+		set:         func(p *PlatformSettings, v int64) { p.MaxImageSize = v },
+	},
+	{
+		Key: "maxArticleTags", Type: "int", Default: 10, Min: 1, Max: 50,
+		Description: "Tags per article",
+		get:         func(p PlatformSettings) int64 { return int64(p.MaxArticleTags) },
+		set:         func(p *PlatformSettings, v int64) { p.MaxArticleTags = int(v) },
+	},
+	{
+		Key: "maxCommentLength", Type: "int", Default: 10000, Min: 100, Max: 50000,
+		Description: "Characters per comment",
+		get:         func(p PlatformSettings) int64 { return int64(p.MaxCommentLength) },
+		set:         func(p *PlatformSettings, v int64) { p.MaxCommentLength = int(v) },
+	},
+}
+
+const (
+	settingsKey     = "settings:current"
+	settingsChannel = "settings:updated"
+
+	// Instances also reload periodically in case they missed an announcement
+	settingsReloadInterval = 5 * time.Minute
+)
+
+var (
+	ErrUnknownSetting = &AppError{Code: "UNKNOWN_SETTING", Message: "Unknown setting"}
+	ErrInvalidSetting = &AppError{Code: "INVALID_SETTING", Message: "Invalid setting value"}
+	ErrNoSettings     = &AppError{Code: "NO_SETTINGS", Message: "No settings to update"}
+)
+
+type settingsService struct {
+	settingsRepo repository.SettingsRepository
+	tx           repository.Transactor
+	moderation   ModerationService
+	redis        *repository.RedisClient
+	logger       *zap.Logger
+
+	mu      sync.RWMutex
+	current PlatformSettings
+}
+
+func NewSettingsService(
+	settingsRepo repository.SettingsRepository,
+	tx repository.Transactor,
+	moderation ModerationService,
+	redis *repository.RedisClient,
+	logger *zap.Logger,
+) SettingsService {
+	return &settingsService{
+		settingsRepo: settingsRepo,
+		tx:           tx,
+		moderation:   moderation,
+		redis:        redis,
+		logger:       logger,
+		current:      buildSettings(nil, logger),
+	}
+}
+
+func (s *settingsService) Current() PlatformSettings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+func (s *settingsService) Reload(ctx context.Context) error {
+	stored, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	settings := buildSettings(stored, s.logger)
+
+	s.mu.Lock()
+	s.current = settings
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *settingsService) List(ctx context.Context) ([]SettingValue, error) {
+	stored, err := s.settingsRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]model.Setting, len(stored))
+	for _, st := range stored {
+		byKey[st.Key] = st
+	}
+
+	current := buildSettings(stored, s.logger)
+	values := make([]SettingValue, len(settingsRegistry))
+	for i, def := range settingsRegistry {
+		values[i] = SettingValue{SettingDefinition: def, Value: def.get(current)}
+		if st, ok := byKey[def.Key]; ok {
+			updatedAt := st.UpdatedAt
+			values[i].UpdatedBy = st.UpdatedBy
+			values[i].UpdatedAt = &updatedAt
+		}
+	}
+	return values, nil
+}
+
+// Update validates all values first and stores them in one transaction, with
+// a history entry per changed key and one audit log entry
+func (s *settingsService) Update(ctx context.Context, adminID uuid.UUID, values map[string]json.RawMessage) ([]SettingValue, error) {
+	if len(values) == 0 {
+		return nil, ErrNoSettings
+	}
+
+	before, err := s.settingsRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	previous := buildSettings(before, s.logger)
+	stored := make(map[string]json.RawMessage, len(before))
+	for _, st := range before {
+		stored[st.Key] = st.Value
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parsed := make(map[string]int64, len(values))
+	for _, key := range keys {
+		def, ok := settingDefinition(key)
+		if !ok {
+			return nil, &AppError{Code: ErrUnknownSetting.Code, Message: ErrUnknownSetting.Message + ": " + key}
+		}
+		v, err := def.parse(values[key])
+		if err != nil {
+			return nil, &AppError{Code: ErrInvalidSetting.Code, Message: fmt.Sprintf("%s: %s %s", ErrInvalidSetting.Message, key, err)}
+		}
+		parsed[key] = v
+	}
+
+	changedBefore := make(map[string]int64)
+	changedAfter := make(map[string]int64)
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		for _, key := range keys {
+			def, _ := settingDefinition(key)
+			if def.get(previous) == parsed[key] {
+				continue
+			}
+
+			value, _ := json.Marshal(parsed[key])
+			if err := s.settingsRepo.Set(ctx, &model.Setting{Key: key, Value: value, UpdatedBy: &adminID}); err != nil {
+				return err
+			}
+			err := s.settingsRepo.AddChange(ctx, &model.SettingChange{
+				Key:       key,
+				OldValue:  stored[key],
+				NewValue:  value,
+				ChangedBy: &adminID,
+			})
+			if err != nil {
+				return err
+			}
+
+			changedBefore[key] = def.get(previous)
+			changedAfter[key] = parsed[key]
+		}
+
+		if len(changedAfter) == 0 {
+			return nil
+		}
+		return s.moderation.Record(ctx, &model.ModerationAction{
+			ModeratorID: adminID,
+			TargetType:  model.AuditTargetSettings,
+			TargetID:    uuid.Nil,
+			Action:      model.ModerationUpdate,
+		}, changedBefore, changedAfter)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changedAfter) > 0 {
+		s.announce(ctx)
+	}
+
+	return s.List(ctx)
+}
+
+func (s *settingsService) History(ctx context.Context, key string, page, pageSize int) (*SettingHistoryResult, error) {
+	if key != "" {
+		if _, ok := settingDefinition(key); !ok {
+			return nil, ErrUnknownSetting
+		}
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 50
+	}
+
+	offset := (page - 1) * pageSize
+
+	changes, total, err := s.settingsRepo.ListChanges(ctx, key, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		changes = []model.SettingChange{}
+	}
+
+	return &SettingHistoryResult{
+		Items:    changes,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		HasMore:  offset+len(changes) < total,
+	}, nil
+}
+
+// load reads stored settings through the Redis cache
+func (s *settingsService) load(ctx context.Context) ([]model.Setting, error) {
+	var stored []model.Setting
+	err := s.redis.GetJSON(ctx, settingsKey, &stored)
+	if err == nil {
+		return stored, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		s.logger.Warn("Failed to read cached settings", zap.Error(err))
+	}
+
+	stored, err = s.settingsRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		stored = []model.Setting{}
+	}
+
+	if err := s.redis.SetJSON(ctx, settingsKey, stored, 0); err != nil {
+		s.logger.Warn("Failed to cache settings", zap.Error(err))
+	}
+	return stored, nil
+}
+
+// announce refreshes the cache and tells every instance, this one included, to reload
+func (s *settingsService) announce(ctx context.Context) {
+	if err := s.redis.Del(ctx, settingsKey).Err(); err != nil {
+		s.logger.Warn("Failed to clear settings cache", zap.Error(err))
+	}
+	if err := s.Reload(ctx); err != nil {
+		s.logger.Warn("Failed to reload settings", zap.Error(err))
+	}
+	if err := s.redis.Publish(ctx, settingsChannel, time.Now().Unix()).Err(); err != nil {
+		s.logger.Warn("Failed to announce settings change", zap.Error(err))
+	}
+}
+
+// parse validates a JSON value against the definition
+func (d SettingDefinition) parse(raw json.RawMessage) (int64, error) {
+	var v int64
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return 0, errors.New("must be an integer")
+	}
+	if v < d.Min || v > d.Max {
+		return 0, fmt.Errorf("must be between %d and %d", d.Min, d.Max)
+	}
+	return v, nil
+}
+
+func settingDefinition(key string) (SettingDefinition, bool) {
+	for _, def := range settingsRegistry {
+		if def.Key == key {
+			return def, true
+		}
+	}
+	return SettingDefinition{}, false
+}
+
+// buildSettings applies stored values over the defaults. Values that no
+// longer validate, e.g. after a bound was tightened, fall back to the default.
+func buildSettings(stored []model.Setting, logger *zap.Logger) PlatformSettings {
+	var settings PlatformSettings
+	for _, def := range settingsRegistry {
+		def.set(&settings, def.Default)
+	}
+
+	for _, st := range stored {
+		def, ok := settingDefinition(st.Key)
+		if !ok {
+			continue
+		}
+		v, err := def.parse(st.Value)
+		if err != nil {
+			logger.Warn("Ignoring invalid stored setting", zap.String("key", st.Key), zap.Error(err))
+			continue
+		}
+		def.set(&settings, v)
+	}
+
+	return settings
+}
+
+// SettingsWatcher reloads settings when any instance changes them
+type SettingsWatcher struct {
+	settings SettingsService
+	redis    *repository.RedisClient
+	logger   *zap.Logger
+}
+
+func NewSettingsWatcher(settings SettingsService, redis *repository.RedisClient, logger *zap.Logger) *SettingsWatcher {
+	return &SettingsWatcher{
+		settings: settings,
+		redis:    redis,
+		logger:   logger,
+	}
+}
+
+// Run loads the settings and then follows announcements until ctx is cancelled
+func (w *SettingsWatcher) Run(ctx context.Context) {
+	w.reload(ctx)
+
+	pubsub := w.redis.Subscribe(ctx, settingsChannel)
+	defer pubsub.Close()
+	messages := pubsub.Channel()
+
+	ticker := time.NewTicker(settingsReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-messages:
+			if !ok {
+				return
+			}
+			w.reload(ctx)
+		case <-ticker.C:
+			w.reload(ctx)
+		}
+	}
+}
+
+func (w *SettingsWatcher) reload(ctx context.Context) {
+	if err := w.settings.Reload(ctx); err != nil {
+		w.logger.Warn("Failed to reload settings", zap.Error(err))
+	}
+}
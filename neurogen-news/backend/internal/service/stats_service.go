@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+)
+
+const (
+	statsDashboardKey = "stats:dashboard"
+	statsDashboardTTL = time.Minute
+	statsSeriesDays   = 30
+
+	// Hourly HyperLogLogs of authenticated users; 24 of them make the last day
+	activeUsersKeyPrefix = "stats:active:"
+	activeUsersKeyTTL    = 25 * time.Hour
+
+	// Daily counters of unique article views; Postgres only keeps totals
+	dailyViewsKeyPrefix = "stats:views:"
+	dailyViewsKeyTTL    = (statsSeriesDays + 5) * 24 * time.Hour
+)
+
+type StatsService interface {
+	GetDashboard(ctx context.Context) (*model.DashboardStats, error)
+	TrackActiveUser(ctx context.Context, userID uuid.UUID)
+}
+
+type statsService struct {
+	statsRepo repository.StatsRepository
+	redis     *repository.RedisClient
+	logger    *zap.Logger
+}
+
+func NewStatsService(statsRepo repository.StatsRepository, redis *repository.RedisClient, logger *zap.Logger) StatsService {
+	return &statsService{
+		statsRepo: statsRepo,
+		redis:     redis,
+		logger:    logger,
+	}
+}
+
+func (s *statsService) GetDashboard(ctx context.Context) (*model.DashboardStats, error) {
+	var cached model.DashboardStats
+	if err := s.redis.GetJSON(ctx, statsDashboardKey, &cached); err == nil {
+		return &cached, nil
+	}
+
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(statsSeriesDays - 1))
+
+	totals, err := s.statsRepo.GetTotals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := s.statsRepo.GetDailySeries(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	if active, err := s.countActiveUsers(ctx, now); err == nil {
+		totals.ActiveUsers24h = active
+	} else {
+		s.logger.Warn("Failed to count active users", zap.Error(err))
+	}
+
+	if err := s.attachViews(ctx, series); err != nil {
+		s.logger.Warn("Failed to load daily views", zap.Error(err))
+	}
+
+	stats := &model.DashboardStats{
+		Stats:       *totals,
+		Series:      series,
+		GeneratedAt: now,
+	}
+
+	if err := s.redis.SetJSON(ctx, statsDashboardKey, stats, statsDashboardTTL); err != nil {
+		s.logger.Warn("Failed to cache dashboard stats", zap.Error(err))
+	}
+
+	return stats, nil
+}
+
+func (s *statsService) TrackActiveUser(ctx context.Context, userID uuid.UUID) {
+	key := activeUsersKey(time.Now().UTC())
+
+	pipe := s.redis.Pipeline()
+	pipe.PFAdd(ctx, key, userID.String())
+	pipe.Expire(ctx, key, activeUsersKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Debug("Failed to track active user", zap.Error(err))
+	}
+}
+
+// countActiveUsers merges the current hour with the 23 before it
+func (s *statsService) countActiveUsers(ctx context.Context, now time.Time) (int64, error) {
+	keys := make([]string, 24)
+	for i := range keys {
+		keys[i] = activeUsersKey(now.Add(-time.Duration(i) * time.Hour))
+	}
+	return s.redis.PFCount(ctx, keys...).Result()
+}
+
+func (s *statsService) attachViews(ctx context.Context, series []model.DailyStats) error {
+	if len(series) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(series))
+	for i := range series {
+		keys[i] = dailyViewsKeyPrefix + series[i].Date
+	}
+
+	values, err := s.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return err
+	}
+
+	for i, value := range values {
+		if str, ok := value.(string); ok {
+			series[i].Views, _ = strconv.ParseInt(str, 10, 64)
+		}
+	}
+
+	return nil
+}
+
+func activeUsersKey(t time.Time) string {
+	return activeUsersKeyPrefix + t.Format("2006010215")
+}
+
+// trackDailyView counts a unique article view towards today's dashboard series
+func trackDailyView(ctx context.Context, redis *repository.RedisClient) error {
+	key := dailyViewsKeyPrefix + time.Now().UTC().Format("2006-01-02")
+
+	pipe := redis.Pipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, dailyViewsKeyTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
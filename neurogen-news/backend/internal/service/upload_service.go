@@ -1,41 +1,145 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"mime/multipart"
-	"path/filepath"
+	"net/http"
+	"path"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/imaging"
+	"github.com/neurogen-news/backend/internal/model"
+	"github.com/neurogen-news/backend/internal/repository"
+	"github.com/neurogen-news/backend/internal/storage"
 )
 
 type UploadService interface {
 	UploadImage(ctx context.Context, userID uuid.UUID, file *multipart.FileHeader, uploadType string) (*UploadResult, error)
 	DeleteFile(ctx context.Context, userID uuid.UUID, fileURL string) error
+
+	// Direct uploads: the client PUTs the file to a presigned URL, then
+	// completes the upload so it is verified and processed
+	PresignUpload(ctx context.Context, userID uuid.UUID, input *PresignUploadInput) (*PresignUploadResult, error)
+	CompleteUpload(ctx context.Context, userID, uploadID uuid.UUID) (*UploadResult, error)
+}
+
+type PresignUploadInput struct {
+	Type        string `json:"type"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+}
+
+// PresignUploadResult tells the client how to send the file: Method to URL
+// with exactly these Headers, before ExpiresAt
+type PresignUploadResult struct {
+	ID        uuid.UUID         `json:"id"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expiresAt"`
 }
 
 type UploadResult struct {
-	URL       string `json:"url"`
-	Filename  string `json:"filename"`
-	Size      int64  `json:"size"`
-	MimeType  string `json:"mimeType"`
+	URL      string `json:"url"`
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+
+	// Variants by MIME type, narrowest first, including the original
+	Variants map[string][]ImageVariant `json:"variants"`
+	// Srcset by MIME type, ready for <source type="..." srcset="...">
+	Srcset map[string]string `json:"srcset"`
+}
+
+type ImageVariant struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Size   int64  `json:"size"`
+}
+
+var (
+	ErrFileNotFound    = &AppError{Code: "FILE_NOT_FOUND", Message: "File not found"}
+	ErrInvalidFileType = &AppError{Code: "INVALID_FILE_TYPE", Message: "Only JPEG, PNG, GIF and WebP images are allowed"}
+	ErrImageTooLarge   = &AppError{Code: "IMAGE_TOO_LARGE", Message: "Image dimensions are too large"}
+	ErrInvalidImage    = &AppError{Code: "INVALID_IMAGE", Message: "Image contains data that is not part of the image"}
+	ErrTooManyFrames   = &AppError{Code: "TOO_MANY_FRAMES", Message: fmt.Sprintf("Animations can have at most %d frames", imaging.MaxGIFFrames)}
+
+	ErrDirectUploadUnsupported = &AppError{Code: "DIRECT_UPLOAD_UNSUPPORTED", Message: "Direct uploads need object storage"}
+	ErrUploadNotFound          = &AppError{Code: "UPLOAD_NOT_FOUND", Message: "Upload not found or expired"}
+	ErrUploadIncomplete        = &AppError{Code: "UPLOAD_INCOMPLETE", Message: "The file has not been uploaded yet"}
+	ErrUploadMismatch          = &AppError{Code: "UPLOAD_MISMATCH", Message: "Uploaded file does not match the declared size or type"}
+)
+
+const (
+	// directUploadTTL is how long a presigned URL and its upload stay valid
+	directUploadTTL = 15 * time.Minute
+	// directUploadGrace is how long after expiry a completion is still
+	// accepted, and how long a claimed completion may take before the upload
+	// can be claimed again or removed
+	directUploadGrace     = 5 * time.Minute
+	directUploadSweep     = 5 * time.Minute
+	directUploadBatchSize = 100
+
+	// directUploadPrefix keeps unverified objects out of the public prefixes;
+	// verified images are stored under a key of their own
+	directUploadPrefix = "tmp/"
+)
+
+// imageVariants are the sizes rendered for each upload type
+var imageVariants = map[storage.UploadType][]imaging.Spec{
+	storage.UploadTypeAvatar: {
+		{Name: "s64", Width: 64, Height: 64},
+		{Name: "s128", Width: 128, Height: 128},
+		{Name: "s256", Width: 256, Height: 256},
+	},
+	storage.UploadTypeCover: {
+		{Name: "og", Width: 1200, Height: 630},
+	},
+	storage.UploadTypeArticle: {
+		{Name: "w320", Width: 320},
+		{Name: "w640", Width: 640},
+		{Name: "w960", Width: 960},
+		{Name: "w1280", Width: 1280},
+	},
 }
 
+// originalVariant names the original in variant lists
+const originalVariant = "original"
+
 type uploadService struct {
-	logger *zap.Logger
-	// TODO: Add S3 client for production
+	uploadRepo repository.UploadRepository
+	mediaRepo  repository.MediaRepository
+	storage    storage.Storage
+	settings   SettingsService
+	quotas     QuotaService
+	logger     *zap.Logger
 }
 
-func NewUploadService(logger *zap.Logger) UploadService {
+func NewUploadService(uploadRepo repository.UploadRepository, mediaRepo repository.MediaRepository, store storage.Storage, settings SettingsService, quotas QuotaService, logger *zap.Logger) UploadService {
 	return &uploadService{
-		logger: logger,
+		uploadRepo: uploadRepo,
+		mediaRepo:  mediaRepo,
+		storage:    store,
+		settings:   settings,
+		quotas:     quotas,
+		logger:     logger,
 	}
 }
 
+// Image types a direct upload can be declared as; the stored content is
+// validated when the upload completes
 var allowedImageTypes = map[string]bool{
 	"image/jpeg": true,
 	"image/png":  true,
@@ -43,71 +147,466 @@ var allowedImageTypes = map[string]bool{
 	"image/webp": true,
 }
 
-var maxImageSize int64 = 10 * 1024 * 1024 // 10MB
-
+// UploadImage strips metadata, renders the variants of the upload type,
+// stores everything under one key prefix and adds it to the media library
 func (s *uploadService) UploadImage(ctx context.Context, userID uuid.UUID, file *multipart.FileHeader, uploadType string) (*UploadResult, error) {
 	// Validate file size
+	maxImageSize := s.settings.Current().MaxImageSize
 	if file.Size > maxImageSize {
-		return nil, &AppError{Code: "FILE_TOO_LARGE", Message: "File size exceeds 10MB limit"}
+		return nil, &AppError{Code: "FILE_TOO_LARGE", Message: fmt.Sprintf("File size exceeds %s limit", formatSize(maxImageSize))}
 	}
 
-	// Validate file type
-	contentType := file.Header.Get("Content-Type")
-	if !allowedImageTypes[contentType] {
-		return nil, &AppError{Code: "INVALID_FILE_TYPE", Message: "Only JPEG, PNG, GIF and WebP images are allowed"}
-	}
-
-	// Open file
 	src, err := file.Open()
 	if err != nil {
 		return nil, err
 	}
 	defer src.Close()
 
-	// Read file content
-	content, err := io.ReadAll(src)
+	content, err := io.ReadAll(io.LimitReader(src, maxImageSize+1))
 	if err != nil {
 		return nil, err
 	}
 
-	// Generate unique filename
-	ext := filepath.Ext(file.Filename)
-	if ext == "" {
-		switch contentType {
-		case "image/jpeg":
-			ext = ".jpg"
-		case "image/png":
-			ext = ".png"
-		case "image/gif":
-			ext = ".gif"
-		case "image/webp":
-			ext = ".webp"
+	// The type comes from the content; the Content-Type header and file
+	// name are the client's claims
+	info, err := imaging.Validate(content)
+	if err != nil {
+		return nil, imageError(err)
+	}
+
+	reservation, err := s.quotas.Reserve(ctx, userID, int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+	defer s.quotas.Release(ctx, reservation)
+
+	return s.storeImage(ctx, userID, content, info, uploadType, uploadBase(uploadType, userID), file.Filename)
+}
+
+// storeImage processes an image, stores the original and its variants under
+// base and records it as media
+func (s *uploadService) storeImage(ctx context.Context, userID uuid.UUID, content []byte, info *imaging.Info, uploadType, base, name string) (*UploadResult, error) {
+	specs := imageVariants[storage.UploadType(uploadType)]
+	cropped := false
+	for _, spec := range specs {
+		cropped = cropped || spec.Height > 0
+	}
+
+	processed, err := imaging.Process(content, info, specs)
+	if err != nil {
+		return nil, imageError(err)
+	}
+
+	result := &UploadResult{
+		Width:    processed.Width,
+		Height:   processed.Height,
+		Variants: make(map[string][]ImageVariant),
+		Srcset:   make(map[string]string),
+	}
+
+	var stored []string
+	for _, out := range processed.Outputs {
+		key := variantKey(base, out.Name, out.Format)
+		if err := s.storage.Put(ctx, key, bytes.NewReader(out.Data), int64(len(out.Data)), out.ContentType); err != nil {
+			s.deleteKeys(ctx, stored)
+			return nil, err
+		}
+		stored = append(stored, key)
+
+		name := out.Name
+		if name == "" {
+			name = originalVariant
+			if out.Format == processed.Format {
+				result.URL = s.storage.URL(key)
+				result.Filename = key
+				result.Size = int64(len(out.Data))
+				result.MimeType = out.ContentType
+			}
 		}
+		result.Variants[out.ContentType] = append(result.Variants[out.ContentType], ImageVariant{
+			Name:   name,
+			URL:    s.storage.URL(key),
+			Width:  out.Width,
+			Height: out.Height,
+			Size:   int64(len(out.Data)),
+		})
 	}
 
-	filename := fmt.Sprintf("%s/%s/%s%s",
-		uploadType,
-		time.Now().Format("2006/01"),
-		uuid.New().String(),
-		strings.ToLower(ext),
+	media := &model.Media{
+		UserID:      userID,
+		Key:         result.Filename,
+		Name:        name,
+		Type:        uploadType,
+		ContentType: result.MimeType,
+		Size:        result.Size,
+		Width:       result.Width,
+		Height:      result.Height,
+	}
+	if err := s.mediaRepo.Create(ctx, media); err != nil {
+		s.deleteKeys(ctx, stored)
+		return nil, err
+	}
+
+	// Cropped variants have another aspect ratio than the original, so
+	// their srcset leaves the original out
+	for contentType, variants := range result.Variants {
+		sort.SliceStable(variants, func(i, j int) bool { return variants[i].Width < variants[j].Width })
+		var entries []string
+		for _, v := range variants {
+			if v.Name == originalVariant && cropped && len(variants) > 1 {
+				continue
+			}
+			entries = append(entries, fmt.Sprintf("%s %dw", v.URL, v.Width))
+		}
+		result.Srcset[contentType] = strings.Join(entries, ", ")
+	}
+
+	s.logger.Info("Image uploaded",
+		zap.String("user_id", userID.String()),
+		zap.String("key", result.Filename),
+		zap.Int("files", len(stored)),
 	)
 
-	// TODO: Upload to S3 in production
-	// For now, just return a placeholder URL
-	_ = content
+	return result, nil
+}
+
+// PresignUpload reserves a key for the user and signs a PUT of exactly the
+// declared size and type to it. Only object storage supports this.
+func (s *uploadService) PresignUpload(ctx context.Context, userID uuid.UUID, input *PresignUploadInput) (*PresignUploadResult, error) {
+	uploader, ok := s.storage.(storage.DirectUploader)
+	if !ok {
+		return nil, ErrDirectUploadUnsupported
+	}
 
-	url := "/uploads/" + filename
+	maxImageSize := s.settings.Current().MaxImageSize
+	if input.Size <= 0 || input.Size > maxImageSize {
+		return nil, &AppError{Code: "FILE_TOO_LARGE", Message: fmt.Sprintf("File size exceeds %s limit", formatSize(maxImageSize))}
+	}
+	if !allowedImageTypes[input.ContentType] {
+		return nil, ErrInvalidFileType
+	}
+	if err := s.quotas.Check(ctx, userID, input.Size); err != nil {
+		return nil, err
+	}
+
+	upload := &model.DirectUpload{
+		UserID:      userID,
+		Key:         directUploadPrefix + uploadBase(input.Type, userID) + imaging.Extension(strings.TrimPrefix(input.ContentType, "image/")),
+		Type:        input.Type,
+		ContentType: input.ContentType,
+		Size:        input.Size,
+		ExpiresAt:   time.Now().Add(directUploadTTL),
+	}
+
+	url, err := uploader.PresignPut(ctx, upload.Key, upload.ContentType, upload.Size, directUploadTTL)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.uploadRepo.Create(ctx, upload); err != nil {
+		return nil, err
+	}
 
-	return &UploadResult{
-		URL:      url,
-		Filename: filename,
-		Size:     file.Size,
-		MimeType: contentType,
+	return &PresignUploadResult{
+		ID:     upload.ID,
+		URL:    url,
+		Method: http.MethodPut,
+		Headers: map[string]string{
+			"Content-Type": upload.ContentType,
+		},
+		ExpiresAt: upload.ExpiresAt,
 	}, nil
 }
 
+// CompleteUpload claims the upload, checks the stored object against what
+// was presigned, sniffs its magic bytes and then processes it like a regular
+// upload under a new public key. Objects that fail verification are deleted
+// together with the upload; other failures release the claim for a retry.
+func (s *uploadService) CompleteUpload(ctx context.Context, userID, uploadID uuid.UUID) (*UploadResult, error) {
+	uploader, ok := s.storage.(storage.DirectUploader)
+	if !ok {
+		return nil, ErrDirectUploadUnsupported
+	}
+
+	// Uploads of other users are indistinguishable from missing ones
+	upload, err := s.uploadRepo.Claim(ctx, uploadID, userID, time.Now().Add(-directUploadGrace))
+	if err != nil {
+		if err == repository.ErrUploadNotFound {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+
+	object, err := uploader.Stat(ctx, upload.Key)
+	if err != nil {
+		s.releaseUpload(ctx, upload)
+		if err == storage.ErrObjectNotFound {
+			return nil, ErrUploadIncomplete
+		}
+		return nil, err
+	}
+	if object.Size != upload.Size || object.ContentType != upload.ContentType {
+		s.discardUpload(ctx, upload)
+		return nil, ErrUploadMismatch
+	}
+	// Other uploads may have used up the quota since presigning
+	reservation, err := s.quotas.Reserve(ctx, userID, upload.Size)
+	if err != nil {
+		if _, ok := err.(*AppError); ok {
+			s.discardUpload(ctx, upload)
+		} else {
+			s.releaseUpload(ctx, upload)
+		}
+		return nil, err
+	}
+	defer s.quotas.Release(ctx, reservation)
+
+	content, err := uploader.Read(ctx, upload.Key, upload.Size)
+	if err != nil {
+		s.releaseUpload(ctx, upload)
+		return nil, err
+	}
+	if int64(len(content)) != upload.Size {
+		s.discardUpload(ctx, upload)
+		return nil, ErrUploadMismatch
+	}
+	info, err := imaging.Validate(content)
+	if err != nil {
+		s.discardUpload(ctx, upload)
+		return nil, imageError(err)
+	}
+	if info.ContentType != upload.ContentType {
+		s.discardUpload(ctx, upload)
+		return nil, ErrUploadMismatch
+	}
+
+	// A fresh base means everything stored below belongs to this call alone
+	base := uploadBase(upload.Type, userID)
+	result, err := s.storeImage(ctx, userID, content, info, upload.Type, base, path.Base(upload.Key))
+	if err != nil {
+		if _, ok := err.(*AppError); ok {
+			s.discardUpload(ctx, upload)
+		} else {
+			s.releaseUpload(ctx, upload)
+		}
+		return nil, err
+	}
+
+	if _, err := s.uploadRepo.Complete(ctx, upload.ID); err != nil {
+		// The claim outlived the grace period and another completion won
+		if media, getErr := s.mediaRepo.GetByBase(ctx, base); getErr == nil {
+			if rmErr := removeMedia(ctx, s.mediaRepo, s.storage, media, s.logger); rmErr != nil {
+				s.logger.Warn("Failed to clean up upload", zap.String("key", media.Key), zap.Error(rmErr))
+			}
+		}
+		if err == repository.ErrUploadNotFound {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+
+	// The processed copy replaces the unverified object
+	s.deleteKeys(ctx, []string{upload.Key})
+
+	return result, nil
+}
+
+// releaseUpload lets the client complete the upload again
+func (s *uploadService) releaseUpload(ctx context.Context, upload *model.DirectUpload) {
+	if err := s.uploadRepo.Release(ctx, upload.ID); err != nil {
+		s.logger.Warn("Failed to release upload", zap.String("upload_id", upload.ID.String()), zap.Error(err))
+	}
+}
+
+// discardUpload removes a claimed upload that failed verification
+func (s *uploadService) discardUpload(ctx context.Context, upload *model.DirectUpload) {
+	s.deleteKeys(ctx, []string{upload.Key})
+	if err := s.uploadRepo.Delete(ctx, upload.ID); err != nil {
+		s.logger.Warn("Failed to delete upload", zap.String("upload_id", upload.ID.String()), zap.Error(err))
+	}
+}
+
+// DeleteFile removes an upload of the user, given the URL of the original or
+// any variant, together with its media record; files of other users are refused.
+// Uploads older than the media library have no record, so their owner is read
+// from the key.
 func (s *uploadService) DeleteFile(ctx context.Context, userID uuid.UUID, fileURL string) error {
-	// TODO: Implement file deletion from S3
+	key, ok := s.storage.KeyFromURL(fileURL)
+	if !ok {
+		return ErrFileNotFound
+	}
+
+	media, err := s.mediaRepo.GetByBase(ctx, keyBase(key))
+	if err == repository.ErrMediaNotFound {
+		return s.deleteUntracked(ctx, userID, key)
+	}
+	if err != nil {
+		return err
+	}
+	if media.UserID != userID {
+		return ErrForbidden
+	}
+
+	// Images still used in content stay, like in the media library
+	removed, err := removeUnusedMedia(ctx, s.mediaRepo, s.storage, media, s.logger)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrMediaInUse
+	}
 	return nil
 }
 
+// deleteUntracked removes the files of an upload that has no media record
+func (s *uploadService) deleteUntracked(ctx context.Context, userID uuid.UUID, key string) error {
+	owner, ok := uploadOwner(key)
+	if !ok {
+		return ErrFileNotFound
+	}
+	if owner != userID {
+		return ErrForbidden
+	}
+
+	if err := s.storage.DeleteFiles(ctx, derivedKeys(key)); err != nil {
+		return err
+	}
+
+	s.logger.Info("File deleted", zap.String("user_id", userID.String()), zap.String("key", key))
+	return nil
+}
+
+// imageError maps validation and processing errors to client errors
+func imageError(err error) error {
+	switch err {
+	case imaging.ErrUnsupportedFormat:
+		return ErrInvalidFileType
+	case imaging.ErrImageTooLarge:
+		return ErrImageTooLarge
+	case imaging.ErrPolyglot:
+		return ErrInvalidImage
+	case imaging.ErrTooManyFrames:
+		return ErrTooManyFrames
+	}
+	return err
+}
+
+// deleteKeys cleans up after a failed upload
+func (s *uploadService) deleteKeys(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.logger.Warn("Failed to clean up upload", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+// UploadExpirer removes direct uploads that were never completed, together
+// with whatever the client managed to upload
+type UploadExpirer struct {
+	uploadRepo repository.UploadRepository
+	storage    storage.Storage
+	logger     *zap.Logger
+}
+
+func NewUploadExpirer(uploadRepo repository.UploadRepository, store storage.Storage, logger *zap.Logger) *UploadExpirer {
+	return &UploadExpirer{
+		uploadRepo: uploadRepo,
+		storage:    store,
+		logger:     logger,
+	}
+}
+
+// Run removes expired uploads until ctx is cancelled
+func (e *UploadExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(directUploadSweep)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			uploads, err := e.uploadRepo.ListExpired(ctx, time.Now().Add(-directUploadGrace), directUploadBatchSize)
+			if err != nil {
+				e.logger.Warn("Failed to list expired uploads", zap.Error(err))
+				continue
+			}
+			for _, upload := range uploads {
+				if err := e.storage.Delete(ctx, upload.Key); err != nil {
+					e.logger.Warn("Failed to delete expired upload", zap.String("key", upload.Key), zap.Error(err))
+					continue
+				}
+				if err := e.uploadRepo.Delete(ctx, upload.ID); err != nil {
+					e.logger.Warn("Failed to delete expired upload", zap.String("upload_id", upload.ID.String()), zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+// uploadBase places an upload under its type and owner:
+// type/user/year/month/uuid, completed by variantKey
+func uploadBase(uploadType string, userID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/%s/%s",
+		uploadType,
+		userID.String(),
+		time.Now().Format("2006/01"),
+		uuid.New().String(),
+	)
+}
+
+// variantKey is base.ext for the original and base_name.ext for variants
+func variantKey(base, name, format string) string {
+	if name != "" {
+		base += "_" + name
+	}
+	return base + imaging.Extension(format)
+}
+
+// keyBase is the base an upload key was made from by variantKey
+func keyBase(key string) string {
+	base := strings.TrimSuffix(key, path.Ext(key))
+	if i := strings.LastIndex(base, "_"); i > strings.LastIndex(base, "/") {
+		base = base[:i]
+	}
+	return base
+}
+
+// derivedKeys lists every key an upload can have produced, given any of them
+func derivedKeys(key string) []string {
+	base := keyBase(key)
+
+	names := []string{""}
+	for _, spec := range imageVariants[storage.UploadType(strings.SplitN(key, "/", 2)[0])] {
+		names = append(names, spec.Name)
+	}
+
+	var keys []string
+	for _, name := range names {
+		for _, format := range []string{imaging.FormatJPEG, imaging.FormatPNG, imaging.FormatGIF, imaging.FormatWebP} {
+			keys = append(keys, variantKey(base, name, format))
+		}
+	}
+	return keys
+}
+
+// uploadOwner reads the owner from a key made by uploadBase
+func uploadOwner(key string) (uuid.UUID, bool) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 5 {
+		return uuid.Nil, false
+	}
+	owner, err := uuid.Parse(parts[1])
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return owner, true
+}
+
+// formatSize renders a byte limit for error messages
+func formatSize(bytes int64) string {
+	if bytes%(1024*1024) == 0 {
+		return fmt.Sprintf("%dMB", bytes/(1024*1024))
+	}
+	return fmt.Sprintf("%dKB", bytes/1024)
+}
@@ -25,7 +25,7 @@ type UserService interface {
 	GetFollowing(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.User, int, error)
 
 	// Articles
-	GetUserArticles(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.ArticleCard, int, error)
+	GetUserArticles(ctx context.Context, userID uuid.UUID, own bool, limit, offset int) ([]model.ArticleCard, int, error)
 }
 
 type UserProfile struct {
@@ -35,6 +35,9 @@ type UserProfile struct {
 	FollowerCount  int         `json:"followerCount"`
 	FollowingCount int         `json:"followingCount"`
 	IsFollowing    bool        `json:"isFollowing"`
+
+	// Set on the owner's own profile only
+	UploadQuota *QuotaStatus `json:"uploadQuota,omitempty"`
 }
 
 type UpdateProfileInput struct {
@@ -60,6 +63,8 @@ type UpdateSettingsInput struct {
 type userService struct {
 	userRepo    repository.UserRepository
 	articleRepo repository.ArticleRepository
+	outboxRepo  repository.OutboxRepository
+	tx          repository.Transactor
 	redis       *repository.RedisClient
 	logger      *zap.Logger
 }
@@ -67,12 +72,16 @@ type userService struct {
 func NewUserService(
 	userRepo repository.UserRepository,
 	articleRepo repository.ArticleRepository,
+	outboxRepo repository.OutboxRepository,
+	tx repository.Transactor,
 	redis *repository.RedisClient,
 	logger *zap.Logger,
 ) UserService {
 	return &userService{
 		userRepo:    userRepo,
 		articleRepo: articleRepo,
+		outboxRepo:  outboxRepo,
+		tx:          tx,
 		redis:       redis,
 		logger:      logger,
 	}
@@ -138,7 +147,13 @@ func (s *userService) UpdateProfile(ctx context.Context, userID uuid.UUID, input
 		user.Github = input.Github
 	}
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.outboxRepo.Enqueue(ctx, model.OutboxUserUpserted, user.ID)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -173,7 +188,19 @@ func (s *userService) GetFollowing(ctx context.Context, userID uuid.UUID, limit,
 	return s.userRepo.GetFollowing(ctx, userID, limit, offset)
 }
 
-func (s *userService) GetUserArticles(ctx context.Context, userID uuid.UUID, limit, offset int) ([]model.ArticleCard, int, error) {
-	return s.articleRepo.GetByAuthor(ctx, userID, limit, offset)
+// GetUserArticles lists published articles; authors looking at their own list
+// also see drafts, articles awaiting review and rejected ones
+func (s *userService) GetUserArticles(ctx context.Context, userID uuid.UUID, own bool, limit, offset int) ([]model.ArticleCard, int, error) {
+	if !own {
+		return s.articleRepo.GetByAuthor(ctx, userID, limit, offset)
+	}
+
+	return s.articleRepo.List(ctx, repository.ArticleListParams{
+		AuthorID: &userID,
+		Statuses: []model.ArticleStatus{model.StatusPending, model.StatusDraft, model.StatusPublished, model.StatusArchived},
+		Sort:     "updated",
+		Limit:    limit,
+		Offset:   offset,
+	})
 }
 
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// LocalStorage keeps objects on disk under a root directory. The server
+// serves the directory itself under the base URL.
+type LocalStorage struct {
+	root    string
+	baseURL string
+	logger  *zap.Logger
+}
+
+// NewLocalStorage creates the root directory if it does not exist
+func NewLocalStorage(root, baseURL string, logger *zap.Logger) (*LocalStorage, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve upload path: %w", err)
+	}
+	if err := os.MkdirAll(absRoot, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	return &LocalStorage{
+		root:    absRoot,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		logger:  logger,
+	}, nil
+}
+
+// Root is the directory objects are stored in
+func (s *LocalStorage) Root() string {
+	return s.root
+}
+
+// Put writes to a temporary file first so readers never see a partial object
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("failed to store file: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.logger.Error("Failed to delete file", zap.String("key", key), zap.Error(err))
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// DeleteFiles removes every key, continuing past failures
+func (s *LocalStorage) DeleteFiles(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		if err := s.Delete(ctx, key); err != nil {
+			s.logger.Error("Failed to delete file", zap.String("key", key), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+func (s *LocalStorage) KeyFromURL(url string) (string, bool) {
+	key := strings.TrimPrefix(url, s.baseURL+"/")
+	if key == url || !ValidKey(key) {
+		return "", false
+	}
+	return key, true
+}
+
+// path maps a key to a file inside the root
+func (s *LocalStorage) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -3,10 +3,10 @@ package storage
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
-	"net/http"
 	"path/filepath"
 	"strings"
 	"time"
@@ -15,8 +15,11 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+
+	"github.com/neurogen-news/backend/internal/imaging"
 )
 
 // UploadType represents the type of upload
@@ -44,25 +47,20 @@ type S3Config struct {
 	AccessKeyID     string
 	SecretAccessKey string
 	Endpoint        string // For S3-compatible services (MinIO, DigitalOcean Spaces, etc.)
+	PublicEndpoint  string // Endpoint browsers reach when it differs from Endpoint (MinIO in Docker)
 	CDNBaseURL      string // CDN URL for public access
 	MaxFileSize     int64  // Maximum file size in bytes
 }
 
 // S3Client wraps AWS S3 client
 type S3Client struct {
-	client     *s3.Client
-	bucket     string
-	cdnBaseURL string
-	maxSize    int64
-	logger     *zap.Logger
-}
-
-// Allowed image MIME types
-var allowedImageTypes = map[string]string{
-	"image/jpeg": ".jpg",
-	"image/png":  ".png",
-	"image/gif":  ".gif",
-	"image/webp": ".webp",
+	client         *s3.Client
+	presigner      *s3.PresignClient
+	bucket         string
+	cdnBaseURL     string
+	publicEndpoint string
+	maxSize        int64
+	logger         *zap.Logger
 }
 
 // NewS3Client creates a new S3 client
@@ -93,6 +91,20 @@ func NewS3Client(cfg S3Config, logger *zap.Logger) (*S3Client, error) {
 
 	client := s3.NewFromConfig(awsCfg, clientOpts...)
 
+	// Presigned URLs are opened by browsers, so they are signed for the public endpoint
+	publicEndpoint := cfg.PublicEndpoint
+	if publicEndpoint == "" {
+		publicEndpoint = cfg.Endpoint
+	}
+	presigner := s3.NewPresignClient(client)
+	if publicEndpoint != cfg.Endpoint {
+		presigner = s3.NewPresignClient(client, func(o *s3.PresignOptions) {
+			o.ClientOptions = append(o.ClientOptions, func(o *s3.Options) {
+				o.BaseEndpoint = aws.String(publicEndpoint)
+			})
+		})
+	}
+
 	// Set default max size (10MB)
 	maxSize := cfg.MaxFileSize
 	if maxSize <= 0 {
@@ -100,11 +112,13 @@ func NewS3Client(cfg S3Config, logger *zap.Logger) (*S3Client, error) {
 	}
 
 	return &S3Client{
-		client:     client,
-		bucket:     cfg.Bucket,
-		cdnBaseURL: cfg.CDNBaseURL,
-		maxSize:    maxSize,
-		logger:     logger,
+		client:         client,
+		presigner:      presigner,
+		bucket:         cfg.Bucket,
+		cdnBaseURL:     cfg.CDNBaseURL,
+		publicEndpoint: strings.TrimSuffix(publicEndpoint, "/"),
+		maxSize:        maxSize,
+		logger:         logger,
 	}, nil
 }
 
@@ -128,17 +142,19 @@ func (c *S3Client) UploadImage(ctx context.Context, file *multipart.FileHeader,
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Detect content type
-	contentType := http.DetectContentType(content)
-
-	// Validate image type
-	ext, ok := allowedImageTypes[contentType]
-	if !ok {
-		return nil, fmt.Errorf("unsupported image type: %s", contentType)
+	// Identify the image by its content, never by the client's claims
+	info, err := imaging.Validate(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
 	}
+	// The file is stored as it is, so it may not carry trailing data
+	if !info.Clean {
+		return nil, fmt.Errorf("invalid image: %w", imaging.ErrPolyglot)
+	}
+	contentType := info.ContentType
 
 	// Generate unique filename
-	filename := generateFilename(uploadType, ext)
+	filename := generateFilename(uploadType, info.Extension)
 
 	// Upload to S3
 	_, err = c.client.PutObject(ctx, &s3.PutObjectInput{
@@ -172,20 +188,22 @@ func (c *S3Client) UploadImageFromBytes(ctx context.Context, content []byte, ori
 		return nil, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", c.maxSize)
 	}
 
-	// Detect content type
-	contentType := http.DetectContentType(content)
-
-	// Validate image type
-	ext, ok := allowedImageTypes[contentType]
-	if !ok {
-		return nil, fmt.Errorf("unsupported image type: %s", contentType)
+	// Identify the image by its content, never by the client's claims
+	info, err := imaging.Validate(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+	// The file is stored as it is, so it may not carry trailing data
+	if !info.Clean {
+		return nil, fmt.Errorf("invalid image: %w", imaging.ErrPolyglot)
 	}
+	contentType := info.ContentType
 
 	// Generate unique filename
-	filename := generateFilename(uploadType, ext)
+	filename := generateFilename(uploadType, info.Extension)
 
 	// Upload to S3
-	_, err := c.client.PutObject(ctx, &s3.PutObjectInput{
+	_, err = c.client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:       aws.String(c.bucket),
 		Key:          aws.String(filename),
 		Body:         bytes.NewReader(content),
@@ -212,7 +230,7 @@ func (c *S3Client) UploadImageFromBytes(ctx context.Context, content []byte, ori
 func (c *S3Client) DeleteFile(ctx context.Context, key string) error {
 	// Extract key from URL if full URL is provided
 	if strings.HasPrefix(key, "http") {
-		key = extractKeyFromURL(key, c.cdnBaseURL, c.bucket)
+		key = c.extractKeyFromURL(key)
 	}
 
 	_, err := c.client.DeleteObject(ctx, &s3.DeleteObjectInput{
@@ -227,12 +245,82 @@ func (c *S3Client) DeleteFile(ctx context.Context, key string) error {
 	return nil
 }
 
-// DeleteFiles deletes multiple files from S3
+// Put uploads an object as publicly cacheable
+func (c *S3Client) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+
+	_, err := c.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(c.bucket),
+		Key:           aws.String(key),
+		Body:          body,
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
+		CacheControl:  aws.String("public, max-age=31536000"),
+	})
+	if err != nil {
+		c.logger.Error("Failed to upload to S3", zap.String("key", key), zap.Error(err))
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an object by key
+func (c *S3Client) Delete(ctx context.Context, key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	return c.DeleteFile(ctx, key)
+}
+
+// URL is the CDN or bucket URL of an object
+func (c *S3Client) URL(key string) string {
+	return c.buildURL(key)
+}
+
+// KeyFromURL accepts URLs under the CDN or the bucket
+func (c *S3Client) KeyFromURL(url string) (string, bool) {
+	key := c.extractKeyFromURL(url)
+	if key == url || !ValidKey(key) {
+		return "", false
+	}
+	return key, true
+}
+
+// maxDeleteBatch is the most keys one DeleteObjects request accepts
+const maxDeleteBatch = 1000
+
+// DeleteFiles deletes multiple files from S3 in batches. Keys that fail are
+// logged; only failed requests are returned as errors.
 func (c *S3Client) DeleteFiles(ctx context.Context, keys []string) error {
+	objects := make([]types.ObjectIdentifier, 0, len(keys))
 	for _, key := range keys {
-		if err := c.DeleteFile(ctx, key); err != nil {
-			c.logger.Error("Failed to delete file", zap.String("key", key), zap.Error(err))
-			// Continue with other files
+		// Extract key from URL if full URL is provided
+		if strings.HasPrefix(key, "http") {
+			key = c.extractKeyFromURL(key)
+		}
+		objects = append(objects, types.ObjectIdentifier{Key: aws.String(key)})
+	}
+
+	for len(objects) > 0 {
+		batch := objects[:min(len(objects), maxDeleteBatch)]
+		objects = objects[len(batch):]
+
+		out, err := c.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(c.bucket),
+			Delete: &types.Delete{Objects: batch, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			c.logger.Error("Failed to delete files from S3", zap.Int("count", len(batch)), zap.Error(err))
+			return fmt.Errorf("failed to delete files: %w", err)
+		}
+		for _, e := range out.Errors {
+			c.logger.Error("Failed to delete file",
+				zap.String("key", aws.ToString(e.Key)),
+				zap.String("error", aws.ToString(e.Message)),
+			)
 		}
 	}
 	return nil
@@ -240,9 +328,7 @@ func (c *S3Client) DeleteFiles(ctx context.Context, keys []string) error {
 
 // GetPresignedURL generates a presigned URL for direct upload
 func (c *S3Client) GetPresignedURL(ctx context.Context, filename string, contentType string, expiration time.Duration) (string, error) {
-	presignClient := s3.NewPresignClient(c.client)
-
-	request, err := presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
+	request, err := c.presigner.PresignPutObject(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(c.bucket),
 		Key:         aws.String(filename),
 		ContentType: aws.String(contentType),
@@ -254,11 +340,85 @@ func (c *S3Client) GetPresignedURL(ctx context.Context, filename string, content
 	return request.URL, nil
 }
 
+// PresignPut signs a PUT bound to the key, content type and exact size.
+// Objects uploaded this way get the same caching headers as Put.
+func (c *S3Client) PresignPut(ctx context.Context, key, contentType string, size int64, expires time.Duration) (string, error) {
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+
+	request, err := c.presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(c.bucket),
+		Key:           aws.String(key),
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate presigned URL: %w", err)
+	}
+
+	return request.URL, nil
+}
+
+// Stat reads the size and content type of an object with a HEAD request
+func (c *S3Client) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	if !ValidKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	out, err := c.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	return &ObjectInfo{
+		Size:        aws.ToInt64(out.ContentLength),
+		ContentType: aws.ToString(out.ContentType),
+	}, nil
+}
+
+// Read fetches the first limit bytes of an object with a ranged GET
+func (c *S3Client) Read(ctx context.Context, key string, limit int64) ([]byte, error) {
+	if !ValidKey(key) {
+		return nil, ErrInvalidKey
+	}
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	out, err := c.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=0-%d", limit-1)),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer out.Body.Close()
+
+	return io.ReadAll(io.LimitReader(out.Body, limit))
+}
+
 // buildURL constructs the public URL for a file
 func (c *S3Client) buildURL(key string) string {
 	if c.cdnBaseURL != "" {
 		return strings.TrimSuffix(c.cdnBaseURL, "/") + "/" + key
 	}
+	// S3-compatible services are addressed path-style
+	if c.publicEndpoint != "" {
+		return fmt.Sprintf("%s/%s/%s", c.publicEndpoint, c.bucket, key)
+	}
 	// Default S3 URL format
 	return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", c.bucket, key)
 }
@@ -279,14 +439,22 @@ func generateFilename(uploadType UploadType, ext string) string {
 }
 
 // extractKeyFromURL extracts the S3 key from a full URL
-func extractKeyFromURL(url, cdnBaseURL, bucket string) string {
+func (c *S3Client) extractKeyFromURL(url string) string {
 	// Try CDN URL first
-	if cdnBaseURL != "" && strings.HasPrefix(url, cdnBaseURL) {
-		return strings.TrimPrefix(url, strings.TrimSuffix(cdnBaseURL, "/")+"/")
+	if c.cdnBaseURL != "" && strings.HasPrefix(url, c.cdnBaseURL) {
+		return strings.TrimPrefix(url, strings.TrimSuffix(c.cdnBaseURL, "/")+"/")
+	}
+
+	// Try the path-style endpoint URL
+	if c.publicEndpoint != "" {
+		endpointPrefix := fmt.Sprintf("%s/%s/", c.publicEndpoint, c.bucket)
+		if strings.HasPrefix(url, endpointPrefix) {
+			return strings.TrimPrefix(url, endpointPrefix)
+		}
 	}
 
 	// Try S3 URL
-	s3Prefix := fmt.Sprintf("https://%s.s3.amazonaws.com/", bucket)
+	s3Prefix := fmt.Sprintf("https://%s.s3.amazonaws.com/", c.bucket)
 	if strings.HasPrefix(url, s3Prefix) {
 		return strings.TrimPrefix(url, s3Prefix)
 	}
@@ -294,56 +462,3 @@ func extractKeyFromURL(url, cdnBaseURL, bucket string) string {
 	// Return as-is if no match
 	return url
 }
-
-// MockS3Client provides a mock implementation for development
-type MockS3Client struct {
-	logger *zap.Logger
-}
-
-// NewMockS3Client creates a mock S3 client for development
-func NewMockS3Client(logger *zap.Logger) *MockS3Client {
-	return &MockS3Client{logger: logger}
-}
-
-// UploadImage mocks image upload
-func (c *MockS3Client) UploadImage(ctx context.Context, file *multipart.FileHeader, uploadType UploadType) (*UploadResult, error) {
-	contentType := file.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "image/jpeg"
-	}
-
-	ext := ".jpg"
-	if e, ok := allowedImageTypes[contentType]; ok {
-		ext = e
-	}
-
-	filename := generateFilename(uploadType, ext)
-	url := "/uploads/" + filename
-
-	c.logger.Info("Mock upload",
-		zap.String("filename", filename),
-		zap.Int64("size", file.Size),
-		zap.String("contentType", contentType))
-
-	return &UploadResult{
-		URL:      url,
-		Key:      filename,
-		Filename: filepath.Base(filename),
-		Size:     file.Size,
-		MimeType: contentType,
-	}, nil
-}
-
-// DeleteFile mocks file deletion
-func (c *MockS3Client) DeleteFile(ctx context.Context, key string) error {
-	c.logger.Info("Mock delete", zap.String("key", key))
-	return nil
-}
-
-// Storage interface for dependency injection
-type Storage interface {
-	UploadImage(ctx context.Context, file *multipart.FileHeader, uploadType UploadType) (*UploadResult, error)
-	DeleteFile(ctx context.Context, key string) error
-}
-
-
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidKey is returned for keys that are empty or escape the storage root
+	ErrInvalidKey = errors.New("invalid storage key")
+	// ErrObjectNotFound is returned when reading an object that does not exist
+	ErrObjectNotFound = errors.New("object not found")
+)
+
+// Storage keeps uploaded objects under slash-separated keys and serves them
+// from public URLs. The backend is chosen at startup: S3 when it is enabled,
+// the local filesystem otherwise.
+type Storage interface {
+	// Put stores the object, replacing any object with the same key
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Delete removes the object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// DeleteFiles removes objects in bulk, logging the ones that fail
+	DeleteFiles(ctx context.Context, keys []string) error
+	// URL is the public address of the object
+	URL(key string) string
+	// KeyFromURL resolves a public URL of this storage back to its key
+	KeyFromURL(url string) (string, bool)
+}
+
+// ObjectInfo is what the storage reports about a stored object
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// DirectUploader is implemented by storages that clients can upload to
+// without going through the API, using a presigned URL
+type DirectUploader interface {
+	// PresignPut signs a PUT of exactly size bytes of contentType to key
+	PresignPut(ctx context.Context, key, contentType string, size int64, expires time.Duration) (string, error)
+	// Stat reports the size and type of an object
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Read returns at most limit bytes from the start of an object
+	Read(ctx context.Context, key string, limit int64) ([]byte, error)
+}
+
+// ValidKey reports whether a key is relative, clean and free of ".." segments
+func ValidKey(key string) bool {
+	if key == "" || key == "." || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	return path.Clean(key) == key && !strings.HasPrefix(key, "..")
+}
@@ -0,0 +1,214 @@
+// Package textdiff compares two texts line by line or word by word using
+// Myers' algorithm and reports the result as runs of kept, inserted and
+// deleted text. Joining the equal and deleted runs gives the old text back,
+// joining the equal and inserted runs gives the new one.
+package textdiff
+
+import (
+	"strings"
+	"unicode"
+)
+
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// Chunk is a run of text with the same operation
+type Chunk struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// MaxEdits bounds the work spent on texts that have little in common. Past
+// it the differing middle is reported as deleted and inserted whole.
+const MaxEdits = 1000
+
+// Lines diffs a and b by lines; line breaks stay with the line they end
+func Lines(a, b string) []Chunk {
+	return diff(splitLines(a), splitLines(b))
+}
+
+// Words diffs a and b by words; whitespace runs count as words of their own
+func Words(a, b string) []Chunk {
+	return diff(splitWords(a), splitWords(b))
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func splitWords(s string) []string {
+	var words []string
+	start := 0
+	space := false
+	for i, r := range s {
+		isSpace := unicode.IsSpace(r)
+		if i > start && isSpace != space {
+			words = append(words, s[start:i])
+			start = i
+		}
+		space = isSpace
+	}
+	if start < len(s) {
+		words = append(words, s[start:])
+	}
+	return words
+}
+
+func diff(a, b []string) []Chunk {
+	var out chunks
+
+	// The common prefix and suffix need no search
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	for _, t := range a[:prefix] {
+		out.add(OpEqual, t)
+	}
+	middleA, middleB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if !myers(middleA, middleB, &out) {
+		for _, t := range middleA {
+			out.add(OpDelete, t)
+		}
+		for _, t := range middleB {
+			out.add(OpInsert, t)
+		}
+	}
+	for _, t := range a[len(a)-suffix:] {
+		out.add(OpEqual, t)
+	}
+
+	return out.done()
+}
+
+// myers finds a shortest edit script from a to b and appends it to out. It
+// reports false, leaving out untouched, when more than MaxEdits are needed.
+func myers(a, b []string, out *chunks) bool {
+	n, m := len(a), len(b)
+	if n+m == 0 {
+		return true
+	}
+
+	// v[offset+k] is the furthest x reached on diagonal k = x - y. trace[d]
+	// keeps v[-d-1..d+1] as it was before step d, which is all the
+	// backtracking needs.
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		if d > MaxEdits {
+			return false
+		}
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				backtrack(a, b, trace, out)
+				return true
+			}
+		}
+	}
+	return true
+}
+
+// backtrack walks the trace from the end and emits the edit script in order
+func backtrack(a, b []string, trace [][]int, out *chunks) {
+	type step struct {
+		op    Op
+		token string
+	}
+	var steps []step
+
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		snapshot := trace[d]
+		at := func(k int) int { return snapshot[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			steps = append(steps, step{OpEqual, a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				steps = append(steps, step{OpInsert, b[y]})
+			} else {
+				x--
+				steps = append(steps, step{OpDelete, a[x]})
+			}
+		}
+	}
+
+	for i := len(steps) - 1; i >= 0; i-- {
+		out.add(steps[i].op, steps[i].token)
+	}
+}
+
+// chunks merges consecutive tokens with the same operation
+type chunks struct {
+	list []Chunk
+	op   Op
+	text strings.Builder
+}
+
+func (c *chunks) add(op Op, token string) {
+	if op != c.op {
+		c.flush()
+		c.op = op
+	}
+	c.text.WriteString(token)
+}
+
+func (c *chunks) flush() {
+	if c.text.Len() > 0 {
+		c.list = append(c.list, Chunk{Op: c.op, Text: c.text.String()})
+		c.text.Reset()
+	}
+}
+
+func (c *chunks) done() []Chunk {
+	c.flush()
+	return c.list
+}
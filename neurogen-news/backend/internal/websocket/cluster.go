@@ -0,0 +1,221 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Cross-instance delivery.
+//
+// Every instance subscribes to ws:broadcast and to its own ws:instance:<id>
+// channel. A presence set ws:presence:<userID> lists the instances holding
+// connections of a user, so per-user messages are published only to those
+// instances. Everything published carries the origin instance ID and each
+// instance drops its own echo, since local clients are served directly.
+//
+// Delivery is at-most-once per live connection (Redis pub/sub). Messages for a
+// user with no live connection anywhere - or whose publish reached nobody - go
+// to a short capped backlog that is replayed when the user connects again.
+
+const (
+	broadcastChannel      = "ws:broadcast"
+	instanceChannelPrefix = "ws:instance:"
+
+	// Presence entries expire unless refreshed by the owning instance
+	presenceTTL             = 90 * time.Second
+	presenceRefreshInterval = 30 * time.Second
+
+	// Undelivered per-user messages kept for replay on reconnect
+	backlogSize = 50
+	backlogTTL  = 24 * time.Hour
+)
+
+// envelope wraps every message published to Redis
+type envelope struct {
+	Origin string          `json:"origin"`
+	UserID uuid.UUID       `json:"userId,omitempty"`
+	Data   json.RawMessage `json:"data"`
+
+	// Disconnect asks every instance to close the connections of UserID
+	Disconnect bool `json:"disconnect,omitempty"`
+}
+
+func instanceChannel(instanceID string) string {
+	return instanceChannelPrefix + instanceID
+}
+
+func presenceKey(userID uuid.UUID) string {
+	return "ws:presence:" + userID.String()
+}
+
+func backlogKey(userID uuid.UUID) string {
+	return "ws:backlog:" + userID.String()
+}
+
+// publish sends data to a Redis channel tagged with this instance as origin.
+// It returns the number of instances that received it.
+func (h *Hub) publish(ctx context.Context, channel string, userID uuid.UUID, data []byte) (int64, error) {
+	payload, err := json.Marshal(envelope{
+		Origin: h.instanceID,
+		UserID: userID,
+		Data:   data,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return h.redis.Publish(ctx, channel, payload).Result()
+}
+
+// handleRedisMessage routes a message received from another instance
+func (h *Hub) handleRedisMessage(channel, payload string) {
+	var env envelope
+	if err := json.Unmarshal([]byte(payload), &env); err != nil {
+		h.logger.Warn("Dropping malformed cluster message",
+			zap.String("channel", channel), zap.Error(err))
+		return
+	}
+
+	// Local clients were already served by the publishing call
+	if env.Origin == h.instanceID {
+		return
+	}
+
+	switch {
+	case channel == broadcastChannel && env.Disconnect:
+		h.disconnectLocal(env.UserID)
+
+	case channel == broadcastChannel:
+		h.broadcastMessage(env.Data)
+
+	case strings.HasPrefix(channel, instanceChannelPrefix):
+		if env.UserID == uuid.Nil {
+			return
+		}
+		// The user may have disconnected since the sender looked up presence
+		if h.deliverToUser(env.UserID, env.Data) == 0 {
+			h.pushBacklog(context.Background(), env.UserID, env.Data)
+		}
+
+	default:
+		if articleID, ok := parseArticleChannel(channel); ok {
+			h.deliverToArticle(articleID, env.Data)
+		}
+	}
+}
+
+// routeToUser publishes a user message to the other instances holding the user's
+// connections and returns how many of them received it
+func (h *Hub) routeToUser(ctx context.Context, userID uuid.UUID, data []byte) int {
+	instances, err := h.redis.SMembers(ctx, presenceKey(userID)).Result()
+	if err != nil {
+		h.logger.Warn("Failed to read user presence", zap.String("userID", userID.String()), zap.Error(err))
+		return 0
+	}
+
+	delivered := 0
+	for _, instanceID := range instances {
+		if instanceID == h.instanceID {
+			continue
+		}
+
+		receivers, err := h.publish(ctx, instanceChannel(instanceID), userID, data)
+		if err != nil {
+			h.logger.Warn("Failed to publish user message",
+				zap.String("userID", userID.String()),
+				zap.String("instance", instanceID),
+				zap.Error(err))
+			continue
+		}
+		if receivers == 0 {
+			// Nobody listens on that instance channel anymore: stale presence entry
+			h.redis.SRem(ctx, presenceKey(userID), instanceID)
+			continue
+		}
+		delivered++
+	}
+
+	return delivered
+}
+
+// markPresent records that this instance holds connections of the user
+func (h *Hub) markPresent(ctx context.Context, userID uuid.UUID) {
+	pipe := h.redis.TxPipeline()
+	pipe.SAdd(ctx, presenceKey(userID), h.instanceID)
+	pipe.Expire(ctx, presenceKey(userID), presenceTTL)
+	pipe.SAdd(ctx, "online:users", userID.String())
+	if _, err := pipe.Exec(ctx); err != nil {
+		h.logger.Warn("Failed to record user presence", zap.String("userID", userID.String()), zap.Error(err))
+	}
+}
+
+// clearPresence removes this instance from the user's presence set
+func (h *Hub) clearPresence(ctx context.Context, userID uuid.UUID) {
+	if err := h.redis.SRem(ctx, presenceKey(userID), h.instanceID).Err(); err != nil {
+		h.logger.Warn("Failed to clear user presence", zap.String("userID", userID.String()), zap.Error(err))
+		return
+	}
+
+	// Only mark offline when no other instance still holds the user
+	if n, err := h.redis.SCard(ctx, presenceKey(userID)).Result(); err == nil && n == 0 {
+		h.redis.SRem(ctx, "online:users", userID.String())
+	}
+}
+
+// refreshPresence extends presence entries of locally connected users
+func (h *Hub) refreshPresence(ctx context.Context) {
+	h.mu.RLock()
+	users := make([]uuid.UUID, 0, len(h.userClients))
+	for userID := range h.userClients {
+		users = append(users, userID)
+	}
+	h.mu.RUnlock()
+
+	if len(users) == 0 {
+		return
+	}
+
+	pipe := h.redis.Pipeline()
+	for _, userID := range users {
+		pipe.SAdd(ctx, presenceKey(userID), h.instanceID)
+		pipe.Expire(ctx, presenceKey(userID), presenceTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		h.logger.Warn("Failed to refresh presence", zap.Error(err))
+	}
+}
+
+// pushBacklog keeps an undelivered user message for replay on reconnect
+func (h *Hub) pushBacklog(ctx context.Context, userID uuid.UUID, data []byte) {
+	pipe := h.redis.TxPipeline()
+	pipe.LPush(ctx, backlogKey(userID), data)
+	pipe.LTrim(ctx, backlogKey(userID), 0, backlogSize-1)
+	pipe.Expire(ctx, backlogKey(userID), backlogTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		h.logger.Warn("Failed to store undelivered message", zap.String("userID", userID.String()), zap.Error(err))
+	}
+}
+
+// replayBacklog sends stored messages to a newly connected client, oldest first
+func (h *Hub) replayBacklog(ctx context.Context, client *Client) {
+	pipe := h.redis.TxPipeline()
+	items := pipe.LRange(ctx, backlogKey(client.UserID), 0, -1)
+	pipe.Del(ctx, backlogKey(client.UserID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		h.logger.Warn("Failed to load undelivered messages", zap.String("userID", client.UserID.String()), zap.Error(err))
+		return
+	}
+
+	messages := items.Val()
+	for i := len(messages) - 1; i >= 0; i-- {
+		select {
+		case client.Send <- []byte(messages[i]):
+		default:
+			return
+		}
+	}
+}
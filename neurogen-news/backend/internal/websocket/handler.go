@@ -117,7 +117,14 @@ func (h *Handler) handleMessage(client *Client, data []byte) {
 		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
 			return
 		}
-		// TODO: Track article subscriptions
+		articleID, err := uuid.Parse(payload.ArticleID)
+		if err != nil {
+			return
+		}
+		if err := h.hub.SubscribeArticle(client, articleID); err != nil {
+			h.logger.Debug("Article subscription rejected",
+				zap.String("clientID", client.ID), zap.Error(err))
+		}
 
 	case "unsubscribe_article":
 		// Unsubscribe from article updates
@@ -127,7 +134,11 @@ func (h *Handler) handleMessage(client *Client, data []byte) {
 		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
 			return
 		}
-		// TODO: Remove article subscription
+		articleID, err := uuid.Parse(payload.ArticleID)
+		if err != nil {
+			return
+		}
+		h.hub.UnsubscribeArticle(client, articleID)
 
 	case "typing":
 		// User is typing (for comments)
@@ -140,11 +151,15 @@ func (h *Handler) handleMessage(client *Client, data []byte) {
 		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
 			return
 		}
-		// Broadcast typing indicator
-		h.hub.Broadcast(Message{
+		articleID, err := uuid.Parse(payload.ArticleID)
+		if err != nil {
+			return
+		}
+		// Send typing indicator to the article room
+		h.hub.BroadcastToArticle(articleID, Message{
 			Type: TypeTyping,
 			Payload: map[string]interface{}{
-				"articleId": payload.ArticleID,
+				"articleId": articleID,
 				"userId":    client.UserID.String(),
 			},
 		})
@@ -154,6 +169,29 @@ func (h *Handler) handleMessage(client *Client, data []byte) {
 	}
 }
 
+// RejectBanned refuses the upgrade for banned users; it runs after OptionalAuth
+func (h *Handler) RejectBanned() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals(string(middleware.UserIDKey)).(uuid.UUID)
+		if !ok {
+			return c.Next()
+		}
+
+		ban, err := h.authService.CheckBan(c.Context(), userID)
+		if err != nil {
+			h.logger.Warn("Failed to check ban", zap.String("user_id", userID.String()), zap.Error(err))
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "Failed to verify account",
+			})
+		}
+		if ban != nil {
+			return middleware.BannedResponse(c, ban)
+		}
+
+		return c.Next()
+	}
+}
+
 // UpgradeCheck middleware to check if request can be upgraded to WebSocket
 func UpgradeCheck() fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/gofiber/contrib/websocket"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
 	"github.com/neurogen-news/backend/internal/repository"
@@ -35,6 +37,9 @@ type Client struct {
 	UserID uuid.UUID
 	Conn   *websocket.Conn
 	Send   chan []byte
+
+	// Article rooms this connection joined (guarded by Hub.roomMu)
+	articles map[uuid.UUID]bool
 }
 
 // Hub manages all WebSocket connections
@@ -57,8 +62,18 @@ type Hub struct {
 	// Mutex for thread-safe operations
 	mu sync.RWMutex
 
+	// Article ID to subscribed clients mapping
+	articleClients map[uuid.UUID]map[*Client]bool
+
+	// Guards article rooms and their Redis subscriptions
+	roomMu sync.Mutex
+
 	// Redis for pub/sub
-	redis *repository.RedisClient
+	redis  *repository.RedisClient
+	pubsub *redis.PubSub
+
+	// Unique ID of this server instance, used to route and de-duplicate cluster messages
+	instanceID string
 
 	// Logger
 	logger *zap.Logger
@@ -67,26 +82,38 @@ type Hub struct {
 // NewHub creates a new WebSocket hub
 func NewHub(redis *repository.RedisClient, logger *zap.Logger) *Hub {
 	return &Hub{
-		clients:     make(map[*Client]bool),
-		userClients: make(map[uuid.UUID]map[*Client]bool),
-		broadcast:   make(chan []byte, 256),
-		register:    make(chan *Client),
-		unregister:  make(chan *Client),
-		redis:       redis,
-		logger:      logger,
+		clients:        make(map[*Client]bool),
+		userClients:    make(map[uuid.UUID]map[*Client]bool),
+		articleClients: make(map[uuid.UUID]map[*Client]bool),
+		broadcast:      make(chan []byte, 256),
+		register:       make(chan *Client),
+		unregister:     make(chan *Client),
+		redis:          redis,
+		instanceID:     uuid.New().String(),
+		logger:         logger,
 	}
 }
 
 // Run starts the hub's event loop
 func (h *Hub) Run(ctx context.Context) {
 	// Start Redis subscription for distributed messaging
+	h.roomMu.Lock()
+	h.pubsub = h.redis.Subscribe(ctx, broadcastChannel, instanceChannel(h.instanceID))
+	h.roomMu.Unlock()
+	h.resubscribeRooms(ctx)
 	go h.subscribeToRedis(ctx)
 
+	presenceTicker := time.NewTicker(presenceRefreshInterval)
+	defer presenceTicker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
 
+		case <-presenceTicker.C:
+			h.refreshPresence(ctx)
+
 		case client := <-h.register:
 			h.registerClient(client)
 
@@ -101,7 +128,6 @@ func (h *Hub) Run(ctx context.Context) {
 
 func (h *Hub) registerClient(client *Client) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
 
 	h.clients[client] = true
 
@@ -111,17 +137,26 @@ func (h *Hub) registerClient(client *Client) {
 			h.userClients[client.UserID] = make(map[*Client]bool)
 		}
 		h.userClients[client.UserID][client] = true
-
-		// Update online status in Redis
-		h.redis.SAdd(context.Background(), "online:users", client.UserID.String())
 	}
 
 	h.logger.Debug("Client registered",
 		zap.String("clientID", client.ID),
 		zap.String("userID", client.UserID.String()))
 
+	h.mu.Unlock()
+
+	if client.UserID != uuid.Nil {
+		// Update presence and online status in Redis
+		h.markPresent(context.Background(), client.UserID)
+
+		// Deliver messages that arrived while the user was offline
+		h.replayBacklog(context.Background(), client)
+	}
+
 	// Broadcast online count update
+	h.mu.RLock()
 	h.broadcastOnlineCount()
+	h.mu.RUnlock()
 }
 
 func (h *Hub) unregisterClient(client *Client) {
@@ -129,6 +164,9 @@ func (h *Hub) unregisterClient(client *Client) {
 	defer h.mu.Unlock()
 
 	if _, ok := h.clients[client]; ok {
+		// Leave rooms first so room fan-out never writes to a closed channel
+		h.leaveAllRooms(client)
+
 		delete(h.clients, client)
 		close(client.Send)
 
@@ -138,8 +176,8 @@ func (h *Hub) unregisterClient(client *Client) {
 				delete(userClients, client)
 				if len(userClients) == 0 {
 					delete(h.userClients, client.UserID)
-					// Update online status in Redis
-					h.redis.SRem(context.Background(), "online:users", client.UserID.String())
+					// Update presence and online status in Redis
+					h.clearPresence(context.Background(), client.UserID)
 				}
 			}
 		}
@@ -161,12 +199,28 @@ func (h *Hub) broadcastMessage(message []byte) {
 		select {
 		case client.Send <- message:
 		default:
-			close(client.Send)
-			delete(h.clients, client)
+			// Slow client, drop the message; the connection is cleaned up on unregister
 		}
 	}
 }
 
+// deliverToUser sends a message to local connections of a user and returns how many got it
+func (h *Hub) deliverToUser(userID uuid.UUID, data []byte) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	delivered := 0
+	for client := range h.userClients[userID] {
+		select {
+		case client.Send <- data:
+			delivered++
+		default:
+		}
+	}
+
+	return delivered
+}
+
 // Register adds a client to the hub
 func (h *Hub) Register(client *Client) {
 	h.register <- client
@@ -185,22 +239,15 @@ func (h *Hub) SendToUser(userID uuid.UUID, msg Message) {
 		return
 	}
 
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	ctx := context.Background()
 
-	if clients, ok := h.userClients[userID]; ok {
-		for client := range clients {
-			select {
-			case client.Send <- data:
-			default:
-				close(client.Send)
-				delete(h.clients, client)
-			}
-		}
-	}
+	// Local connections first, then other instances holding the user
+	delivered := h.deliverToUser(userID, data)
+	delivered += h.routeToUser(ctx, userID, data)
 
-	// Also publish to Redis for distributed messaging
-	h.redis.Publish(context.Background(), "ws:user:"+userID.String(), data)
+	if delivered == 0 {
+		h.pushBacklog(ctx, userID, data)
+	}
 }
 
 // Broadcast sends a message to all connected clients
@@ -214,7 +261,9 @@ func (h *Hub) Broadcast(msg Message) {
 	h.broadcast <- data
 
 	// Also publish to Redis for distributed messaging
-	h.redis.Publish(context.Background(), "ws:broadcast", data)
+	if _, err := h.publish(context.Background(), broadcastChannel, uuid.Nil, data); err != nil {
+		h.logger.Warn("Failed to publish broadcast", zap.Error(err))
+	}
 }
 
 // SendNotification sends a notification to a user
@@ -225,6 +274,38 @@ func (h *Hub) SendNotification(userID uuid.UUID, notification interface{}) {
 	})
 }
 
+// DisconnectUser closes every connection of a user, on this instance and on
+// the others, e.g. once the user is banned
+func (h *Hub) DisconnectUser(userID uuid.UUID) {
+	h.disconnectLocal(userID)
+
+	payload, err := json.Marshal(envelope{Origin: h.instanceID, UserID: userID, Disconnect: true})
+	if err != nil {
+		h.logger.Error("Failed to marshal message", zap.Error(err))
+		return
+	}
+	if err := h.redis.Publish(context.Background(), broadcastChannel, payload).Err(); err != nil {
+		h.logger.Warn("Failed to publish disconnect", zap.String("userID", userID.String()), zap.Error(err))
+	}
+}
+
+// disconnectLocal closes the local connections of a user; their read pumps
+// then unregister them as for any dropped connection
+func (h *Hub) disconnectLocal(userID uuid.UUID) {
+	h.mu.RLock()
+	conns := make([]*websocket.Conn, 0, len(h.userClients[userID]))
+	for client := range h.userClients[userID] {
+		conns = append(conns, client.Conn)
+	}
+	h.mu.RUnlock()
+
+	deadline := time.Now().Add(time.Second)
+	for _, conn := range conns {
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "banned"), deadline)
+		_ = conn.Close()
+	}
+}
+
 // SendAchievementUnlock notifies user about new achievement
 func (h *Hub) SendAchievementUnlock(userID uuid.UUID, achievement interface{}) {
 	h.SendToUser(userID, Message{
@@ -233,9 +314,9 @@ func (h *Hub) SendAchievementUnlock(userID uuid.UUID, achievement interface{}) {
 	})
 }
 
-// BroadcastNewComment notifies about new comment on article
+// BroadcastNewComment notifies article subscribers about a new comment
 func (h *Hub) BroadcastNewComment(articleID uuid.UUID, comment interface{}) {
-	h.Broadcast(Message{
+	h.BroadcastToArticle(articleID, Message{
 		Type: TypeNewComment,
 		Payload: map[string]interface{}{
 			"articleId": articleID,
@@ -244,9 +325,9 @@ func (h *Hub) BroadcastNewComment(articleID uuid.UUID, comment interface{}) {
 	})
 }
 
-// BroadcastReaction notifies about reaction on article
+// BroadcastReaction notifies article subscribers about new reaction counts
 func (h *Hub) BroadcastReaction(articleID uuid.UUID, reactions interface{}) {
-	h.Broadcast(Message{
+	h.BroadcastToArticle(articleID, Message{
 		Type: TypeReaction,
 		Payload: map[string]interface{}{
 			"articleId": articleID,
@@ -283,33 +364,27 @@ func (h *Hub) GetOnlineCount() int64 {
 	return count
 }
 
-// IsUserOnline checks if a user is online
+// IsUserOnline checks if a user is connected to any instance
 func (h *Hub) IsUserOnline(userID uuid.UUID) bool {
-	result, _ := h.redis.SIsMember(context.Background(), "online:users", userID.String()).Result()
-	return result
+	result, _ := h.redis.Exists(context.Background(), presenceKey(userID)).Result()
+	return result > 0
 }
 
 // subscribeToRedis listens for messages from other server instances
 func (h *Hub) subscribeToRedis(ctx context.Context) {
-	pubsub := h.redis.Subscribe(ctx, "ws:broadcast")
-	defer pubsub.Close()
+	defer h.pubsub.Close()
 
-	ch := pubsub.Channel()
+	ch := h.pubsub.Channel()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case msg := <-ch:
-			// Broadcast to local clients
-			h.mu.RLock()
-			for client := range h.clients {
-				select {
-				case client.Send <- []byte(msg.Payload):
-				default:
-				}
+		case msg, ok := <-ch:
+			if !ok {
+				return
 			}
-			h.mu.RUnlock()
+			h.handleRedisMessage(msg.Channel, msg.Payload)
 		}
 	}
 }
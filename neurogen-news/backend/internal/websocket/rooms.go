@@ -0,0 +1,163 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// Redis channel prefix for article-scoped events
+	articleChannelPrefix = "ws:article:"
+
+	// Upper bound of article rooms a single connection may join
+	maxArticleSubscriptions = 20
+)
+
+var ErrTooManySubscriptions = errors.New("too many article subscriptions")
+
+func articleChannel(articleID uuid.UUID) string {
+	return articleChannelPrefix + articleID.String()
+}
+
+// SubscribeArticle adds a client to an article room
+func (h *Hub) SubscribeArticle(client *Client, articleID uuid.UUID) error {
+	h.roomMu.Lock()
+	defer h.roomMu.Unlock()
+
+	if client.articles == nil {
+		client.articles = make(map[uuid.UUID]bool)
+	}
+	if client.articles[articleID] {
+		return nil
+	}
+	if len(client.articles) >= maxArticleSubscriptions {
+		return ErrTooManySubscriptions
+	}
+
+	room, ok := h.articleClients[articleID]
+	if !ok {
+		room = make(map[*Client]bool)
+		h.articleClients[articleID] = room
+
+		// First local subscriber: start receiving the room's events from Redis
+		if h.pubsub != nil {
+			if err := h.pubsub.Subscribe(context.Background(), articleChannel(articleID)); err != nil {
+				h.logger.Error("Failed to subscribe to article channel",
+					zap.String("articleID", articleID.String()), zap.Error(err))
+			}
+		}
+	}
+
+	room[client] = true
+	client.articles[articleID] = true
+
+	return nil
+}
+
+// UnsubscribeArticle removes a client from an article room
+func (h *Hub) UnsubscribeArticle(client *Client, articleID uuid.UUID) {
+	h.roomMu.Lock()
+	defer h.roomMu.Unlock()
+
+	h.leaveRoom(client, articleID)
+}
+
+// leaveAllRooms removes a disconnecting client from every room it joined
+func (h *Hub) leaveAllRooms(client *Client) {
+	h.roomMu.Lock()
+	defer h.roomMu.Unlock()
+
+	for articleID := range client.articles {
+		h.leaveRoom(client, articleID)
+	}
+}
+
+// leaveRoom must be called with roomMu held
+func (h *Hub) leaveRoom(client *Client, articleID uuid.UUID) {
+	delete(client.articles, articleID)
+
+	room, ok := h.articleClients[articleID]
+	if !ok {
+		return
+	}
+
+	delete(room, client)
+	if len(room) > 0 {
+		return
+	}
+
+	// Last local subscriber left: stop receiving the room's events
+	delete(h.articleClients, articleID)
+	if h.pubsub != nil {
+		if err := h.pubsub.Unsubscribe(context.Background(), articleChannel(articleID)); err != nil {
+			h.logger.Error("Failed to unsubscribe from article channel",
+				zap.String("articleID", articleID.String()), zap.Error(err))
+		}
+	}
+}
+
+// BroadcastToArticle sends a message to subscribers of an article on all instances
+func (h *Hub) BroadcastToArticle(articleID uuid.UUID, msg Message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		h.logger.Error("Failed to marshal message", zap.Error(err))
+		return
+	}
+
+	h.deliverToArticle(articleID, data)
+
+	// Other instances deliver to their own subscribers; our echo is dropped
+	if _, err := h.publish(context.Background(), articleChannel(articleID), uuid.Nil, data); err != nil {
+		h.logger.Warn("Failed to publish article message",
+			zap.String("articleID", articleID.String()), zap.Error(err))
+	}
+}
+
+// deliverToArticle sends a message to local subscribers of an article
+func (h *Hub) deliverToArticle(articleID uuid.UUID, data []byte) {
+	h.roomMu.Lock()
+	defer h.roomMu.Unlock()
+
+	for client := range h.articleClients[articleID] {
+		select {
+		case client.Send <- data:
+		default:
+			// Slow client, drop the event rather than block the room
+		}
+	}
+}
+
+// resubscribeRooms restores Redis subscriptions for rooms joined before Run
+func (h *Hub) resubscribeRooms(ctx context.Context) {
+	h.roomMu.Lock()
+	defer h.roomMu.Unlock()
+
+	if len(h.articleClients) == 0 {
+		return
+	}
+
+	channels := make([]string, 0, len(h.articleClients))
+	for articleID := range h.articleClients {
+		channels = append(channels, articleChannel(articleID))
+	}
+	if err := h.pubsub.Subscribe(ctx, channels...); err != nil {
+		h.logger.Error("Failed to subscribe to article channels", zap.Error(err))
+	}
+}
+
+// parseArticleChannel extracts the article ID from a Redis channel name
+func parseArticleChannel(channel string) (uuid.UUID, bool) {
+	if !strings.HasPrefix(channel, articleChannelPrefix) {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(strings.TrimPrefix(channel, articleChannelPrefix))
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,26 @@
+-- Migration: Transactional outbox
+-- Domain events written together with the data they describe
+
+-- ============================================
+-- Outbox events
+-- ============================================
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_type VARCHAR(50) NOT NULL, -- article.upserted, article.deleted, user.upserted, tag.upserted
+    aggregate_id UUID NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    available_at TIMESTAMPTZ NOT NULL DEFAULT NOW(), -- next attempt (backoff)
+    locked_until TIMESTAMPTZ, -- claimed by a dispatcher until this time
+    processed_at TIMESTAMPTZ,
+    dead_at TIMESTAMPTZ, -- gave up after max attempts
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- ============================================
+-- Indexes
+-- ============================================
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(available_at, created_at)
+    WHERE processed_at IS NULL AND dead_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_dead ON outbox_events(dead_at) WHERE dead_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_processed ON outbox_events(processed_at) WHERE processed_at IS NOT NULL;
@@ -0,0 +1,123 @@
+-- Migration: Full-text search
+-- Weighted tsvectors used when Meilisearch is not configured
+
+-- ============================================
+-- Articles
+-- ============================================
+-- Weights: A = title, B = lead, C = content, D = tags.
+-- Text is indexed with both russian and english stemming, tags as-is.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION build_article_search_vector(p_id UUID, p_title TEXT, p_lead TEXT, p_content TEXT)
+RETURNS tsvector AS $$
+    SELECT
+        setweight(to_tsvector('russian', COALESCE(p_title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(p_title, '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(p_lead, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(p_lead, '')), 'B') ||
+        setweight(to_tsvector('russian', COALESCE(p_content, '')), 'C') ||
+        setweight(to_tsvector('english', COALESCE(p_content, '')), 'C') ||
+        setweight(to_tsvector('simple', COALESCE((
+            SELECT string_agg(t.name, ' ')
+            FROM article_tags at
+            JOIN tags t ON t.id = at.tag_id
+            WHERE at.article_id = p_id
+        ), '')), 'D');
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION update_article_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector = build_article_search_vector(NEW.id, NEW.title, NEW.lead, NEW.content);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_update_article_search_vector ON articles;
+CREATE TRIGGER trigger_update_article_search_vector
+BEFORE INSERT OR UPDATE OF title, lead, content ON articles
+FOR EACH ROW EXECUTE FUNCTION update_article_search_vector();
+
+-- Tags live in article_tags, so tag changes refresh the owning article
+CREATE OR REPLACE FUNCTION refresh_article_search_vector_on_tags()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_article_id UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        v_article_id = OLD.article_id;
+    ELSE
+        v_article_id = NEW.article_id;
+    END IF;
+
+    UPDATE articles
+    SET search_vector = build_article_search_vector(id, title, lead, content)
+    WHERE id = v_article_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_refresh_article_search_vector_on_tags ON article_tags;
+CREATE TRIGGER trigger_refresh_article_search_vector_on_tags
+AFTER INSERT OR DELETE ON article_tags
+FOR EACH ROW EXECUTE FUNCTION refresh_article_search_vector_on_tags();
+
+CREATE OR REPLACE FUNCTION refresh_article_search_vector_on_tag_rename()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE articles a
+    SET search_vector = build_article_search_vector(a.id, a.title, a.lead, a.content)
+    FROM article_tags at
+    WHERE at.article_id = a.id AND at.tag_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_refresh_article_search_vector_on_tag_rename ON tags;
+CREATE TRIGGER trigger_refresh_article_search_vector_on_tag_rename
+AFTER UPDATE OF name ON tags
+FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+EXECUTE FUNCTION refresh_article_search_vector_on_tag_rename();
+
+-- Backfill existing rows without touching updated_at
+ALTER TABLE articles DISABLE TRIGGER update_articles_updated_at;
+UPDATE articles SET search_vector = build_article_search_vector(id, title, lead, content);
+ALTER TABLE articles ENABLE TRIGGER update_articles_updated_at;
+
+-- Replaces the expression index on title and lead
+DROP INDEX IF EXISTS idx_articles_search;
+CREATE INDEX IF NOT EXISTS idx_articles_search_vector ON articles USING gin(search_vector);
+
+-- ============================================
+-- Users
+-- ============================================
+-- Weights: A = username and display name, B = bio
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION build_user_search_vector(p_username TEXT, p_display_name TEXT, p_bio TEXT)
+RETURNS tsvector AS $$
+    SELECT
+        setweight(to_tsvector('simple', COALESCE(p_username, '') || ' ' || COALESCE(p_display_name, '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(p_bio, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(p_bio, '')), 'B');
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION update_user_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector = build_user_search_vector(NEW.username, NEW.display_name, NEW.bio);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_update_user_search_vector ON users;
+CREATE TRIGGER trigger_update_user_search_vector
+BEFORE INSERT OR UPDATE OF username, display_name, bio ON users
+FOR EACH ROW EXECUTE FUNCTION update_user_search_vector();
+
+-- Backfill existing rows without touching updated_at
+ALTER TABLE users DISABLE TRIGGER update_users_updated_at;
+UPDATE users SET search_vector = build_user_search_vector(username, display_name, bio);
+ALTER TABLE users ENABLE TRIGGER update_users_updated_at;
+
+CREATE INDEX IF NOT EXISTS idx_users_search_vector ON users USING gin(search_vector);
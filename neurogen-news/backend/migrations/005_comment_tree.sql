@@ -0,0 +1,10 @@
+-- Migration: Comment tree
+-- Keyset indexes for paging root comments and walking replies
+
+-- ============================================
+-- Indexes
+-- ============================================
+CREATE INDEX IF NOT EXISTS idx_comments_article_roots ON comments(article_id, created_at DESC, id DESC)
+    WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_parent_created ON comments(parent_id, created_at, id)
+    WHERE parent_id IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Migration: Dashboard statistics
+-- Indexes behind the daily series of the admin dashboard
+
+-- ============================================
+-- Indexes
+-- ============================================
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
+CREATE INDEX IF NOT EXISTS idx_reports_created_at ON reports(created_at);
+CREATE INDEX IF NOT EXISTS idx_reports_resolved_at ON reports(resolved_at) WHERE resolved_at IS NOT NULL;
@@ -0,0 +1,28 @@
+-- Migration: Reports
+-- Moderator queue: assignment, resolutions and hidden comments
+
+-- ============================================
+-- Reports table extensions
+-- ============================================
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS assigned_to UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ;
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS resolution VARCHAR(20); -- delete, hide, warn, ban
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS resolution_note TEXT;
+
+-- One open report per reporter and target
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_open_unique ON reports(reporter_id, target_type, target_id)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_reports_queue ON reports(status, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_reports_assigned_to ON reports(assigned_to) WHERE assigned_to IS NOT NULL;
+
+-- ============================================
+-- Hidden comments
+-- ============================================
+-- A hidden comment shows a placeholder; the original text is kept for moderators
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS is_hidden BOOLEAN DEFAULT false;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS hidden_content TEXT;
+
+-- ============================================
+-- Moderation actions
+-- ============================================
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_moderator ON moderation_actions(moderator_id, created_at DESC);
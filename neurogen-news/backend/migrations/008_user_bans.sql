@@ -0,0 +1,16 @@
+-- Migration: User bans
+-- Ban history in user_bans; users.is_banned/banned_until mirror the active ban
+
+-- ============================================
+-- User bans table extensions
+-- ============================================
+ALTER TABLE user_bans ALTER COLUMN expires_at TYPE TIMESTAMPTZ;
+ALTER TABLE user_bans ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+ALTER TABLE user_bans ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+ALTER TABLE user_bans ADD COLUMN IF NOT EXISTS revoked_by UUID REFERENCES users(id);
+
+-- ============================================
+-- Indexes
+-- ============================================
+CREATE INDEX IF NOT EXISTS idx_user_bans_active ON user_bans(user_id, created_at DESC) WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_ban_expiry ON users(banned_until) WHERE is_banned = true;
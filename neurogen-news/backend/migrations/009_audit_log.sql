@@ -0,0 +1,15 @@
+-- Migration: Audit log
+-- Before/after snapshots for moderation_actions and indexes for filtering
+
+-- ============================================
+-- Moderation actions extensions
+-- ============================================
+ALTER TABLE moderation_actions ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+ALTER TABLE moderation_actions ADD COLUMN IF NOT EXISTS snapshot_before JSONB;
+ALTER TABLE moderation_actions ADD COLUMN IF NOT EXISTS snapshot_after JSONB;
+
+-- ============================================
+-- Indexes
+-- ============================================
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_created ON moderation_actions(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_action ON moderation_actions(action, created_at DESC);
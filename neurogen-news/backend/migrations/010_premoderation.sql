@@ -0,0 +1,22 @@
+-- Migration: Pre-moderation
+-- Articles from new or low-karma authors wait for review with status 'pending'
+
+-- ============================================
+-- Review state
+-- ============================================
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMPTZ;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS review_decision VARCHAR(20);
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS review_note TEXT;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+-- ============================================
+-- Indexes
+-- ============================================
+-- Review queue, oldest submissions first
+CREATE INDEX IF NOT EXISTS idx_articles_pending ON articles(submitted_at) WHERE status = 'pending';
+
+-- ============================================
+-- Notifications
+-- ============================================
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'article_review';
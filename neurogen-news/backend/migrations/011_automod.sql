@@ -0,0 +1,36 @@
+-- Migration: Automoderation
+-- Admin-managed rules checked when comments and articles are written
+
+-- ============================================
+-- Rules
+-- ============================================
+CREATE TABLE IF NOT EXISTS automod_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL,   -- stopwords, regex, links, caps, duplicate, new_account
+    target VARCHAR(20) NOT NULL DEFAULT 'all', -- comment, article, all
+    action VARCHAR(20) NOT NULL, -- allow, hold, shadow_hide, reject
+    config JSONB NOT NULL DEFAULT '{}',
+    priority INTEGER NOT NULL DEFAULT 100,
+    is_enabled BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_automod_rules_priority ON automod_rules(priority, created_at);
+
+-- ============================================
+-- Held and shadow-hidden content
+-- ============================================
+-- NULL is visible to everyone; 'held' and 'shadow' only to the author
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS automod_state VARCHAR(20);
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS automod_state VARCHAR(20);
+
+-- ============================================
+-- System account
+-- ============================================
+-- Files automatic reports and signs automatic moderation actions; it has no password
+INSERT INTO users (id, username, email, display_name, role, is_verified)
+VALUES ('00000000-0000-0000-0000-000000000001', 'automod', 'automod@system.local', 'Автомодератор', 'MODERATOR', true)
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,27 @@
+-- Migration: Platform settings
+-- Runtime-tunable limits with change history; unset keys use built-in defaults
+
+-- ============================================
+-- Settings
+-- ============================================
+CREATE TABLE IF NOT EXISTS platform_settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value JSONB NOT NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- ============================================
+-- History
+-- ============================================
+CREATE TABLE IF NOT EXISTS platform_settings_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    key VARCHAR(100) NOT NULL,
+    old_value JSONB,
+    new_value JSONB NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_settings_history_changed ON platform_settings_history(changed_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_settings_history_key ON platform_settings_history(key, changed_at DESC);
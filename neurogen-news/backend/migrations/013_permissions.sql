@@ -0,0 +1,16 @@
+-- Migration: Permissions
+-- Role capabilities live in code; this stores per-category moderator grants
+
+-- ============================================
+-- Category moderators
+-- ============================================
+-- A grant lets a user moderate articles and comments of one category
+CREATE TABLE IF NOT EXISTS category_moderators (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    granted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (user_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_moderators_category ON category_moderators(category_id);
@@ -0,0 +1,22 @@
+-- Migration: Direct uploads
+-- Presigned uploads that go straight to object storage
+
+-- ============================================
+-- Direct uploads
+-- ============================================
+-- A row is created when the URL is signed and completed once the object is
+-- verified; rows still pending after expires_at are removed with their object
+CREATE TABLE IF NOT EXISTS direct_uploads (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key VARCHAR(500) NOT NULL UNIQUE,
+    type VARCHAR(20) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    expires_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_direct_uploads_user ON direct_uploads(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_direct_uploads_pending ON direct_uploads(expires_at) WHERE completed_at IS NULL;
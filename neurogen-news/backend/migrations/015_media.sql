@@ -0,0 +1,33 @@
+-- Migration: Media library
+-- Uploaded images and where they are used
+
+-- ============================================
+-- Media
+-- ============================================
+-- One row per upload; key is the original, variants share its prefix
+CREATE TABLE IF NOT EXISTS media (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key VARCHAR(500) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    type VARCHAR(20) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_user ON media(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_media_created ON media(created_at);
+
+-- ============================================
+-- Media references
+-- ============================================
+-- Rebuilt by the media collector from article, draft and profile content
+CREATE TABLE IF NOT EXISTS media_references (
+    media_id UUID NOT NULL REFERENCES media(id) ON DELETE CASCADE,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('article', 'draft', 'avatar', 'profile_cover')),
+    target_id UUID NOT NULL,
+    PRIMARY KEY (media_id, target_type, target_id)
+);
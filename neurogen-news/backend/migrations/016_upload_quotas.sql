@@ -0,0 +1,51 @@
+-- Migration: Upload quotas
+-- Storage usage per user, kept current by triggers on media, and admin overrides
+
+-- ============================================
+-- Upload usage
+-- ============================================
+-- uploads_today counts uploads made on usage_date and restarts on the next upload
+-- of a new day; max_bytes and max_daily_uploads override the role quota when set
+CREATE TABLE IF NOT EXISTS upload_usage (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    used_bytes BIGINT NOT NULL DEFAULT 0,
+    file_count INTEGER NOT NULL DEFAULT 0,
+    uploads_today INTEGER NOT NULL DEFAULT 0,
+    usage_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    max_bytes BIGINT CHECK (max_bytes >= 0),
+    max_daily_uploads INTEGER CHECK (max_daily_uploads >= 0),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION update_upload_usage()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO upload_usage (user_id, used_bytes, file_count, uploads_today, usage_date)
+        VALUES (NEW.user_id, NEW.size, 1, 1, CURRENT_DATE)
+        ON CONFLICT (user_id) DO UPDATE SET
+            used_bytes = upload_usage.used_bytes + EXCLUDED.used_bytes,
+            file_count = upload_usage.file_count + 1,
+            uploads_today = CASE WHEN upload_usage.usage_date = CURRENT_DATE
+                THEN upload_usage.uploads_today + 1 ELSE 1 END,
+            usage_date = CURRENT_DATE,
+            updated_at = NOW();
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE upload_usage SET
+            used_bytes = GREATEST(used_bytes - OLD.size, 0),
+            file_count = GREATEST(file_count - 1, 0),
+            updated_at = NOW()
+        WHERE user_id = OLD.user_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS update_upload_usage ON media;
+CREATE TRIGGER update_upload_usage AFTER INSERT OR DELETE ON media
+    FOR EACH ROW EXECUTE FUNCTION update_upload_usage();
+
+-- Existing media count from the start
+INSERT INTO upload_usage (user_id, used_bytes, file_count)
+SELECT user_id, SUM(size), COUNT(*) FROM media GROUP BY user_id
+ON CONFLICT (user_id) DO NOTHING;
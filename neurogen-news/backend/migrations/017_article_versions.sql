@@ -0,0 +1,24 @@
+-- Migration: Article version history
+-- Every edit of the title or content is stored in article_versions with the editor
+
+-- ============================================
+-- Versions
+-- ============================================
+-- A version is the article as it was after an edit; restored_from points at the
+-- version whose text was brought back
+ALTER TABLE article_versions ADD COLUMN IF NOT EXISTS restored_from INT;
+ALTER TABLE article_versions ALTER COLUMN version SET NOT NULL;
+ALTER TABLE article_versions ALTER COLUMN created_at SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_versions_article_version
+    ON article_versions(article_id, version);
+DROP INDEX IF EXISTS idx_article_versions_article_id;
+
+-- ============================================
+-- Backfill
+-- ============================================
+-- Existing articles start their history with the current text, credited to the author
+INSERT INTO article_versions (article_id, title, content, edited_by, version, created_at)
+SELECT a.id, a.title, a.content, a.author_id, 1, COALESCE(a.updated_at, a.created_at, NOW())
+FROM articles a
+WHERE NOT EXISTS (SELECT 1 FROM article_versions v WHERE v.article_id = a.id);
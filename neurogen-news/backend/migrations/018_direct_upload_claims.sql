@@ -0,0 +1,10 @@
+-- Migration: Direct upload claims
+-- Completing an upload claims it first, so concurrent completions cannot both
+-- process the object and the expirer leaves uploads being processed alone
+
+-- ============================================
+-- Claims
+-- ============================================
+-- claimed_at is set while a completion verifies and processes the object and
+-- cleared again when it fails in a way the client can retry
+ALTER TABLE direct_uploads ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMPTZ;
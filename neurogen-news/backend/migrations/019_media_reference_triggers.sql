@@ -0,0 +1,111 @@
+-- Migration: Incremental media references
+-- media_references follows content as it is saved, so neither the media
+-- collector nor deleting an image has to scan all content
+
+-- ============================================
+-- Keys
+-- ============================================
+-- The base of a key is the key without its extension; variants add a suffix
+-- to it (keyBase in the upload service)
+CREATE OR REPLACE FUNCTION media_key_base(p_key TEXT)
+RETURNS TEXT AS $$
+    SELECT regexp_replace(p_key, '\.[^./]*$', '');
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE INDEX IF NOT EXISTS idx_media_base ON media(media_key_base(key));
+
+-- Bases of the uploads a text links to, whatever host serves them. Uploads
+-- are keyed type/user/year/month/uuid (uploadBase in the upload service).
+CREATE OR REPLACE FUNCTION media_bases(p_text TEXT)
+RETURNS SETOF TEXT AS $$
+    SELECT DISTINCT m.match[1]
+    FROM regexp_matches(
+        COALESCE(p_text, ''),
+        '((?:article|avatar|cover)/[0-9a-f-]{36}/[0-9]{4}/[0-9]{2}/[0-9a-f-]{36})',
+        'g'
+    ) AS m(match);
+$$ LANGUAGE sql IMMUTABLE;
+
+-- ============================================
+-- References
+-- ============================================
+CREATE INDEX IF NOT EXISTS idx_media_references_target ON media_references(target_type, target_id);
+
+CREATE OR REPLACE FUNCTION replace_media_references(p_type TEXT, p_id UUID, p_text TEXT)
+RETURNS VOID AS $$
+BEGIN
+    DELETE FROM media_references WHERE target_type = p_type AND target_id = p_id;
+
+    INSERT INTO media_references (media_id, target_type, target_id)
+    SELECT m.id, p_type, p_id
+    FROM media m
+    WHERE media_key_base(m.key) IN (SELECT media_bases(p_text))
+    ON CONFLICT DO NOTHING;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION update_article_media_references()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM media_references WHERE target_type = 'article' AND target_id = OLD.id;
+        RETURN NULL;
+    END IF;
+
+    PERFORM replace_media_references('article', NEW.id, COALESCE(NEW.content, '') || ' ' || COALESCE(NEW.cover_image_url, ''));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_update_article_media_references ON articles;
+CREATE TRIGGER trigger_update_article_media_references
+AFTER INSERT OR UPDATE OF content, cover_image_url OR DELETE ON articles
+FOR EACH ROW EXECUTE FUNCTION update_article_media_references();
+
+CREATE OR REPLACE FUNCTION update_draft_media_references()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM media_references WHERE target_type = 'draft' AND target_id = OLD.id;
+        RETURN NULL;
+    END IF;
+
+    PERFORM replace_media_references('draft', NEW.id, COALESCE(NEW.content, '') || ' ' || COALESCE(NEW.cover_image_url, ''));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_update_draft_media_references ON drafts;
+CREATE TRIGGER trigger_update_draft_media_references
+AFTER INSERT OR UPDATE OF content, cover_image_url OR DELETE ON drafts
+FOR EACH ROW EXECUTE FUNCTION update_draft_media_references();
+
+CREATE OR REPLACE FUNCTION update_user_media_references()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM media_references WHERE target_type IN ('avatar', 'profile_cover') AND target_id = OLD.id;
+        RETURN NULL;
+    END IF;
+
+    PERFORM replace_media_references('avatar', NEW.id, NEW.avatar_url);
+    PERFORM replace_media_references('profile_cover', NEW.id, NEW.cover_url);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_update_user_media_references ON users;
+CREATE TRIGGER trigger_update_user_media_references
+AFTER INSERT OR UPDATE OF avatar_url, cover_url OR DELETE ON users
+FOR EACH ROW EXECUTE FUNCTION update_user_media_references();
+
+-- ============================================
+-- Backfill
+-- ============================================
+-- Replaces what the last collector run found
+DELETE FROM media_references;
+
+SELECT replace_media_references('article', id, COALESCE(content, '') || ' ' || COALESCE(cover_image_url, '')) FROM articles;
+SELECT replace_media_references('draft', id, COALESCE(content, '') || ' ' || COALESCE(cover_image_url, '')) FROM drafts;
+SELECT replace_media_references('avatar', id, avatar_url) FROM users WHERE avatar_url IS NOT NULL;
+SELECT replace_media_references('profile_cover', id, cover_url) FROM users WHERE cover_url IS NOT NULL;
@@ -0,0 +1,50 @@
+-- Migration: Upload reservations
+-- Uploads reserve their size before they are processed, so concurrent uploads
+-- cannot together exceed the quota; days are counted in UTC
+
+-- ============================================
+-- Reservations
+-- ============================================
+-- A reservation counts against the quota until the upload is stored or fails;
+-- reservations left behind by a crash stop counting at expires_at
+CREATE TABLE IF NOT EXISTS upload_reservations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    size BIGINT NOT NULL CHECK (size > 0),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_upload_reservations_user ON upload_reservations(user_id, expires_at);
+
+-- ============================================
+-- Upload usage
+-- ============================================
+-- usage_date is a UTC day, like the reset time the API reports
+ALTER TABLE upload_usage ALTER COLUMN usage_date SET DEFAULT (NOW() AT TIME ZONE 'UTC')::date;
+
+CREATE OR REPLACE FUNCTION update_upload_usage()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_today DATE := (NOW() AT TIME ZONE 'UTC')::date;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO upload_usage (user_id, used_bytes, file_count, uploads_today, usage_date)
+        VALUES (NEW.user_id, NEW.size, 1, 1, v_today)
+        ON CONFLICT (user_id) DO UPDATE SET
+            used_bytes = upload_usage.used_bytes + EXCLUDED.used_bytes,
+            file_count = upload_usage.file_count + 1,
+            uploads_today = CASE WHEN upload_usage.usage_date = v_today
+                THEN upload_usage.uploads_today + 1 ELSE 1 END,
+            usage_date = v_today,
+            updated_at = NOW();
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE upload_usage SET
+            used_bytes = GREATEST(used_bytes - OLD.size, 0),
+            file_count = GREATEST(file_count - 1, 0),
+            updated_at = NOW()
+        WHERE user_id = OLD.user_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
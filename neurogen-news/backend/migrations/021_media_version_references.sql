@@ -0,0 +1,26 @@
+-- Migration: Media references from article history
+-- Old versions can be restored, so the images they show stay in use
+
+ALTER TABLE media_references DROP CONSTRAINT IF EXISTS media_references_target_type_check;
+ALTER TABLE media_references ADD CONSTRAINT media_references_target_type_check
+    CHECK (target_type IN ('article', 'article_version', 'draft', 'avatar', 'profile_cover'));
+
+CREATE OR REPLACE FUNCTION update_article_version_media_references()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM media_references WHERE target_type = 'article_version' AND target_id = OLD.id;
+        RETURN NULL;
+    END IF;
+
+    PERFORM replace_media_references('article_version', NEW.id, NEW.content);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_update_article_version_media_references ON article_versions;
+CREATE TRIGGER trigger_update_article_version_media_references
+AFTER INSERT OR UPDATE OF content OR DELETE ON article_versions
+FOR EACH ROW EXECUTE FUNCTION update_article_version_media_references();
+
+SELECT replace_media_references('article_version', id, content) FROM article_versions;